@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+)
+
+var (
+	// Append-to-file flag, shared by upload and check
+	appendToFile string
+)
+
+// appendSnippet appends a rendered snippet to path, preceded by the
+// configured separator. The file is created if needed and locked while
+// writing so concurrent invocations don't interleave.
+func appendSnippet(cfg *config.Config, path, snippet string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := lockFile(f); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	defer unlockFile(f)
+
+	separator := strings.ReplaceAll(cfg.AppendSeparator(), "%timestamp%", time.Now().Format("2006-01-02 15:04:05"))
+
+	// Don't start a new file with blank lines
+	if info, err := f.Stat(); err == nil && info.Size() == 0 {
+		separator = strings.TrimLeft(separator, "\n")
+	}
+
+	if _, err := f.WriteString(separator + strings.TrimRight(snippet, "\n") + "\n"); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// appendOutput appends snippet to the --append-to-file target, if one was given.
+// Failures are warnings: the upload or check itself succeeded.
+func appendOutput(cfg *config.Config, snippet string) {
+	if appendToFile == "" {
+		return
+	}
+	if err := appendSnippet(cfg, appendToFile, snippet); err != nil {
+		warnf("%v", err)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package main
+
+import "os"
+
+// lockFile is a no-op on Windows; appends are short single writes
+func lockFile(f *os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op on Windows
+func unlockFile(f *os.File) error {
+	return nil
+}
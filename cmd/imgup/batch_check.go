@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/imgup"
+)
+
+var (
+	// Batch check flags
+	checkBatch       bool
+	checkJSONInput   bool
+	checkConcurrency int
+)
+
+// defaultCheckConcurrency is how many files check --batch looks up at once.
+// Cache lookups are quick; it matters with --prefer-remote.
+const defaultCheckConcurrency = 4
+
+// readBatchPaths reads the paths for check --batch: one per line, or a JSON
+// array of strings with --json. Blank lines are skipped.
+func readBatchPaths(r io.Reader, jsonList bool) ([]string, error) {
+	if jsonList {
+		var paths []string
+		if err := json.NewDecoder(r).Decode(&paths); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON path list: %w", err)
+		}
+		return paths, nil
+	}
+
+	var paths []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if path := strings.TrimSpace(scanner.Text()); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read paths: %w", err)
+	}
+	return paths, nil
+}
+
+// checkBatchCommand checks every path read from stdin, --concurrency at a
+// time, and prints a JSON array with each file's status in input order
+func checkBatchCommand(ctx context.Context, cfg *config.Config, service string) error {
+	paths, err := readBatchPaths(os.Stdin, checkJSONInput)
+	if err != nil {
+		return err
+	}
+
+	client := imgup.New(cfg)
+	entries := make([]dirEntry, len(paths))
+	sem := make(chan struct{}, checkConcurrency)
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if _, err := os.Stat(path); err != nil {
+				entries[i] = dirEntry{Path: path, Error: fmt.Sprintf("file not found: %s", path)}
+				return
+			}
+			entries[i] = checkPath(ctx, client, service, path)
+		}(i, path)
+	}
+	wg.Wait()
+
+	output, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal results: %w", err)
+	}
+	fmt.Println(string(output))
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/render"
+	"github.com/pdxmph/imgupv2/pkg/templates"
+	"github.com/pdxmph/imgupv2/pkg/types"
+)
+
+// batchFormats returns the format each image of a batch is rendered in: its
+// own format, or else options.format, or else the configured default. It
+// returns nil when neither the images, the options nor --append-to-file ask
+// for a format, so the response has no rendered snippets.
+func batchFormats(cfg *config.Config, request types.BatchUploadRequest) ([]string, error) {
+	fallback := ""
+	if request.Options != nil {
+		fallback = request.Options.Format
+	}
+	wanted := fallback != "" || appendToFile != ""
+	for _, img := range request.Images {
+		if img.Format != "" {
+			wanted = true
+		}
+	}
+	if !wanted {
+		return nil, nil
+	}
+	if fallback == "" {
+		fallback = cfg.Default.Format
+	}
+	if fallback == "" {
+		fallback = "url"
+	}
+
+	formats := make([]string, len(request.Images))
+	for i, img := range request.Images {
+		formats[i] = img.Format
+		if formats[i] == "" {
+			formats[i] = fallback
+		}
+		// Check every format before anything is uploaded
+		if _, err := outputTemplate(cfg, formats[i]); err != nil {
+			return nil, err
+		}
+	}
+	return formats, nil
+}
+
+// renderBatchUpload renders an uploaded batch image's snippet in format
+func renderBatchUpload(cfg *config.Config, service, format string, img types.ImageUpload, common *types.CommonSettings, result types.UploadResult) string {
+	template, err := outputTemplate(cfg, format)
+	if err != nil {
+		return ""
+	}
+	tags := append([]string{}, img.Tags...)
+	if common != nil {
+		tags = append(tags, common.Tags...)
+	}
+	return templates.Process(template, templates.Variables{
+		PhotoID:     result.PhotoID,
+		URL:         result.URL,
+		ImageURL:    result.ImageURL,
+		EditURL:     render.EditURL(service, result.PhotoID),
+		Filename:    snippetFilename(cfg, img.Path),
+		Title:       img.Title,
+		Description: img.Description,
+		Alt:         img.Alt,
+		Tags:        tags,
+	})
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pdxmph/imgupv2/pkg/term"
+	"github.com/pdxmph/imgupv2/pkg/types"
+)
+
+// summarizeBatch counts uploaded, duplicate, failed and resumed images
+func summarizeBatch(uploads []types.UploadResult) *types.BatchSummary {
+	summary := &types.BatchSummary{}
+	for _, upload := range uploads {
+		switch {
+		case upload.Error != nil:
+			summary.Failed++
+		case upload.Resumed:
+			summary.Resumed++
+		case upload.Duplicate:
+			summary.Duplicates++
+		default:
+			summary.Uploaded++
+		}
+	}
+	return summary
+}
+
+// formatBatchSummary renders a summary as "12 uploaded, 3 duplicates, 1 failed"
+func formatBatchSummary(summary *types.BatchSummary) string {
+	parts := []string{fmt.Sprintf("%d uploaded", summary.Uploaded)}
+	if summary.Duplicates > 0 {
+		noun := "duplicates"
+		if summary.Duplicates == 1 {
+			noun = "duplicate"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", summary.Duplicates, noun))
+	}
+	if summary.Resumed > 0 {
+		parts = append(parts, fmt.Sprintf("%d resumed", summary.Resumed))
+	}
+	if summary.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", summary.Failed))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// colorBatchSummary formats a summary for stderr: green when everything went
+// through, red when something failed
+func colorBatchSummary(summary *types.BatchSummary) string {
+	line := formatBatchSummary(summary)
+	if summary.Failed > 0 {
+		return term.Error(os.Stderr, line)
+	}
+	return term.Success(os.Stderr, line)
+}
+
+// allRetryable reports whether a batch has failures and all of them
+// happened in transit
+func allRetryable(uploads []types.UploadResult) bool {
+	failed := false
+	for _, upload := range uploads {
+		if upload.Error == nil {
+			continue
+		}
+		if !upload.Retryable {
+			return false
+		}
+		failed = true
+	}
+	return failed
+}
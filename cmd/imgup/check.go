@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/duplicate"
+	"github.com/pdxmph/imgupv2/pkg/imgup"
+	"github.com/pdxmph/imgupv2/pkg/render"
+	"github.com/pdxmph/imgupv2/pkg/templates"
+)
+
+// checkResult is one service's answer for a file, as reported by check --all
+type checkResult struct {
+	Service string     `json:"service"`
+	Found   bool       `json:"found"`
+	Error   string     `json:"error,omitempty"`
+	Matches []checkHit `json:"matches"`
+}
+
+// checkHit is one upload of a file to a service
+type checkHit struct {
+	PhotoID    string    `json:"photo_id"`
+	URL        string    `json:"url"`
+	ImageURL   string    `json:"image_url"`
+	UploadTime time.Time `json:"upload_time"`
+}
+
+// configuredServices returns the upload services that have credentials
+func configuredServices(cfg *config.Config) []string {
+	var services []string
+	if cfg.Flickr.AccessToken != "" && cfg.Flickr.AccessSecret != "" {
+		services = append(services, "flickr")
+	}
+	if cfg.SmugMug.AccessToken != "" && cfg.SmugMug.AccessSecret != "" {
+		services = append(services, "smugmug")
+	}
+	return services
+}
+
+// checkAllServices runs every configured service's duplicate check in
+// parallel and prints all matches
+func checkAllServices(ctx context.Context, cfg *config.Config, imagePath string) error {
+	services := configuredServices(cfg)
+	if len(services) == 0 {
+		return fmt.Errorf("Not authenticated. Run 'imgup auth flickr' or 'imgup auth smugmug' first.")
+	}
+	return checkServices(ctx, cfg, services, imagePath)
+}
+
+// checkServices checks the services in parallel and prints every match,
+// prefixed with the service. Each service is searched, so a file cached for
+// one service is still found on the others. With --all-matches each service
+// reports all of its copies of the file instead of one.
+func checkServices(ctx context.Context, cfg *config.Config, services []string, imagePath string) error {
+	template, err := outputTemplate(cfg, outputFormat)
+	if err != nil {
+		return err
+	}
+
+	client := imgup.New(cfg)
+	uploads := make([][]*duplicate.Upload, len(services))
+	errs := make([]error, len(services))
+
+	var wg sync.WaitGroup
+	for i, svc := range services {
+		wg.Add(1)
+		go func(i int, svc string) {
+			defer wg.Done()
+			if checkAllMatches {
+				uploads[i], errs[i] = client.CheckAllMatches(ctx, svc, imagePath)
+				return
+			}
+			upload, err := client.CheckService(ctx, svc, imagePath)
+			if upload != nil {
+				uploads[i] = []*duplicate.Upload{upload}
+			}
+			errs[i] = err
+		}(i, svc)
+	}
+	wg.Wait()
+
+	// Collect results in service order
+	results := make([]checkResult, len(services))
+	found := false
+	for i, svc := range services {
+		results[i] = checkResult{Service: svc, Matches: []checkHit{}}
+		if errs[i] != nil {
+			results[i].Error = errs[i].Error()
+			errorf("couldn't check %s: %v", svc, errs[i])
+			continue
+		}
+		if len(uploads[i]) > 1 {
+			warnf("%s has %d copies of %s", svc, len(uploads[i]), filepath.Base(imagePath))
+		}
+		for _, upload := range uploads[i] {
+			results[i].Found = true
+			found = true
+			results[i].Matches = append(results[i].Matches, checkHit{
+				PhotoID:    upload.RemoteID,
+				URL:        client.DisplayURL(svc, upload.RemoteID, upload.RemoteURL),
+				ImageURL:   upload.ImageURL,
+				UploadTime: upload.UploadTime,
+			})
+		}
+	}
+
+	if outputFormat == "json" {
+		output, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		filenameNoExt := snippetFilename(cfg, imagePath)
+
+		var lines []string
+		for _, result := range results {
+			if !result.Found && result.Error == "" && checkAll {
+				fmt.Fprintf(os.Stderr, "%s: not found\n", result.Service)
+			}
+			for _, hit := range result.Matches {
+				vars := templates.Variables{
+					PhotoID:  hit.PhotoID,
+					URL:      hit.URL,
+					ImageURL: hit.ImageURL,
+					EditURL:  render.EditURL(result.Service, hit.PhotoID),
+					Filename: filenameNoExt,
+				}
+				line := fmt.Sprintf("%s: %s", result.Service, templates.Process(template, vars))
+				fmt.Println(line)
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 {
+			appendOutput(cfg, strings.Join(lines, "\n"))
+		}
+	}
+
+	if !found {
+		// Exit with error code to indicate not found, like a single-service check
+		return errSilent
+	}
+	return nil
+}
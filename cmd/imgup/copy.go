@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/pdxmph/imgupv2/pkg/clipboard"
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/render"
+	"github.com/pdxmph/imgupv2/pkg/templates"
+)
+
+var (
+	// Clipboard flags
+	copyOutput bool
+	copyFormat string
+)
+
+// copyTemplate returns the template for the clipboard, or "" to copy the
+// printed output. Unknown formats are reported before anything is uploaded.
+func copyTemplate(cfg *config.Config) (string, error) {
+	if copyFormat == "" {
+		return "", nil
+	}
+	template, err := render.Template(cfg, copyFormat)
+	if errors.Is(err, render.ErrUnknownFormat) {
+		return "", unknownFormatError(cfg, copyFormat)
+	}
+	return template, err
+}
+
+// copySnippet puts the output, or the --copy-format rendering of vars, on the
+// clipboard when --copy or --copy-format was given. Failures are warnings:
+// the upload itself succeeded.
+func copySnippet(cfg *config.Config, output string, vars templates.Variables) {
+	if !copyOutput && copyFormat == "" {
+		return
+	}
+	template, err := copyTemplate(cfg)
+	if err != nil {
+		warnf("%v", err)
+		return
+	}
+	if template != "" {
+		output = templates.Process(template, vars)
+	}
+	if err := clipboard.Write(output); err != nil {
+		warnf("failed to copy to the clipboard: %v", err)
+	}
+}
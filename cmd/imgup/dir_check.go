@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/imgup"
+	"github.com/pdxmph/imgupv2/pkg/types"
+)
+
+var (
+	// Directory check and upload flags
+	checkDir       string
+	uploadDir      string
+	onlyNew        bool
+	onlyDuplicates bool
+)
+
+// dirEntry is one image's line in a check --dir manifest
+type dirEntry struct {
+	Path      string `json:"path"`
+	Duplicate bool   `json:"duplicate"`
+	PhotoID   string `json:"photo_id,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// status names the entry's state in the text manifest
+func (e dirEntry) status() string {
+	switch {
+	case e.Error != "":
+		return "error"
+	case e.Duplicate:
+		return "duplicate"
+	}
+	return "new"
+}
+
+// dirImages lists the images under dir that watch would upload, in lexical
+// order, skipping hidden directories
+func dirImages(dir string) ([]string, error) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", dir)
+	}
+	var paths []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isWatchedImage(path) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	return paths, nil
+}
+
+// checkPath looks up one image in the service's duplicate cache
+func checkPath(ctx context.Context, client *imgup.Client, service, path string) dirEntry {
+	entry := dirEntry{Path: path}
+	upload, err := client.CheckDuplicate(ctx, service, path)
+	if err != nil {
+		entry.Error = err.Error()
+	} else if upload != nil {
+		entry.Duplicate = true
+		entry.PhotoID = upload.RemoteID
+		entry.URL = client.DisplayURL(service, upload.RemoteID, upload.RemoteURL)
+	}
+	return entry
+}
+
+// checkDirectory looks up every image under dir in the service's duplicate
+// cache, keeping only new or only already-uploaded ones when asked. Files
+// that can't be checked are kept unless a filter is set.
+func checkDirectory(ctx context.Context, client *imgup.Client, service, dir string) ([]dirEntry, error) {
+	paths, err := dirImages(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := []dirEntry{}
+	for _, path := range paths {
+		entry := checkPath(ctx, client, service, path)
+		if (onlyNew || onlyDuplicates) && entry.Error != "" {
+			warnf("skipping %s: %s", path, entry.Error)
+			continue
+		}
+		if (onlyNew && entry.Duplicate) || (onlyDuplicates && !entry.Duplicate) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// checkDirCommand prints a manifest of which images under --dir are
+// already uploaded
+func checkDirCommand(ctx context.Context, cfg *config.Config, service string) error {
+	client := imgup.New(cfg)
+	entries, err := checkDirectory(ctx, client, service, checkDir)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "json" {
+		output, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+	for _, entry := range entries {
+		detail := entry.URL
+		if entry.Error != "" {
+			detail = entry.Error
+		}
+		fmt.Println(strings.TrimRight(entry.status()+"\t"+entry.Path+"\t"+detail, "\t"))
+	}
+	return nil
+}
+
+// dirBatchInput builds batch JSON uploading the images under --dir, after
+// the --only-new or --only-duplicates filter
+func dirBatchInput(ctx context.Context) ([]byte, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	applyDuplicatePreference(cfg)
+	client := imgup.New(cfg)
+	resolved, err := client.ResolveService(service)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	if onlyNew || onlyDuplicates {
+		entries, err := checkDirectory(ctx, client, resolved, uploadDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			paths = append(paths, entry.Path)
+		}
+	} else if paths, err = dirImages(uploadDir); err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no images to upload in %s", uploadDir)
+	}
+
+	request := types.BatchUploadRequest{
+		Common: &types.CommonSettings{Service: resolved},
+	}
+	for _, path := range paths {
+		request.Images = append(request.Images, types.ImageUpload{Path: path})
+	}
+	return json.Marshal(request)
+}
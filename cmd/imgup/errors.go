@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/imgup"
+	"github.com/pdxmph/imgupv2/pkg/term"
+)
+
+// exitUploadFailed is the exit status when an upload failed in transit and
+// running imgup again may succeed. Every other failure exits with 1.
+const exitUploadFailed = 3
+
+// cliError is an error whose message is printed to stderr exactly as-is,
+// without the usual "Error: " prefix
+type cliError struct {
+	msg    string
+	status int // exit status; 0 means 1
+}
+
+func (e *cliError) Error() string {
+	return e.msg
+}
+
+// failf returns an error that the top-level handler prints verbatim
+func failf(format string, args ...interface{}) error {
+	return &cliError{msg: fmt.Sprintf(format, args...)}
+}
+
+// warnf prints a warning to stderr
+func warnf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s %s\n", term.Warn(os.Stderr, "Warning:"), fmt.Sprintf(format, args...))
+}
+
+// errorf prints an error to stderr
+func errorf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s %s\n", term.Error(os.Stderr, "Error:"), fmt.Sprintf(format, args...))
+}
+
+// errSilent exits with a failure status without printing anything,
+// for commands that have already reported the problem (or report nothing)
+var errSilent = errors.New("")
+
+// errUploadsFailed exits with exitUploadFailed without printing anything,
+// for a batch that has reported its uploads failing in transit
+var errUploadsFailed = &cliError{status: exitUploadFailed}
+
+// uploadFailf is failf for a failed upload, exiting with exitUploadFailed
+// when the upload failed in transit
+func uploadFailf(err error, format string, args ...interface{}) error {
+	ce := &cliError{msg: fmt.Sprintf(format, args...)}
+	if imgup.IsTransient(err) {
+		ce.status = exitUploadFailed
+	}
+	return ce
+}
+
+// errAmbiguousService explains how to choose between configured services
+var errAmbiguousService = failf("Error: Both Flickr and SmugMug are configured. Please specify --service or set a default:\n" +
+	"  imgup config set default.service flickr\n" +
+	"  imgup config set default.service smugmug")
+
+// unknownFormatError lists the available templates for an unknown format
+func unknownFormatError(cfg *config.Config, format string) error {
+	return failf("Unknown format: %s\nAvailable formats: %s", format, strings.Join(templateNames(cfg), ", "))
+}
+
+// templateNames returns the configured template names, sorted
+func templateNames(cfg *config.Config) []string {
+	var names []string
+	for k := range cfg.Templates {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completeFormats offers the configured template names for --format
+func completeFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return templateNames(cfg), cobra.ShellCompDirectiveNoFileComp
+}
+
+// handleError reports a command error and exits with a non-zero status
+func handleError(err error) {
+	var ce *cliError
+	status := 1
+	switch {
+	case errors.Is(err, errSilent):
+		// Nothing to print
+	case errors.As(err, &ce):
+		if ce.msg != "" {
+			fmt.Fprintln(os.Stderr, term.Error(os.Stderr, ce.msg))
+		}
+		if ce.status != 0 {
+			status = ce.status
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "%s %v\n", term.Error(os.Stderr, "Error:"), err)
+	}
+	os.Exit(status)
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,12 +11,11 @@ import (
 	"github.com/pdxmph/imgupv2/pkg/upload"
 )
 
-func guiServerCmd(args []string) {
+func guiServerCmd(ctx context.Context, args []string) error {
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		os.Exit(1)
+		return failf("Error loading config: %v", err)
 	}
 
 	// Create upload service
@@ -27,7 +25,7 @@ func guiServerCmd(args []string) {
 	server := gui.NewServer(os.Stdin, os.Stdout, cfg, uploader)
 
 	// Handle graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	// Listen for interrupt signals
@@ -41,7 +39,7 @@ func guiServerCmd(args []string) {
 
 	// Run server
 	if err := server.Run(ctx); err != nil {
-		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
-		os.Exit(1)
+		return failf("Server error: %v", err)
 	}
+	return nil
 }
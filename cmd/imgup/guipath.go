@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+)
+
+// guiBundleName and guiBinaryName are the names the GUI is built with
+const (
+	guiBundleName = "imgupv2-gui.app"
+	guiBinaryName = "imgupv2-gui"
+)
+
+// findGUIApp returns the GUI app bundle or binary to launch. An explicit
+// --gui-path or gui.path must exist; otherwise the places in gui.search are
+// tried in order. The error lists every place that was searched.
+func findGUIApp(cfg *config.Config, explicit string) (string, error) {
+	if explicit == "" {
+		explicit = cfg.GUI.Path
+	}
+	if explicit != "" {
+		if _, err := os.Stat(explicit); err != nil {
+			return "", fmt.Errorf("GUI not found at %s (from --gui-path or gui.path)", explicit)
+		}
+		return explicit, nil
+	}
+
+	var searched []string
+	for _, source := range cfg.GUI.SearchOrder() {
+		path, tried := searchGUI(source)
+		if path != "" {
+			return path, nil
+		}
+		searched = append(searched, tried...)
+	}
+
+	return "", fmt.Errorf("GUI not found. Searched:\n  %s\nPoint imgup at it with --gui-path or 'imgup config set gui.path /path/to/%s'",
+		strings.Join(searched, "\n  "), guiBundleName)
+}
+
+// searchGUI looks for the GUI in one search source, returning its path or
+// the places it tried
+func searchGUI(source string) (string, []string) {
+	home, _ := os.UserHomeDir()
+
+	switch source {
+	case "dev":
+		return firstBundle(filepath.Join(home, "code", "imgupv2", "gui", "build", "bin", guiBundleName))
+	case "applications":
+		return firstBundle(
+			filepath.Join("/Applications", guiBundleName),
+			filepath.Join(home, "Applications", guiBundleName),
+		)
+	case "spotlight":
+		query := "kMDItemCFBundleIdentifier == 'com.wails.imgupv2-gui'"
+		tried := []string{"Spotlight (mdfind " + query + ")"}
+		output, err := exec.Command("mdfind", query).Output()
+		if err != nil {
+			return "", tried
+		}
+		if apps := strings.Split(strings.TrimSpace(string(output)), "\n"); apps[0] != "" {
+			return apps[0], nil
+		}
+		return "", tried
+	case "path":
+		if path, err := exec.LookPath(guiBinaryName); err == nil {
+			return path, nil
+		}
+		return "", []string{guiBinaryName + " on $PATH"}
+	}
+	return "", []string{"unknown search source '" + source + "'"}
+}
+
+// firstBundle returns the first of paths that is a directory
+func firstBundle(paths ...string) (string, []string) {
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", paths
+}
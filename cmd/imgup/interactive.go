@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/metadata"
+	"github.com/pdxmph/imgupv2/pkg/term"
+)
+
+// promptUploadMetadata asks for the upload's metadata, offering flag values
+// or the image's embedded title, description and keywords as defaults
+func promptUploadMetadata(cfg *config.Config, imagePath string) error {
+	if !term.IsTerminal(os.Stdin) {
+		return fmt.Errorf("--interactive needs a terminal. Pass --title, --description, --alt and --tags instead")
+	}
+
+	// Embedded metadata fills in whatever the flags didn't
+	embeddedTitle, embeddedDescription, keywords, _ := metadata.ExtractMetadata(imagePath)
+	if title == "" {
+		title = embeddedTitle
+	}
+	if description == "" {
+		description = embeddedDescription
+	}
+	if len(tags) == 0 {
+		sort.Strings(keywords)
+		tags = keywords
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Fprintf(os.Stderr, "Uploading %s (press Enter to keep the value in brackets, or - to clear it)\n", imagePath)
+
+	var err error
+	if title, err = promptString(reader, "Title", title); err != nil {
+		return err
+	}
+	if description, err = promptString(reader, "Description", description); err != nil {
+		return err
+	}
+	// The caption is only a stand-in for alt text with default.social_fallbacks on
+	if altText == "" && cfg.Default.SocialFallbacks {
+		altText = description
+	}
+	if altText, err = promptString(reader, "Alt text", altText); err != nil {
+		return err
+	}
+	tagList, err := promptString(reader, "Tags (comma-separated)", strings.Join(tags, ", "))
+	if err != nil {
+		return err
+	}
+	tags = splitTags(tagList)
+	if isPrivate, err = promptBool(reader, "Private", isPrivate); err != nil {
+		return err
+	}
+
+	// Only offer the social networks that are set up
+	if cfg.Mastodon.AnyConfigured() {
+		if postToMastodon, err = promptBool(reader, "Post to Mastodon", postToMastodon); err != nil {
+			return err
+		}
+	}
+	if account, _ := cfg.Bluesky.Account(blueskyAccount); account.Configured() {
+		if postToBluesky, err = promptBool(reader, "Post to Bluesky", postToBluesky); err != nil {
+			return err
+		}
+	}
+	if postToMastodon || postToBluesky {
+		if post, err = promptString(reader, "Post text", post); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// promptString asks for a value, returning current when the answer is empty
+// and an empty value when it's -
+func promptString(reader *bufio.Reader, label, current string) (string, error) {
+	if current != "" {
+		fmt.Fprintf(os.Stderr, "%s [%s]: ", label, current)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: ", label)
+	}
+
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	input = strings.TrimSpace(input)
+	switch input {
+	case "":
+		return current, nil
+	case "-":
+		return "", nil
+	}
+	return input, nil
+}
+
+// promptBool asks a yes/no question, returning current when the answer is empty
+func promptBool(reader *bufio.Reader, label string, current bool) (bool, error) {
+	choices := "y/N"
+	if current {
+		choices = "Y/n"
+	}
+	for {
+		fmt.Fprintf(os.Stderr, "%s? [%s]: ", label, choices)
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return false, fmt.Errorf("failed to read input: %w", err)
+		}
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "":
+			return current, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintf(os.Stderr, "Please answer y or n.\n")
+	}
+}
+
+// splitTags splits a comma-separated tag list, dropping empty entries
+func splitTags(list string) []string {
+	var result []string
+	for _, tag := range strings.Split(list, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
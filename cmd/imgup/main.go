@@ -3,22 +3,35 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/pdxmph/imgupv2/pkg/alttext"
 	"github.com/pdxmph/imgupv2/pkg/backends"
 	"github.com/pdxmph/imgupv2/pkg/config"
 	"github.com/pdxmph/imgupv2/pkg/duplicate"
+	"github.com/pdxmph/imgupv2/pkg/httpfixture"
+	"github.com/pdxmph/imgupv2/pkg/httpx"
+	"github.com/pdxmph/imgupv2/pkg/imageproc"
+	"github.com/pdxmph/imgupv2/pkg/imgup"
+	"github.com/pdxmph/imgupv2/pkg/oauth"
+	"github.com/pdxmph/imgupv2/pkg/render"
 	"github.com/pdxmph/imgupv2/pkg/services/bluesky"
+	"github.com/pdxmph/imgupv2/pkg/services/hashtags"
 	"github.com/pdxmph/imgupv2/pkg/services/mastodon"
 	"github.com/pdxmph/imgupv2/pkg/templates"
+	"github.com/pdxmph/imgupv2/pkg/term"
+	"github.com/pdxmph/imgupv2/pkg/textutil"
 	"github.com/pdxmph/imgupv2/pkg/types"
 )
 
@@ -30,6 +43,8 @@ var (
 
 	// Upload flags
 	title        string
+	titleFromFilename bool
+	sanitizeFilename bool
 	description  string
 	altText      string
 	outputFormat string
@@ -37,24 +52,92 @@ var (
 	tags         []string
 	service      string
 	
+	// Flickr-only flags
+	safetyLevel  string
+	contentType  string
+	hiddenFromSearch bool
+	
+	// SmugMug-only flags
+	smugmugPrivacy   string
+	
 	// Mastodon flags
 	postToMastodon   bool
+	mastodonAccount  string
 	post             string
+	postFile         string
 	visibility       string
-	
-	// Bluesky flag (shares post with Mastodon)
+	tagPrefix        string
+	pollOptions      string
+	pollDuration     time.Duration
+	noSocialURL      bool
+	noText           bool
+	tagsFile         string
+	
+	// Bluesky flags (shares post with Mastodon)
 	postToBluesky    bool
+	blueskyAccount   string
 	
 	// Testing flag
 	dryRun           bool
 	
+	// Prompt for metadata instead of flags
+	interactive      bool
+	
 	// Duplicate detection flags
 	force            bool
+	replaceUpload    bool
+	skipExistingInAlbum bool
+	uploadAlbum      string
+	dateTaken        string
+	remoteFilename   string
+	verifyUpload     bool
+	copyEXIFFrom     string
+	noEmbedMetadata  bool
 	duplicateInfo    bool  // GUI flag to get duplicate status in JSON
+	preferRemote     bool
+	preferCache      bool
+	pruneCacheOnMiss bool
 	
 	// JSON input flags
 	jsonInput        bool
 	jsonFile         string
+	jsonSchema       bool
+	resumeBatch      bool
+	printRequest     bool
+	
+	// Session defaults flag
+	noRemember       bool
+	
+	// Alt text lint flag
+	lintAlt          bool
+	altRequired      bool
+	
+	// Format conversion flag
+	transcode        string
+	
+	// Template override flag
+	templateFile     string
+	
+	// Minimum size flag
+	minDimension     int
+	
+	// Corruption check flag
+	validateImage    bool
+	
+	// Config export/import flags
+	exportFormat     string
+	includeSecrets   bool
+	importFormat     string
+	
+	// Check flags
+	checkAll         bool
+	checkAllMatches  bool
+	
+	// Profile for this run
+	configProfile    string
+
+	// Output
+	noColor          bool
 )
 
 func main() {
@@ -80,13 +163,16 @@ with support for metadata embedding and multiple output formats.`,
 	}
 	
 	rootCmd.Flags().BoolVarP(&showVersion, "version", "v", false, "version for imgup")
+	rootCmd.PersistentFlags().StringVar(&configProfile, "config-profile", "", "Use this profile's configuration for one command (see 'imgup profile')")
+	rootCmd.RegisterFlagCompletionFunc("config-profile", completeProfiles)
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Don't color output (also off when NO_COLOR is set or output isn't a terminal)")
 
 	// Auth command
 	authCmd := &cobra.Command{
 		Use:   "auth [service]",
 		Short: "Authenticate with a photo service",
 		Args:  cobra.ExactArgs(1),
-		Run:   authCommand,
+		RunE:  authCommand,
 	}
 
 	// Upload command
@@ -94,44 +180,120 @@ with support for metadata embedding and multiple output formats.`,
 		Use:   "upload [image]",
 		Short: "Upload an image",
 		Args:  cobra.RangeArgs(0, 1), // 0 for JSON stdin, 1 for single image
-		Run:   uploadCommand,
+		RunE:  uploadCommand,
 	}
 
 	// Add upload flags
 	uploadCmd.Flags().StringVar(&title, "title", "", "Photo title")
+	uploadCmd.Flags().BoolVar(&titleFromFilename, "title-from-filename", false, "Without --title, use the cleaned-up filename as the title")
+	uploadCmd.Flags().BoolVar(&sanitizeFilename, "sanitize-filename", false, "Drop counters like \"(1)\" and \" copy\" from the filename used in titles and %filename%")
 	uploadCmd.Flags().StringVar(&description, "description", "", "Photo description")
+	uploadCmd.Flags().StringVar(&description, "caption", "", "Photo caption stored on the service (same as --description)")
 	uploadCmd.Flags().StringVar(&altText, "alt", "", "Alt text for accessibility")
-	uploadCmd.Flags().StringVar(&outputFormat, "format", "url", "Output format: url, markdown, html, json")
+	uploadCmd.Flags().StringVar(&outputFormat, "format", "url", "Output format: url, markdown, html, json, org, bbcode (see 'imgup config show')")
+	uploadCmd.Flags().StringVar(&templateFile, "template-file", "", "Render the output with the template in this file instead of --format's")
 	uploadCmd.Flags().BoolVar(&isPrivate, "private", false, "Make the photo private")
 	uploadCmd.Flags().StringSliceVar(&tags, "tags", nil, "Comma-separated tags")
+	uploadCmd.Flags().StringVar(&tagsFile, "tags-file", "", "Add the tags listed in a file, one per line or comma-separated (# starts a comment)")
 	uploadCmd.Flags().StringVar(&service, "service", "", "Upload service: flickr or smugmug (auto-detected if not specified)")
+	uploadCmd.Flags().StringVar(&safetyLevel, "safety", "", "Flickr safety level: safe, moderate, restricted")
+	uploadCmd.Flags().StringVar(&contentType, "content-type", "", "Flickr content type: photo, screenshot, other")
+	uploadCmd.Flags().BoolVar(&hiddenFromSearch, "hidden-from-search", false, "Hide the photo from Flickr public search")
+	uploadCmd.Flags().StringVar(&smugmugPrivacy, "smugmug-privacy", "", "SmugMug image privacy: public, unlisted (hidden from the gallery), private (needs a private album)")
 	
 	// Add social posting flags
 	uploadCmd.Flags().BoolVar(&postToMastodon, "mastodon", false, "Post to Mastodon after upload")
+	uploadCmd.Flags().StringVar(&mastodonAccount, "mastodon-account", "", "Comma-separated named Mastodon accounts to post to (see 'imgup config show')")
 	uploadCmd.Flags().BoolVar(&postToBluesky, "bluesky", false, "Post to Bluesky after upload")
+	uploadCmd.Flags().StringVar(&blueskyAccount, "bluesky-account", "", "Named Bluesky account to post as (see 'imgup config show')")
 	uploadCmd.Flags().StringVar(&post, "post", "", "Text for social media post (shared by Mastodon and Bluesky)")
+	uploadCmd.Flags().StringVar(&post, "embed-text", "", "Social post body, separate from the caption and alt text (same as --post)")
 	uploadCmd.Flags().StringVar(&visibility, "visibility", "public", "Mastodon post visibility: public, unlisted, followers, direct (Mastodon only)")
+	uploadCmd.Flags().StringVar(&tagPrefix, "tag-prefix", "", "Prefix for social hashtags built from tags (service tags are unchanged)")
+	uploadCmd.Flags().BoolVar(&noSocialURL, "no-social-url", false, "Leave the photo URL out of social posts (the image is still attached)")
+	uploadCmd.Flags().BoolVar(&noText, "no-text", false, "Post only the image to social media, with no text, photo URL or hashtags")
+	uploadCmd.Flags().StringVar(&pollOptions, "poll", "", "Add a Mastodon poll with 2-4 options separated by | (Mastodon only)")
+	uploadCmd.Flags().DurationVar(&pollDuration, "poll-duration", 24*time.Hour, "How long the --poll stays open, e.g. 30m, 24h (Mastodon only)")
 	uploadCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be posted without actually posting")
 	
 	// Add duplicate detection flags
 	uploadCmd.Flags().BoolVar(&duplicateInfo, "duplicate-info", false, "Include duplicate status in JSON output (for GUI)")
 	uploadCmd.Flags().BoolVar(&force, "force", false, "Force upload even if duplicate is found")
+	uploadCmd.Flags().BoolVar(&replaceUpload, "replace", false, "Upload even if a duplicate is found, and remove the checksum tag from the earlier Flickr copies")
+	uploadCmd.Flags().StringVar(&uploadAlbum, "album", "", "Album to upload to by name (Flickr: photoset, added besides the photostream); defaults to flickr.upload_album or smugmug.upload_album")
+	uploadCmd.Flags().StringVar(&dateTaken, "date-taken", "", "Set the Flickr date taken after upload: YYYY-MM-DD[ HH:MM[:SS]], or auto for the EXIF date")
+	uploadCmd.Flags().StringVar(&remoteFilename, "remote-filename", "", "Name the service stores the file under, instead of its name on disk")
+	uploadCmd.Flags().BoolVar(&verifyUpload, "verify-upload", false, "SmugMug: check the stored original's checksum matches the file after upload (one more request per image)")
+	uploadCmd.Flags().StringVar(&copyEXIFFrom, "copy-exif-from", "", "Copy the copyright, artist and credit tags from this image into the uploaded file (needs exiftool)")
+	uploadCmd.Flags().BoolVar(&noEmbedMetadata, "no-embed-metadata", false, "Don't write metadata into the file; it's only set through the service's API")
+	uploadCmd.MarkFlagsMutuallyExclusive("no-embed-metadata", "copy-exif-from")
+	uploadCmd.Flags().BoolVar(&skipExistingInAlbum, "skip-existing-in-album", false, "Skip the upload if the album (Flickr: photoset, or photostream without an album) has a photo with the same title (see default.skip_existing_match)")
+	uploadCmd.Flags().BoolVar(&preferRemote, "prefer-remote", false, "Confirm duplicates with a search on the service (slower, catches deleted photos)")
+	uploadCmd.Flags().BoolVar(&preferCache, "prefer-cache", false, "Trust the local upload cache for duplicates (default)")
+	uploadCmd.Flags().BoolVar(&pruneCacheOnMiss, "prune-cache-on-miss", false, "Check with the service and remove cache entries for photos it no longer has")
+	uploadCmd.MarkFlagsMutuallyExclusive("prefer-remote", "prefer-cache")
+	uploadCmd.MarkFlagsMutuallyExclusive("prune-cache-on-miss", "prefer-cache")
+	uploadCmd.MarkFlagsMutuallyExclusive("description", "caption")
+	uploadCmd.Flags().StringVar(&postFile, "post-file", "", "Read the social post text from a file (--post - reads stdin)")
+	uploadCmd.MarkFlagsMutuallyExclusive("post", "embed-text", "post-file")
 	
 	// Add JSON input flags
 	uploadCmd.Flags().BoolVar(&jsonInput, "json", false, "Read JSON upload specification from stdin")
 	uploadCmd.Flags().StringVar(&jsonFile, "json-file", "", "Read JSON upload specification from file")
+	uploadCmd.Flags().BoolVar(&jsonSchema, "json-schema", false, "Print the JSON Schema for --json input and exit")
+	uploadCmd.Flags().BoolVar(&resumeBatch, "resume", false, "Skip images an earlier run of the same JSON batch already uploaded")
+	uploadCmd.Flags().BoolVar(&printRequest, "print-request", false, "Print the JSON batch request as it will run, with defaults applied, to stderr")
+	uploadCmd.Flags().BoolVar(&noRemember, "no-remember", false, "Don't use or update last-used service and visibility")
+	uploadCmd.Flags().BoolVar(&lintAlt, "lint-alt", false, "Warn about missing, short or redundant alt text")
+	uploadCmd.Flags().BoolVar(&altRequired, "alt-required", false, "Refuse to upload images without alt text or a description")
+	uploadCmd.Flags().StringVar(&appendToFile, "append-to-file", "", "Also append the output to this file, with a timestamp separator")
+	uploadCmd.Flags().BoolVar(&copyOutput, "copy", false, "Also copy the output to the clipboard")
+	uploadCmd.Flags().StringVar(&copyFormat, "copy-format", "", "Copy this format to the clipboard instead of the printed one (implies --copy)")
+	uploadCmd.Flags().StringVar(&transcode, "transcode", "", "Convert the image before upload: jpeg, png or webp")
+	uploadCmd.Flags().IntVar(&minDimension, "min-dimension", 0, "Reject images whose longest edge is shorter than this many pixels")
+	uploadCmd.Flags().BoolVar(&validateImage, "validate", true, "Decode JPEG, PNG and GIF files before upload and reject corrupt ones")
+	uploadCmd.Flags().BoolVar(&interactive, "interactive", false, "Prompt for title, description, alt text, tags, privacy and social posting")
+	uploadCmd.MarkFlagsMutuallyExclusive("interactive", "json")
+	uploadCmd.MarkFlagsMutuallyExclusive("interactive", "json-file")
+	uploadCmd.Flags().StringVar(&uploadDir, "dir", "", "Upload the images in a directory and its subdirectories as a batch")
+	uploadCmd.Flags().BoolVar(&onlyNew, "only-new", false, "With --dir, upload only images not uploaded before")
+	uploadCmd.Flags().BoolVar(&onlyDuplicates, "only-duplicates", false, "With --dir, upload only images uploaded before (use with --force or --replace)")
+	uploadCmd.MarkFlagsMutuallyExclusive("dir", "json", "json-file", "interactive")
+	uploadCmd.MarkFlagsMutuallyExclusive("only-new", "only-duplicates")
 
 	// Check command
 	checkCmd := &cobra.Command{
 		Use:   "check [image]",
 		Short: "Check if an image has already been uploaded",
-		Args:  cobra.ExactArgs(1),
-		Run:   checkCommand,
+		Args:  cobra.RangeArgs(0, 1), // 0 with --dir or --batch
+		RunE:  checkCommand,
 	}
 	
 	// Add check flags
-	checkCmd.Flags().StringVar(&outputFormat, "format", "url", "Output format: url, markdown, html, json")
+	checkCmd.Flags().StringVar(&outputFormat, "format", "url", "Output format: url, markdown, html, json, org, bbcode (see 'imgup config show')")
 	checkCmd.Flags().StringVar(&service, "service", "", "Upload service: flickr or smugmug (auto-detected if not specified)")
+	checkCmd.Flags().BoolVar(&checkAll, "all", false, "Check every configured service and report all matches")
+	checkCmd.Flags().BoolVar(&checkAllMatches, "all-matches", false, "Search the service and list every copy of the image, not just one")
+	checkCmd.Flags().StringVar(&appendToFile, "append-to-file", "", "Also append the output to this file, with a timestamp separator")
+	checkCmd.Flags().BoolVar(&preferRemote, "prefer-remote", false, "Confirm duplicates with a search on the service (slower, catches deleted photos)")
+	checkCmd.Flags().BoolVar(&preferCache, "prefer-cache", false, "Trust the local upload cache for duplicates (default)")
+	checkCmd.Flags().BoolVar(&pruneCacheOnMiss, "prune-cache-on-miss", false, "Check with the service and remove cache entries for photos it no longer has")
+	checkCmd.MarkFlagsMutuallyExclusive("prefer-remote", "prefer-cache")
+	checkCmd.MarkFlagsMutuallyExclusive("prune-cache-on-miss", "prefer-cache")
+	checkCmd.Flags().StringVar(&checkDir, "dir", "", "Report which images in a directory and its subdirectories are already uploaded")
+	checkCmd.Flags().BoolVar(&onlyNew, "only-new", false, "With --dir, list only images not uploaded before")
+	checkCmd.Flags().BoolVar(&onlyDuplicates, "only-duplicates", false, "With --dir, list only images uploaded before")
+	checkCmd.Flags().BoolVar(&checkBatch, "batch", false, "Check the paths read from stdin, one per line, and print a JSON array of their statuses")
+	checkCmd.Flags().BoolVar(&checkJSONInput, "json", false, "With --batch, read a JSON array of paths instead of lines")
+	checkCmd.Flags().IntVar(&checkConcurrency, "concurrency", defaultCheckConcurrency, "With --batch, how many files to check at once")
+	checkCmd.MarkFlagsMutuallyExclusive("dir", "batch", "all", "all-matches")
+	checkCmd.MarkFlagsMutuallyExclusive("only-new", "only-duplicates")
+	
+	// Complete --format with the configured template names
+	uploadCmd.RegisterFlagCompletionFunc("format", completeFormats)
+	uploadCmd.RegisterFlagCompletionFunc("copy-format", completeFormats)
+	uploadCmd.RegisterFlagCompletionFunc("transcode", cobra.FixedCompletions(imageproc.Formats, cobra.ShellCompDirectiveNoFileComp))
+	checkCmd.RegisterFlagCompletionFunc("format", completeFormats)
 
 	// Config command
 	configCmd := &cobra.Command{
@@ -142,67 +304,139 @@ with support for metadata embedding and multiple output formats.`,
 	configShowCmd := &cobra.Command{
 		Use:   "show",
 		Short: "Show configuration",
-		Run:   configShowCommand,
+		RunE:  configShowCommand,
 	}
 
 	configSetCmd := &cobra.Command{
 		Use:   "set [key] [value]",
 		Short: "Set a configuration value",
 		Args:  cobra.ExactArgs(2),
-		Run:   configSetCommand,
+		RunE:  configSetCommand,
+	}
+
+	configExportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Print the configuration for backup or another machine",
+		Long: `Print the full configuration as JSON or YAML. Secrets (tokens, app
+passwords and consumer secrets) are replaced with REDACTED unless
+--include-secrets is given.`,
+		Args: cobra.NoArgs,
+		RunE: configExportCommand,
+	}
+	configExportCmd.Flags().StringVar(&exportFormat, "format", "json", "Output format: json or yaml")
+	configExportCmd.Flags().BoolVar(&includeSecrets, "include-secrets", false, "Include tokens and passwords in the export")
+	configExportCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(config.ExportFormats, cobra.ShellCompDirectiveNoFileComp))
+
+	configImportCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Merge an exported configuration into the current one",
+		Long: `Merge a configuration written by 'imgup config export' into the current
+one. Settings in the file replace current ones; settings it leaves out,
+and REDACTED secrets, are kept. Use - to read from stdin.`,
+		Args: cobra.ExactArgs(1),
+		RunE: configImportCommand,
+	}
+	configImportCmd.Flags().StringVar(&importFormat, "format", "", "Input format: json or yaml (default: from the file extension)")
+	configImportCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(config.ExportFormats, cobra.ShellCompDirectiveNoFileComp))
+
+	configPathCmd := &cobra.Command{
+		Use:   "path",
+		Short: "Print the location of the configuration file",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println(config.Path())
+		},
 	}
 
-	configCmd.AddCommand(configShowCmd, configSetCmd)
+	configCmd.AddCommand(configShowCmd, configSetCmd, configPathCmd, configExportCmd, configImportCmd)
 
 	// Version command
 	versionCmd := &cobra.Command{
 		Use:   "version",
 		Short: "Show version information",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			fmt.Printf("imgupv2 version %s\n", version)
 			if version != "dev" {
 				fmt.Printf("  commit: %s\n", commit)
 				fmt.Printf("  built:  %s\n", date)
 			}
+			return nil
 		},
 	}
 
 	// Add commands to root
-	rootCmd.AddCommand(authCmd, uploadCmd, checkCmd, configCmd, versionCmd, createPullCommand())
+	rootCmd.AddCommand(authCmd, uploadCmd, checkCmd, configCmd, versionCmd, createPullCommand(), createPostCommand(), createRetrySocialCommand(), createUpdateMetadataCommand(), createWatchCommand(), createProfileCommand())
+
+	// Identify imgup on every request
+	transport := httpx.Install(httpx.UserAgent(version))
+
+	// Commands return errors; report them here so messages and exit codes stay consistent.
+	// Usage is only shown for argument/flag errors, which cobra reports before PersistentPreRun.
+	rootCmd.SilenceErrors = true
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if noColor {
+			term.Disable()
+		}
+		if configProfile != "" {
+			if err := config.SetProfile(configProfile); err != nil {
+				return err
+			}
+		}
+		// The profile's config can name its own User-Agent; config errors are
+		// reported by the command itself
+		if cfg, err := config.Load(); err == nil && cfg.Default.UserAgent != "" {
+			transport.UserAgent = cfg.Default.UserAgent
+		}
+		return nil
+	}
+
+	// Record or replay HTTP traffic when IMGUP_HTTP_FIXTURE is set
+	saveFixture, err := httpfixture.InstallFromEnv()
+	if err != nil {
+		handleError(err)
+	}
+
+	// Cancel in-flight work on Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = rootCmd.ExecuteContext(ctx)
 
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	// Save the recording before handleError exits
+	if saveErr := saveFixture(); saveErr != nil {
+		warnf("%v", saveErr)
+	}
+
+	if err != nil {
+		stop()
+		handleError(err)
 	}
 }
 
-func authCommand(cmd *cobra.Command, args []string) {
+func authCommand(cmd *cobra.Command, args []string) error {
 	service := args[0]
 	switch service {
 	case "flickr":
 		if err := authFlickr(); err != nil {
-			fmt.Fprintf(os.Stderr, "Authentication failed: %v\n", err)
-			os.Exit(1)
+			return failf("Authentication failed: %v", err)
 		}
 	case "mastodon":
 		if err := authMastodon(); err != nil {
-			fmt.Fprintf(os.Stderr, "Authentication failed: %v\n", err)
-			os.Exit(1)
+			return failf("Authentication failed: %v", err)
 		}
 	case "bluesky":
 		if err := authBluesky(); err != nil {
-			fmt.Fprintf(os.Stderr, "Authentication failed: %v\n", err)
-			os.Exit(1)
+			return failf("Authentication failed: %v", err)
 		}
 	case "smugmug":
 		if err := authSmugMug(); err != nil {
-			fmt.Fprintf(os.Stderr, "Authentication failed: %v\n", err)
-			os.Exit(1)
+			return failf("Authentication failed: %v", err)
 		}
 	default:
-		fmt.Fprintf(os.Stderr, "Unknown service: %s\n", service)
-		fmt.Fprintf(os.Stderr, "Available services: flickr, mastodon, bluesky, smugmug\n")
-		os.Exit(1)
+		return failf("Unknown service: %s\nAvailable services: flickr, mastodon, bluesky, smugmug", service)
 	}
+	return nil
 }
 
 func authFlickr() error {
@@ -272,15 +506,30 @@ func authMastodon() error {
 		return fmt.Errorf("missing instance URL")
 	}
 
-	// Step 1: Register the app if we don't have client credentials
-	if cfg.Mastodon.ClientID == "" || cfg.Mastodon.ClientSecret == "" {
+	scopes := cfg.Mastodon.RequestedScopes()
+
+	// The authorization code comes back to a local callback, received on
+	// loopback only and checked against a random state
+	callback, err := oauth.NewCodeServer(8080, "/callback")
+	if err != nil {
+		return err
+	}
+
+	// Step 1: Register the app if we don't have client credentials, or if
+	// the requested scopes changed (an app can't grant more than it registered for)
+	needsRegistration := cfg.Mastodon.ClientID == "" || cfg.Mastodon.ClientSecret == ""
+	if !needsRegistration && cfg.Mastodon.RegisteredScopes() != scopes {
+		fmt.Println("Mastodon scopes changed; registering the app again.")
+		needsRegistration = true
+	}
+	if needsRegistration {
 		fmt.Println("Registering app with Mastodon instance...")
 		
 		// Register app
 		appData := url.Values{}
 		appData.Set("client_name", "imgupv2")
-		appData.Set("redirect_uris", "http://localhost:8080/callback")
-		appData.Set("scopes", "read write:media write:statuses")
+		appData.Set("redirect_uris", callback.URL())
+		appData.Set("scopes", scopes)
 		appData.Set("website", "https://github.com/pdxmph/imgupv2")
 		
 		resp, err := http.PostForm(cfg.Mastodon.InstanceURL+"/api/v1/apps", appData)
@@ -304,6 +553,7 @@ func authMastodon() error {
 		
 		cfg.Mastodon.ClientID = appResp.ClientID
 		cfg.Mastodon.ClientSecret = appResp.ClientSecret
+		cfg.Mastodon.AppScopes = scopes
 		
 		// Save the client credentials
 		if err := cfg.Save(); err != nil {
@@ -313,54 +563,37 @@ func authMastodon() error {
 		fmt.Println("App registered successfully!")
 	}
 	
-	// Step 2: OAuth 2.0 authorization flow
-	authURL := fmt.Sprintf("%s/oauth/authorize?client_id=%s&scope=read%%20write:media%%20write:statuses&redirect_uri=%s&response_type=code",
-		cfg.Mastodon.InstanceURL,
-		cfg.Mastodon.ClientID,
-		url.QueryEscape("http://localhost:8080/callback"))
-	
-	fmt.Printf("\nPlease visit this URL to authorize imgupv2:\n%s\n\n", authURL)
-	
-	// Start local server to receive callback
-	authCode := make(chan string, 1)
-	errChan := make(chan error, 1)
-	
-	srv := &http.Server{Addr: ":8080"}
+	// Step 2: OAuth 2.0 authorization flow, with PKCE so an intercepted code
+	// can't be exchanged by anyone else
+	pkce, err := oauth.NewPKCE()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := callback.Start(ctx); err != nil {
+		return err
+	}
 	
-	http.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
-		code := r.URL.Query().Get("code")
-		if code == "" {
-			errChan <- fmt.Errorf("no authorization code received")
-			fmt.Fprintf(w, "Error: No authorization code received")
-			return
-		}
-		
-		authCode <- code
-		fmt.Fprintf(w, "Authorization successful! You can close this window and return to the terminal.")
-	})
+	authParams := url.Values{}
+	authParams.Set("client_id", cfg.Mastodon.ClientID)
+	authParams.Set("scope", scopes)
+	authParams.Set("redirect_uri", callback.URL())
+	authParams.Set("response_type", "code")
+	authParams.Set("state", callback.State())
+	authParams.Set("code_challenge", pkce.Challenge)
+	authParams.Set("code_challenge_method", oauth.PKCEMethod)
+	authURL := cfg.Mastodon.InstanceURL + "/oauth/authorize?" + strings.ReplaceAll(authParams.Encode(), "+", "%20")
 	
-	// Start server in goroutine
-	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errChan <- err
-		}
-	}()
+	fmt.Printf("\nPlease visit this URL to authorize imgupv2:\n%s\n\n", authURL)
 	
 	// Wait for auth code or error
-	var code string
-	select {
-	case code = <-authCode:
-		fmt.Println("Authorization code received!")
-	case err := <-errChan:
+	code, err := callback.Wait(5 * time.Minute)
+	if err != nil {
 		return fmt.Errorf("authorization failed: %w", err)
-	case <-time.After(5 * time.Minute):
-		return fmt.Errorf("authorization timeout")
 	}
-	
-	// Shutdown server
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	srv.Shutdown(ctx)
+	fmt.Println("Authorization code received!")
+	cancel()
 	
 	// Step 3: Exchange code for access token
 	tokenData := url.Values{}
@@ -368,8 +601,9 @@ func authMastodon() error {
 	tokenData.Set("client_secret", cfg.Mastodon.ClientSecret)
 	tokenData.Set("code", code)
 	tokenData.Set("grant_type", "authorization_code")
-	tokenData.Set("redirect_uri", "http://localhost:8080/callback")
-	tokenData.Set("scope", "read write:media write:statuses")
+	tokenData.Set("redirect_uri", callback.URL())
+	tokenData.Set("code_verifier", pkce.Verifier)
+	tokenData.Set("scope", scopes)
 	
 	resp, err := http.PostForm(cfg.Mastodon.InstanceURL+"/oauth/token", tokenData)
 	if err != nil {
@@ -472,41 +706,63 @@ func authSmugMug() error {
 	return nil
 }
 
-func uploadCommand(cmd *cobra.Command, args []string) {
+func uploadCommand(cmd *cobra.Command, args []string) error {
+	if jsonSchema {
+		return printJSONSchema(types.BatchUploadSchema())
+	}
+
+	if (onlyNew || onlyDuplicates) && uploadDir == "" {
+		return fmt.Errorf("--only-new and --only-duplicates need --dir")
+	}
+	if uploadDir != "" && len(args) > 0 {
+		return fmt.Errorf("pass an image or --dir, not both")
+	}
+	if remoteFilename != "" && (jsonInput || jsonFile != "" || uploadDir != "") {
+		return fmt.Errorf("--remote-filename names a single upload; set \"remote_filename\" on each image in batch JSON instead")
+	}
+	if tagsFile != "" {
+		var err error
+		if fileTags, err = readTagsFile(tagsFile); err != nil {
+			return err
+		}
+		tags = mergeTags(tags, fileTags)
+	}
+
 	// Check if JSON mode is requested
-	if jsonInput || jsonFile != "" {
+	if jsonInput || jsonFile != "" || uploadDir != "" {
 		if err := handleJSONUpload(cmd); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return err
 		}
-		return
+		return nil
+	}
+	if resumeBatch {
+		return fmt.Errorf("--resume only applies to JSON batches (--json or --json-file)")
+	}
+	if err := readPostText(&post, postFile); err != nil {
+		return err
 	}
 	
 	// Single image mode - require exactly one argument
 	if len(args) != 1 {
-		fmt.Fprintf(os.Stderr, "Error: Single image upload requires exactly one image path\n")
+		errorf("Single image upload requires exactly one image path")
 		cmd.Usage()
-		os.Exit(1)
+		return errSilent
 	}
 	
 	imagePath := args[0]
 
 	// Check if file exists
 	if _, err := os.Stat(imagePath); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: File not found: %s\n", imagePath)
-		os.Exit(1)
+		return fmt.Errorf("File not found: %s", imagePath)
 	}
 
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		os.Exit(1)
+		return failf("Error loading config: %v", err)
 	}
-	
-	// Variables to track upload results
-	var photoID, photoURL, imageURL string
-	var isDuplicate bool
+	applyDuplicatePreference(cfg)
+	client := imgup.New(cfg)
 
 	// Apply defaults from config if flags weren't explicitly set
 	if !cmd.Flags().Changed("format") && cfg.Default.Format != "" {
@@ -515,187 +771,211 @@ func uploadCommand(cmd *cobra.Command, args []string) {
 	if !cmd.Flags().Changed("service") && cfg.Default.Service != "" {
 		service = cfg.Default.Service
 	}
-
-	// Determine which service to use
-	if service == "" {
-		// Auto-detect based on which service is configured
-		hasFlickr := cfg.Flickr.AccessToken != "" && cfg.Flickr.AccessSecret != ""
-		hasSmugMug := cfg.SmugMug.AccessToken != "" && cfg.SmugMug.AccessSecret != ""
-		
-		if hasFlickr && hasSmugMug {
-			// If default service is set, use it
-			if cfg.Default.Service != "" {
-				service = cfg.Default.Service
-			} else {
-				fmt.Fprintf(os.Stderr, "Error: Both Flickr and SmugMug are configured. Please specify --service or set a default:\n")
-				fmt.Fprintf(os.Stderr, "  imgup config set default.service flickr\n")
-				fmt.Fprintf(os.Stderr, "  imgup config set default.service smugmug\n")
-				os.Exit(1)
-			}
-		} else if hasFlickr {
-			service = "flickr"
-		} else if hasSmugMug {
-			service = "smugmug"
-		} else {
-			fmt.Fprintf(os.Stderr, "Error: Not authenticated. Run 'imgup auth flickr' or 'imgup auth smugmug' first.\n")
-			os.Exit(1)
+	
+	// Reject unknown formats and unreadable templates before uploading anything
+	if _, err := outputTemplate(cfg, outputFormat); err != nil {
+		return err
+	}
+	if _, err := copyTemplate(cfg); err != nil {
+		return err
+	}
+	if transcode != "" {
+		if _, err := imageproc.ParseFormat(transcode); err != nil {
+			return err
 		}
 	}
-	
-	// Validate service
-	if service != "flickr" && service != "smugmug" {
-		fmt.Fprintf(os.Stderr, "Error: Invalid service '%s'. Must be 'flickr' or 'smugmug'\n", service)
-		os.Exit(1)
+	if !cmd.Flags().Changed("min-dimension") {
+		minDimension = cfg.Default.MinDimension
+	}
+	if !cmd.Flags().Changed("validate") {
+		validateImage = cfg.ValidateImages()
+	}
+	if !cmd.Flags().Changed("alt-required") {
+		altRequired = cfg.Default.AltRequired
 	}
 	
-	// Check authentication for specified service
-	switch service {
-	case "flickr":
-		if cfg.Flickr.AccessToken == "" || cfg.Flickr.AccessSecret == "" {
-			fmt.Fprintf(os.Stderr, "Error: Not authenticated with Flickr. Run 'imgup auth flickr' first.\n")
-			os.Exit(1)
+	// Ask for metadata before falling back to the filename
+	if interactive {
+		if err := promptUploadMetadata(cfg, imagePath); err != nil {
+			return err
 		}
-	case "smugmug":
-		if cfg.SmugMug.AccessToken == "" || cfg.SmugMug.AccessSecret == "" {
-			fmt.Fprintf(os.Stderr, "Error: Not authenticated with SmugMug. Run 'imgup auth smugmug' first.\n")
-			os.Exit(1)
+	}
+	
+	// Fall back to a title built from the filename
+	if title == "" && (titleFromFilename || cfg.Default.TitleFromFilename) {
+		title = textutil.TitleizeWith(displayFilename(cfg, uploadName(imagePath, remoteFilename)), cfg.Default.TitleCleanup)
+	}
+	
+	// Catch a mistyped Mastodon or Bluesky account before uploading
+	var mastodonAccounts []string
+	if postToMastodon {
+		if mastodonAccounts, err = mastodonAccountNames(cfg, mastodonAccount); err != nil {
+			return err
 		}
-		if cfg.SmugMug.AlbumID == "" {
-			fmt.Fprintf(os.Stderr, "Error: No SmugMug album selected. Run 'imgup auth smugmug' again.\n")
-			os.Exit(1)
+	}
+	if postToBluesky {
+		if _, err := cfg.Bluesky.Account(blueskyAccount); err != nil {
+			return err
 		}
 	}
-
-
-	// Always check for duplicates unless --force is specified or disabled in config
-	if !force && cfg.IsDuplicateCheckEnabled() {
-		var checker *duplicate.RemoteChecker
-		
-		switch service {
-		case "flickr":
-			checker, err = duplicate.SetupFlickrDuplicateChecker(&cfg.Flickr)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error setting up duplicate checker: %v\n", err)
-				os.Exit(1)
-			}
-			
-		case "smugmug":
-			checker, err = duplicate.SetupSmugMugDuplicateChecker(&cfg.SmugMug)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error setting up duplicate checker: %v\n", err)
-				os.Exit(1)
+	
+	// Polls are Mastodon-only
+	var poll *mastodon.Poll
+	if pollOptions != "" {
+		if !postToMastodon {
+			if postToBluesky {
+				return fmt.Errorf("Bluesky doesn't support polls. Add --mastodon to post the poll there")
 			}
+			return fmt.Errorf("--poll needs --mastodon")
 		}
-		defer checker.Close()
-
-		// Silent duplicate checking - no verbose messages
-		ctx := context.Background()
-		
-		existingUpload, err := checker.Check(ctx, imagePath)
-		if err != nil {
-			// Only show error if it's significant
-			if duplicateInfo {
-				// For GUI mode, we need to handle errors differently
-				fmt.Fprintf(os.Stderr, "Error checking for duplicate: %v\n", err)
-			}
-			// Continue with upload if duplicate check fails
-		} else if existingUpload != nil {
-			// Found a duplicate! Set our variables instead of exiting
-			isDuplicate = true
-			photoID = existingUpload.RemoteID
-			photoURL = existingUpload.RemoteURL
-			imageURL = existingUpload.ImageURL
-			
-			if os.Getenv("IMGUP_DEBUG") != "" {
-				fmt.Fprintf(os.Stderr, "DEBUG: Duplicate detected!\n")
-				fmt.Fprintf(os.Stderr, "  Service: %s\n", existingUpload.Service)
-				fmt.Fprintf(os.Stderr, "  RemoteID: %s\n", photoID)
-				fmt.Fprintf(os.Stderr, "  RemoteURL: %s\n", photoURL)
-				fmt.Fprintf(os.Stderr, "  ImageURL: %s\n", imageURL)
-			}
+		if poll, err = mastodon.ParsePoll(pollOptions, pollDuration); err != nil {
+			return err
+		}
+	}
+	
+	// Check alt text before uploading so it can still be fixed. Missing alt
+	// text isn't flagged when describe.endpoint will generate it.
+	if (lintAlt || cfg.Default.LintAlt) && (altText != "" || cfg.Describe.Endpoint == "") {
+		for _, warning := range altLinter(cfg).Lint(altText, title) {
+			warnf("%s", warning)
+		}
+	}
+	
+	// Fall back to last-used options when neither a flag nor a config default is set
+	var state *config.State
+	if !noRemember {
+		state, _ = config.LoadState()
+	}
+	if state != nil {
+		if service == "" && state.Service != "" {
+			service = state.Service
+		}
+		if !cmd.Flags().Changed("visibility") && state.Visibility != "" {
+			visibility = state.Visibility
 		}
 	}
 
-	// Perform the upload based on service
-	ctx := context.Background()
+	// Determine which service to use
+	service, err = client.ResolveService(service)
+	if err == imgup.ErrAmbiguousService {
+		return errAmbiguousService
+	} else if err == imgup.ErrNotAuthenticated {
+		return fmt.Errorf("Not authenticated. Run 'imgup auth flickr' or 'imgup auth smugmug' first.")
+	} else if err != nil {
+		return err
+	}
+	
+	// Apply Flickr safety/content type defaults and validate them
+	if !cmd.Flags().Changed("safety") && cfg.Flickr.SafetyLevel != "" {
+		safetyLevel = cfg.Flickr.SafetyLevel
+	}
+	if !cmd.Flags().Changed("content-type") && cfg.Flickr.ContentType != "" {
+		contentType = cfg.Flickr.ContentType
+	}
+	if service != "flickr" && (cmd.Flags().Changed("safety") || cmd.Flags().Changed("content-type") || hiddenFromSearch) {
+		warnf("--safety, --content-type and --hidden-from-search only apply to Flickr uploads")
+	}
+	if safetyLevel != "" {
+		if err := backends.ValidateFlickrSafetyLevel(safetyLevel); err != nil {
+			return err
+		}
+	}
+	if contentType != "" {
+		if err := backends.ValidateFlickrContentType(contentType); err != nil {
+			return err
+		}
+	}
+	
+	// Apply the SmugMug privacy default and validate it
+	if !cmd.Flags().Changed("smugmug-privacy") && cfg.SmugMug.Privacy != "" {
+		smugmugPrivacy = cfg.SmugMug.Privacy
+	}
+	if service != "smugmug" && cmd.Flags().Changed("smugmug-privacy") {
+		warnf("--smugmug-privacy only applies to SmugMug uploads")
+	}
+	if service == "smugmug" && smugmugPrivacy != "" {
+		if err := backends.ValidateSmugMugPrivacy(smugmugPrivacy); err != nil {
+			return err
+		}
+	}
 	
-	// Calculate MD5 for the file (used for machine tags and caching)
-	fileInfo, err := duplicate.GetFileInfo(imagePath)
+	// Check authentication for specified service
+	if err := client.CheckAuth(service); err != nil {
+		return err
+	}
+
+	req := &imgup.UploadRequest{
+		Path:             imagePath,
+		Title:            title,
+		Description:      description,
+		Alt:              altText,
+		Tags:             tags,
+		Private:          isPrivate,
+		Service:          service,
+		Force:            force,
+		Replace:          replaceUpload,
+		SafetyLevel:      safetyLevel,
+		ContentType:      contentType,
+		HiddenFromSearch: hiddenFromSearch,
+		SmugMugPrivacy:   smugmugPrivacy,
+		Post:             post,
+		Visibility:       visibility,
+		TagPrefix:        tagPrefix,
+		BlueskyAccount:   blueskyAccount,
+		MastodonAccounts: mastodonAccounts,
+		Transcode:        transcode,
+		MinDimension:     minDimension,
+		SkipValidation:   !validateImage,
+		AltRequired:      altRequired,
+		SkipExistingInAlbum: skipExistingInAlbum,
+		Album:            uploadAlbum,
+		DateTaken:        dateTaken,
+		RemoteFilename:   remoteFilename,
+		VerifyUpload:     verifyUpload,
+		CopyEXIFFrom:     copyEXIFFrom,
+		Poll:             poll,
+		NoSocialURL:      noSocialURL,
+		NoText:           noText,
+	}
+	if err := req.CheckNoText(); err != nil {
+		return err
+	}
+
+	// Upload (or find the duplicate); social posting happens after output below
+	ctx := cmd.Context()
+	result, err := client.Upload(ctx, req)
 	if err != nil {
-		// Log warning but continue - upload can still work without MD5
-		fmt.Fprintf(os.Stderr, "Warning: Failed to calculate file hash: %v\n", err)
+		return uploadFailf(err, "Upload failed: %v", err)
 	}
+	photoID := result.PhotoID
+	photoURL := result.URL
+	imageURL := result.ImageURL
+	isDuplicate := result.Duplicate
+	altText = req.Alt // may have been generated by describe.endpoint
 	
-	// Only perform actual upload if not a duplicate
-	if !isDuplicate {
-		// Silent operation - no verbose messages
-		
-		switch service {
-		case "flickr":
-			uploader := backends.NewFlickrUploader(
-				cfg.Flickr.ConsumerKey,
-				cfg.Flickr.ConsumerSecret,
-				cfg.Flickr.AccessToken,
-				cfg.Flickr.AccessSecret,
-			)
-			result, err := uploader.Upload(ctx, imagePath, title, description, tags, isPrivate)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Upload failed: %v\n", err)
-				os.Exit(1)
-			}
-			photoID = result.PhotoID
-			photoURL = result.URL
-			imageURL = result.ImageURL
-			
-			// Print warnings to stderr unless in JSON mode
-			if len(result.Warnings) > 0 && outputFormat != "json" {
-				for _, warning := range result.Warnings {
-					fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
-				}
-			}
-			
-		case "smugmug":
-			uploader := backends.NewSmugMugUploader(
-				cfg.SmugMug.ConsumerKey,
-				cfg.SmugMug.ConsumerSecret,
-				cfg.SmugMug.AccessToken,
-				cfg.SmugMug.AccessSecret,
-				cfg.SmugMug.AlbumID,
-			)
-			result, err := uploader.Upload(ctx, imagePath, title, description, tags, isPrivate)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Upload failed: %v\n", err)
-				os.Exit(1)
-			}
-			photoID = result.ImageKey
-			photoURL = result.URL
-			imageURL = result.ImageURL
-		}
-
-		// Always record successful upload in cache for future duplicate detection
-		// Reuse the fileInfo we calculated earlier
-		if fileInfo != nil {
-			// Create cache and record the upload
-			cache, err := duplicate.NewSQLiteCache(duplicate.DefaultCachePath())
-			if err == nil {
-				defer cache.Close()
-				
-				upload := &duplicate.Upload{
-					FileMD5:    fileInfo.MD5,
-					Service:    service,
-					RemoteID:   photoID,
-					RemoteURL:  photoURL,
-					ImageURL:   imageURL,
-					UploadTime: time.Now(),
-					Filename:   filepath.Base(imagePath),
-					FileSize:   fileInfo.Size,
-				}
-				
-				if err := cache.Record(upload); err != nil {
-					// Log error but don't fail the upload
-					fmt.Fprintf(os.Stderr, "Warning: Failed to cache upload: %v\n", err)
-				}
-			}
+	if isDuplicate && os.Getenv("IMGUP_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "DEBUG: Duplicate detected!\n")
+		fmt.Fprintf(os.Stderr, "  RemoteID: %s\n", photoID)
+		fmt.Fprintf(os.Stderr, "  RemoteURL: %s\n", photoURL)
+		fmt.Fprintf(os.Stderr, "  ImageURL: %s\n", imageURL)
+	}
+	
+	// Print warnings to stderr unless in JSON mode
+	if len(result.Warnings) > 0 && outputFormat != "json" {
+		for _, warning := range result.Warnings {
+			warnf("%s", warning)
+		}
+	}
+	
+	// Remember this session's choices for the next invocation
+	if state != nil {
+		rememberedVisibility := ""
+		if postToMastodon {
+			rememberedVisibility = visibility
+		}
+		state.Remember(service, client.UploadAlbumName(service, req), rememberedVisibility)
+		if err := state.Save(); err != nil && os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Failed to save last-used state: %v\n", err)
 		}
 	}
 
@@ -708,21 +988,15 @@ func uploadCommand(cmd *cobra.Command, args []string) {
 			"url":       photoURL,
 			"imageUrl":  imageURL,
 			"photoId":   photoID,
+			"service":   service,
 		}
 		jsonBytes, _ := json.MarshalIndent(jsonOutput, "", "  ")
 		fmt.Println(string(jsonBytes))
 	} else {
 		// Normal output using templates
-		template, exists := cfg.Templates[outputFormat]
-		if !exists {
-			fmt.Fprintf(os.Stderr, "Unknown format: %s\n", outputFormat)
-			fmt.Fprintf(os.Stderr, "Available formats: ")
-			var formats []string
-			for k := range cfg.Templates {
-				formats = append(formats, k)
-			}
-			fmt.Fprintf(os.Stderr, "%s\n", strings.Join(formats, ", "))
-			os.Exit(1)
+		template, err := outputTemplate(cfg, outputFormat)
+		if err != nil {
+			return err
 		}
 		
 		if os.Getenv("IMGUP_DEBUG") != "" {
@@ -730,15 +1004,8 @@ func uploadCommand(cmd *cobra.Command, args []string) {
 		}
 
 		// Build template variables
-		filename := filepath.Base(imagePath)
-		filenameNoExt := strings.TrimSuffix(filename, filepath.Ext(filename))
-		
-		// Build edit URL based on service
-		editURL := ""
-		if service == "flickr" {
-			editURL = "https://www.flickr.com/photos/upload/edit/?ids=" + photoID
-		}
-		// SmugMug doesn't have a direct edit URL pattern we can construct
+		filenameNoExt := snippetFilename(cfg, imagePath)
+		editURL := render.EditURL(service, photoID)
 		
 		// Debug output
 		if os.Getenv("IMGUP_DEBUG") != "" {
@@ -768,11 +1035,14 @@ func uploadCommand(cmd *cobra.Command, args []string) {
 		// Process and output
 		output := templates.Process(template, vars)
 		fmt.Println(output)
+		appendOutput(cfg, output)
+		copySnippet(cfg, output, vars)
 	}
 
 	// Warn if using direct visibility with Bluesky
 	if postToBluesky && visibility == "direct" {
-		fmt.Fprintf(os.Stderr, "\nWarning: Bluesky does not support private posts. Your post will be PUBLIC on Bluesky.\n")
+		fmt.Fprintln(os.Stderr)
+		warnf("Bluesky does not support private posts. Your post will be PUBLIC on Bluesky.")
 		if !dryRun {
 			fmt.Fprintf(os.Stderr, "Use --dry-run to test without posting, or create a test account for safe testing.\n\n")
 		}
@@ -780,23 +1050,33 @@ func uploadCommand(cmd *cobra.Command, args []string) {
 	
 	// Post to Mastodon if requested
 	if postToMastodon && !dryRun {
-		if err := postToMastodonService(cfg, service, photoID, photoURL, title, description, altText, tags); err != nil {
-			fmt.Fprintf(os.Stderr, "Mastodon post failed: %v\n", err)
-			// Don't exit - the upload was successful
-		} else {
-			fmt.Println("Posted to Mastodon successfully!")
+		for _, social := range client.PostToMastodon(ctx, req, result) {
+			for _, warning := range social.Warnings {
+				warnf("%s: %s", socialLabel(social), warning)
+			}
+			if social.Error != nil {
+				fmt.Fprintf(os.Stderr, "%s post failed: %v\n", socialLabel(social), social.Error)
+				// Don't exit - the upload was successful; queue the post for 'imgup retry-social'
+				if err := client.QueueSocialRetry(req, result, social); err != nil {
+					warnf("Failed to queue post for retry: %v", err)
+				} else {
+					fmt.Fprintf(os.Stderr, "Queued for retry. Run 'imgup retry-social' to try again.\n")
+				}
+			} else {
+				fmt.Println(term.Success(os.Stdout, fmt.Sprintf("Posted to %s successfully!", socialLabel(social))))
+			}
 		}
 	} else if postToMastodon && dryRun {
 		fmt.Printf("\n[DRY RUN] Would post to Mastodon:\n")
-		fmt.Printf("  Visibility: %s\n", visibility)
-		statusText := post
-		if statusText == "" && title != "" {
-			statusText = title
+		if len(mastodonAccounts) > 0 {
+			fmt.Printf("  Accounts: %s\n", strings.Join(mastodonAccounts, ", "))
 		}
-		statusText += "\n\n" + photoURL
+		fmt.Printf("  Visibility: %s\n", visibility)
+		statusText := client.StatusText(req, photoURL)
 		fmt.Printf("  Text: %s\n", statusText)
-		if len(tags) > 0 {
-			fmt.Printf("  Tags: %v\n", tags)
+		fmt.Printf("  Alt: %s\n", client.SocialAltText(req))
+		if missing := hashtags.Missing(statusText, req.SocialTags(), tagPrefix, cfg.Default.HashtagStyle); len(missing) > 0 {
+			fmt.Printf("  Hashtags: %s\n", strings.Join(missing, " "))
 		}
 	}
 	
@@ -805,27 +1085,31 @@ func uploadCommand(cmd *cobra.Command, args []string) {
 		if os.Getenv("IMGUP_DEBUG") != "" {
 			fmt.Fprintf(os.Stderr, "DEBUG: Starting Bluesky post with photoID=%s, service=%s\n", photoID, service)
 		}
-		if err := postToBlueskyService(cfg, service, photoID, photoURL, title, description, altText, tags); err != nil {
-			fmt.Fprintf(os.Stderr, "Bluesky post failed: %v\n", err)
-			// Don't exit - the upload was successful
+		social := client.PostToBluesky(ctx, req, result)
+		for _, warning := range social.Warnings {
+			warnf("%s", warning)
+		}
+		if social.Error != nil {
+			fmt.Fprintf(os.Stderr, "Bluesky post failed: %v\n", social.Error)
+			// Don't exit - the upload was successful; queue the post for 'imgup retry-social'
+			if err := client.QueueSocialRetry(req, result, social); err != nil {
+				warnf("Failed to queue post for retry: %v", err)
+			} else {
+				fmt.Fprintf(os.Stderr, "Queued for retry. Run 'imgup retry-social' to try again.\n")
+			}
 		} else {
-			fmt.Println("Posted to Bluesky successfully!")
+			fmt.Println(term.Success(os.Stdout, "Posted to Bluesky successfully!"))
 		}
 	} else if postToBluesky && dryRun {
 		fmt.Printf("\n[DRY RUN] Would post to Bluesky:\n")
-		fmt.Printf("  Visibility: PUBLIC (all Bluesky posts are public)\n")
-		statusText := post
-		if statusText == "" && title != "" {
-			statusText = title
+		if account, err := cfg.Bluesky.Account(blueskyAccount); err == nil && account.Handle != "" {
+			fmt.Printf("  Account: @%s\n", account.Handle)
 		}
-		statusText += "\n\n" + photoURL
+		fmt.Printf("  Visibility: PUBLIC (all Bluesky posts are public)\n")
+		statusText := client.StatusText(req, photoURL)
+		fmt.Printf("  Alt: %s\n", client.SocialAltText(req))
 		// Add hashtags
-		for _, tag := range tags {
-			hashtag := "#" + strings.ReplaceAll(tag, " ", "")
-			if !strings.Contains(statusText, hashtag) {
-				statusText += " " + hashtag
-			}
-		}
+		statusText = hashtags.Append(statusText, req.SocialTags(), tagPrefix, cfg.Default.HashtagStyle)
 		fmt.Printf("  Text (%d chars): %s\n", len(statusText), statusText)
 		if len(statusText) > 300 {
 			fmt.Printf("  WARNING: Text exceeds Bluesky's 300 character limit!\n")
@@ -837,6 +1121,17 @@ func uploadCommand(cmd *cobra.Command, args []string) {
 		fmt.Fprintf(os.Stderr, "\nTip: Use --alt to provide descriptive alt text for better accessibility.\n")
 		fmt.Fprintf(os.Stderr, "Example: --alt \"Person standing on mountain peak at sunset\"\n")
 	}
+	return nil
+}
+
+// printJSONSchema prints a JSON Schema to stdout
+func printJSONSchema(schema map[string]interface{}) error {
+	output, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	fmt.Println(string(output))
+	return nil
 }
 
 func handleJSONUpload(cmd *cobra.Command) error {
@@ -856,6 +1151,12 @@ func handleJSONUpload(cmd *cobra.Command) error {
 		if err != nil {
 			return fmt.Errorf("failed to read file %s: %w", jsonFile, err)
 		}
+	} else if uploadDir != "" {
+		// Build a batch from the directory
+		input, err = dirBatchInput(cmd.Context())
+		if err != nil {
+			return err
+		}
 	} else {
 		return fmt.Errorf("no JSON input specified")
 	}
@@ -878,43 +1179,118 @@ func handleJSONUpload(cmd *cobra.Command) error {
 		}
 	}
 	
+	// Check a Mastodon poll before uploading anything
+	if request.Social != nil && request.Social.Mastodon != nil && request.Social.Mastodon.Poll != nil {
+		if _, err := batchPoll(request.Social.Mastodon.Poll); err != nil {
+			return err
+		}
+	}
+	if noText && request.Social != nil {
+		if (request.Social.Mastodon != nil && (request.Social.Mastodon.Post != "" || request.Social.Mastodon.Poll != nil)) ||
+			(request.Social.Bluesky != nil && request.Social.Bluesky.Post != "") {
+			return fmt.Errorf("--no-text posts only the images, so the batch can't have post text or a poll")
+		}
+	}
+	
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	applyDuplicatePreference(cfg)
+	if !cmd.Flags().Changed("min-dimension") {
+		minDimension = cfg.Default.MinDimension
+	}
+	if !cmd.Flags().Changed("validate") {
+		validateImage = cfg.ValidateImages()
+	}
+	if !cmd.Flags().Changed("alt-required") {
+		altRequired = cfg.Default.AltRequired
+	}
+	
+	formats, err := batchFormats(cfg, request)
+	if err != nil {
+		return err
+	}
 	
 	// Apply options from JSON
 	if request.Options != nil {
 		if request.Options.Force {
 			force = true
 		}
+		if request.Options.Replace {
+			replaceUpload = true
+		}
+		if request.Options.SkipExistingInAlbum {
+			skipExistingInAlbum = true
+		}
 		if request.Options.DryRun {
 			dryRun = true
 		}
+		if request.Options.VerifyUpload {
+			verifyUpload = true
+		}
 	}
 	
 	// Determine service
-	service := determineService(cfg, request.Common)
-	if service == "" {
-		return fmt.Errorf("no upload service configured. Run 'imgup auth flickr' or 'imgup auth smugmug' first")
+	client := imgup.New(cfg)
+	requestedService := ""
+	if request.Common != nil {
+		requestedService = request.Common.Service
+	}
+	service, err := client.ResolveService(requestedService)
+	if err != nil {
+		return err
 	}
 	
 	// Process uploads
-	ctx := context.Background()
+	ctx := cmd.Context()
 	response := &types.BatchUploadResponse{
 		Success: true,
 		Uploads: make([]types.UploadResult, len(request.Images)),
 	}
 	
+	// Record progress in the cache so an interrupted batch can be resumed
+	response.BatchID = duplicate.BatchID(input)
+	if request.Options != nil && request.Options.BatchID != "" {
+		response.BatchID = request.Options.BatchID
+	}
+	if printRequest {
+		if err := printBatchRequest(effectiveBatchRequest(cfg, request, service, response.BatchID)); err != nil {
+			return err
+		}
+	}
+	progress, err := duplicate.OpenDefaultCache()
+	if err != nil {
+		warnf("batch progress won't be recorded: %v", err)
+	} else {
+		defer progress.Close()
+	}
+	
 	// Upload images (could be parallelized in future)
 	var uploadedImages []uploadedImage
 	for i, img := range request.Images {
-		result := uploadSingleImage(ctx, cfg, service, img, request.Common)
+		if img.Title == "" && (titleFromFilename || cfg.Default.TitleFromFilename) {
+			img.Title = textutil.TitleizeWith(displayFilename(cfg, uploadName(img.Path, img.RemoteFilename)), cfg.Default.TitleCleanup)
+		}
+		result := uploadBatchImage(ctx, client, progress, response.BatchID, service, img, request.Common)
+		if img.Alt == "" {
+			img.Alt = result.Alt
+		}
+		if lintAlt || cfg.Default.LintAlt {
+			result.Warnings = append(result.Warnings, altLinter(cfg).Lint(img.Alt, img.Title)...)
+		}
+		if formats != nil && result.Error == nil {
+			result.Rendered = renderBatchUpload(cfg, service, formats[i], img, request.Common, result)
+			appendOutput(cfg, result.Rendered)
+		}
 		response.Uploads[i] = result
 		
 		if result.Error == nil {
 			uploadedImages = append(uploadedImages, uploadedImage{
+				Index:    i + 1,
+				Path:     img.Path,
+				Service:  service,
 				URL:      result.URL,
 				ImageURL: result.ImageURL,
 				PhotoID:  result.PhotoID,
@@ -928,11 +1304,13 @@ func handleJSONUpload(cmd *cobra.Command) error {
 	// Handle social media posting if at least one image uploaded successfully
 	if len(uploadedImages) > 0 && request.Social != nil && !dryRun {
 		response.Social = &types.SocialPostResults{}
+		resolveImageURLs(ctx, client, uploadedImages, response.Uploads)
 		
 		// Post to Mastodon
 		if request.Social.Mastodon != nil && request.Social.Mastodon.Enabled {
-			mastodonResult := postToMastodonBatch(cfg, uploadedImages, request.Social.Mastodon)
+			mastodonResult, accountResults := postToMastodonAccountsBatch(cfg, uploadedImages, request.Social.Mastodon)
 			response.Social.Mastodon = &mastodonResult
+			response.Social.MastodonAccounts = accountResults
 		}
 		
 		// Post to Bluesky
@@ -942,6 +1320,11 @@ func handleJSONUpload(cmd *cobra.Command) error {
 		}
 	}
 	
+	response.Summary = summarizeBatch(response.Uploads)
+	if term.IsTerminal(os.Stderr) {
+		fmt.Fprintln(os.Stderr, colorBatchSummary(response.Summary))
+	}
+	
 	// Output JSON response
 	output, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
@@ -949,189 +1332,269 @@ func handleJSONUpload(cmd *cobra.Command) error {
 	}
 	fmt.Println(string(output))
 	
+	// Nothing went through and every failure may go away on another run
+	if len(uploadedImages) == 0 && allRetryable(response.Uploads) {
+		return errUploadsFailed
+	}
 	return nil
 }
 
 // Helper struct for passing uploaded image data
 type uploadedImage struct {
+	Index    int    // position in the batch, counting from 1
+	Path     string
+	Service  string
 	URL      string
 	ImageURL string
 	PhotoID  string
 	Alt      string
 }
 
-// determineService figures out which service to use based on config and request
-func determineService(cfg *config.Config, common *types.CommonSettings) string {
-	// Check if service specified in request
-	if common != nil && common.Service != "" {
-		return common.Service
+// resolveImageURLs asks the service for an image URL for each upload that
+// came back without one, so it can still be attached to the batch's posts.
+// A failed lookup is added to the upload's warnings.
+func resolveImageURLs(ctx context.Context, client *imgup.Client, images []uploadedImage, uploads []types.UploadResult) {
+	for i := range images {
+		img := &images[i]
+		if img.ImageURL != "" || img.PhotoID == "" {
+			continue
+		}
+		upload := &uploads[img.Index-1]
+		imageURL, err := client.SocialImageURL(ctx, img.Service, img.PhotoID)
+		if err != nil {
+			upload.Warnings = append(upload.Warnings, fmt.Sprintf("No image URL to attach to social posts: %v", err))
+			continue
+		}
+		img.ImageURL = imageURL
+		upload.ImageURL = imageURL
 	}
-	
-	// Use default from config
-	if cfg.Default.Service != "" {
-		return cfg.Default.Service
+}
+
+// checkPostMedia makes sure every uploaded image can be attached to a batch
+// post, so the post's media follow the batch's order with none left out.
+// The error names each image without an image URL.
+func checkPostMedia(images []uploadedImage) error {
+	var missing []string
+	for _, img := range images {
+		if img.ImageURL == "" {
+			missing = append(missing, fmt.Sprintf("%d (%s)", img.Index, filepath.Base(img.Path)))
+		}
+	}
+	switch len(missing) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("image %s has no image URL to attach, so nothing was posted", missing[0])
+	default:
+		return fmt.Errorf("images %s have no image URL to attach, so nothing was posted", strings.Join(missing, ", "))
+	}
+}
+
+// uploadBatchImage uploads one image of a batch and records it in the batch's
+// progress. With --resume, an image the batch already uploaded is skipped
+// using the local cache alone.
+func uploadBatchImage(ctx context.Context, client *imgup.Client, progress *duplicate.SQLiteCache, batchID, service string, img types.ImageUpload, common *types.CommonSettings) types.UploadResult {
+	if progress == nil {
+		return uploadSingleImage(ctx, client, service, img, common)
 	}
 	
-	// Auto-detect based on what's configured
-	hasFlickr := cfg.Flickr.AccessToken != "" && cfg.Flickr.AccessSecret != ""
-	hasSmugMug := cfg.SmugMug.AccessToken != "" && cfg.SmugMug.AccessSecret != ""
-	
-	if hasFlickr && !hasSmugMug {
-		return "flickr"
-	} else if hasSmugMug && !hasFlickr {
-		return "smugmug"
+	fileMD5, err := duplicate.CalculateFileMD5(img.Path)
+	if err != nil {
+		return uploadSingleImage(ctx, client, service, img, common)
+	}
+	
+	if resumeBatch {
+		done, err := progress.BatchItem(ctx, batchID, fileMD5, service)
+		if err == nil && done != nil {
+			return types.UploadResult{
+				Path:     img.Path,
+				URL:      done.RemoteURL,
+				ImageURL: done.ImageURL,
+				PhotoID:  done.RemoteID,
+				Resumed:  true,
+			}
+		}
 	}
 	
-	return "" // Both or neither configured
+	result := uploadSingleImage(ctx, client, service, img, common)
+	if result.Error == nil {
+		err := progress.RecordBatchItem(&duplicate.BatchItem{
+			BatchID:   batchID,
+			FileMD5:   fileMD5,
+			Service:   service,
+			RemoteID:  result.PhotoID,
+			RemoteURL: result.URL,
+			ImageURL:  result.ImageURL,
+		})
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to record batch progress: %v", err))
+		}
+	}
+	return result
 }
 
 // uploadSingleImage handles uploading a single image and returns the result
-func uploadSingleImage(ctx context.Context, cfg *config.Config, service string, img types.ImageUpload, common *types.CommonSettings) types.UploadResult {
+func uploadSingleImage(ctx context.Context, client *imgup.Client, service string, img types.ImageUpload, common *types.CommonSettings) types.UploadResult {
 	result := types.UploadResult{
 		Path: img.Path,
 	}
 	
+	req := &imgup.UploadRequest{
+		Path:        img.Path,
+		Title:       img.Title,
+		Description: img.Description,
+		Alt:         img.Alt,
+		Service:     service,
+		Force:       force,
+		Replace:     replaceUpload,
+		Transcode:   transcode,
+		MinDimension: minDimension,
+		SkipValidation: !validateImage,
+		AltRequired:    altRequired,
+		SkipExistingInAlbum: skipExistingInAlbum,
+		Album:       uploadAlbum,
+		DateTaken:   dateTaken,
+		RemoteFilename: img.RemoteFilename,
+		VerifyUpload: verifyUpload,
+		CopyEXIFFrom: copyEXIFFrom,
+	}
+	if img.DateTaken != "" {
+		req.DateTaken = img.DateTaken
+	}
+	
 	// Merge tags from image and common settings
-	var tags []string
 	if len(img.Tags) > 0 {
-		tags = append(tags, img.Tags...)
-	}
-	if common != nil && len(common.Tags) > 0 {
-		tags = append(tags, common.Tags...)
+		req.Tags = append(req.Tags, img.Tags...)
 	}
 	
-	// Check private setting
-	isPrivate := false
+	// Flickr defaults from config, overridden by common settings
+	req.SafetyLevel = client.Config().Flickr.SafetyLevel
+	req.ContentType = client.Config().Flickr.ContentType
+	req.SmugMugPrivacy = client.Config().SmugMug.Privacy
 	if common != nil {
-		isPrivate = common.Private
-	}
-	
-	// Check for duplicates first
-	if !force && cfg.IsDuplicateCheckEnabled() {
-		isDuplicate, existingUpload := checkForDuplicate(ctx, cfg, service, img.Path)
-		if isDuplicate && existingUpload != nil {
-			result.Duplicate = true
-			result.URL = existingUpload.RemoteURL
-			result.ImageURL = existingUpload.ImageURL
-			result.PhotoID = existingUpload.RemoteID
-			return result
+		req.Tags = append(req.Tags, common.Tags...)
+		req.Private = common.Private
+		req.HiddenFromSearch = common.HiddenFromSearch
+		if common.SafetyLevel != "" {
+			req.SafetyLevel = common.SafetyLevel
+		}
+		if common.ContentType != "" {
+			req.ContentType = common.ContentType
+		}
+		if common.SmugMugPrivacy != "" {
+			req.SmugMugPrivacy = common.SmugMugPrivacy
+		}
+		if common.Transcode != "" {
+			req.Transcode = common.Transcode
+		}
+		if common.MinDimension > 0 {
+			req.MinDimension = common.MinDimension
+		}
+		if common.Album != "" {
+			req.Album = common.Album
+		}
+		if common.CopyEXIFFrom != "" {
+			req.CopyEXIFFrom = common.CopyEXIFFrom
 		}
 	}
-	
-	// Get file info for machine tags
-	fileInfo, _ := duplicate.GetFileInfo(img.Path)
-	
-	// Perform upload based on service
-	switch service {
-	case "flickr":
-		uploader := backends.NewFlickrUploader(
-			cfg.Flickr.ConsumerKey,
-			cfg.Flickr.ConsumerSecret,
-			cfg.Flickr.AccessToken,
-			cfg.Flickr.AccessSecret,
-		)
-		
-		uploadResult, err := uploader.Upload(ctx, img.Path, img.Title, img.Description, tags, isPrivate)
-		if err != nil {
-			errStr := err.Error()
-			result.Error = &errStr
-			return result
-		}
-		
-		result.URL = uploadResult.URL
-		result.ImageURL = uploadResult.ImageURL
-		result.PhotoID = uploadResult.PhotoID
-		result.Warnings = uploadResult.Warnings
-		
-	case "smugmug":
-		uploader := backends.NewSmugMugUploader(
-			cfg.SmugMug.ConsumerKey,
-			cfg.SmugMug.ConsumerSecret,
-			cfg.SmugMug.AccessToken,
-			cfg.SmugMug.AccessSecret,
-			cfg.SmugMug.AlbumID,
-		)
-		
-		uploadResult, err := uploader.Upload(ctx, img.Path, img.Title, img.Description, tags, isPrivate)
-		if err != nil {
-			errStr := err.Error()
-			result.Error = &errStr
-			return result
-		}
-		
-		result.URL = uploadResult.URL
-		result.ImageURL = uploadResult.ImageURL
-		result.PhotoID = uploadResult.ImageKey
-		
-	default:
-		errStr := fmt.Sprintf("unsupported service: %s", service)
+	if img.CopyEXIFFrom != "" {
+		req.CopyEXIFFrom = img.CopyEXIFFrom
+	}
+	if noEmbedMetadata && req.CopyEXIFFrom != "" {
+		errStr := "copy_exif_from writes tags into the uploaded file, which --no-embed-metadata doesn't allow"
 		result.Error = &errStr
 		return result
 	}
+	req.Tags = mergeTags(req.Tags, fileTags)
 	
-	// Record successful upload in cache
-	if fileInfo != nil && result.Error == nil {
-		recordUploadInCache(service, img.Path, result.PhotoID, result.URL, result.ImageURL, fileInfo)
+	uploadResult, err := client.Upload(ctx, req)
+	if err != nil {
+		errStr := err.Error()
+		result.Error = &errStr
+		result.Retryable = imgup.IsTransient(err)
+		return result
 	}
 	
-	return result
-}
-
-// checkForDuplicate checks if an image has already been uploaded
-func checkForDuplicate(ctx context.Context, cfg *config.Config, service string, imagePath string) (bool, *duplicate.Upload) {
-	var checker *duplicate.RemoteChecker
-	var err error
-	
-	switch service {
-	case "flickr":
-		checker, err = duplicate.SetupFlickrDuplicateChecker(&cfg.Flickr)
-	case "smugmug":
-		checker, err = duplicate.SetupSmugMugDuplicateChecker(&cfg.SmugMug)
-	default:
-		return false, nil
+	result.URL = uploadResult.URL
+	result.ImageURL = uploadResult.ImageURL
+	result.PhotoID = uploadResult.PhotoID
+	result.Service = uploadResult.Service
+	result.Duplicate = uploadResult.Duplicate
+	if img.Alt == "" {
+		result.Alt = req.Alt
 	}
-	
-	if err != nil {
-		return false, nil
+	if len(uploadResult.Warnings) > 0 {
+		result.Warnings = uploadResult.Warnings
 	}
-	defer checker.Close()
 	
-	existingUpload, err := checker.Check(ctx, imagePath)
-	if err != nil || existingUpload == nil {
-		return false, nil
+	return result
+}
+
+// batchPoll builds the Mastodon poll for a JSON batch, which defaults to
+// the --poll-duration default of a day
+func batchPoll(settings *types.PollSettings) (*mastodon.Poll, error) {
+	duration := 24 * time.Hour
+	if settings.Duration != "" {
+		d, err := time.ParseDuration(settings.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid poll duration '%s': %w", settings.Duration, err)
+		}
+		duration = d
 	}
-	
-	return true, existingUpload
+	return mastodon.NewPoll(settings.Options, duration)
 }
 
-// recordUploadInCache records a successful upload for future duplicate detection
-func recordUploadInCache(service, imagePath, photoID, photoURL, imageURL string, fileInfo *duplicate.FileInfo) {
-	cache, err := duplicate.NewSQLiteCache(duplicate.DefaultCachePath())
-	if err != nil {
-		return
+// postToMastodonAccountsBatch posts multiple images to each named Mastodon
+// account, or to the main account when none are named. With named accounts,
+// the first result sums up the per-account results that follow it.
+func postToMastodonAccountsBatch(cfg *config.Config, images []uploadedImage, settings *types.MastodonSettings) (types.SocialPostResult, []types.SocialPostResult) {
+	if err := checkPostMedia(images); err != nil {
+		errStr := err.Error()
+		return types.SocialPostResult{Error: &errStr}, nil
+	}
+	names := settings.Accounts
+	if len(names) == 0 {
+		var err error
+		if names, err = mastodonAccountNames(cfg, mastodonAccount); err != nil {
+			errStr := err.Error()
+			return types.SocialPostResult{Error: &errStr}, nil
+		}
 	}
-	defer cache.Close()
-	
-	upload := &duplicate.Upload{
-		FileMD5:    fileInfo.MD5,
-		Service:    service,
-		RemoteID:   photoID,
-		RemoteURL:  photoURL,
-		ImageURL:   imageURL,
-		UploadTime: time.Now(),
-		Filename:   filepath.Base(imagePath),
-		FileSize:   fileInfo.Size,
+	if len(names) == 0 {
+		return postToMastodonBatch(cfg, images, settings, ""), nil
 	}
 	
-	cache.Record(upload)
+	summary := types.SocialPostResult{Success: true}
+	var results []types.SocialPostResult
+	var failures []string
+	for _, name := range names {
+		result := postToMastodonBatch(cfg, images, settings, name)
+		if result.Error != nil {
+			summary.Success = false
+			failures = append(failures, fmt.Sprintf("%s: %s", name, *result.Error))
+		}
+		results = append(results, result)
+	}
+	if len(failures) > 0 {
+		errStr := fmt.Sprintf("%d of %d Mastodon accounts failed (%s)", len(failures), len(names), strings.Join(failures, "; "))
+		summary.Error = &errStr
+	}
+	return summary, results
 }
 
-// postToMastodonBatch posts multiple images to Mastodon
-func postToMastodonBatch(cfg *config.Config, images []uploadedImage, settings *types.MastodonSettings) types.SocialPostResult {
-	result := types.SocialPostResult{}
+// postToMastodonBatch posts multiple images to one Mastodon account; name is
+// empty for the main account
+func postToMastodonBatch(cfg *config.Config, images []uploadedImage, settings *types.MastodonSettings, name string) types.SocialPostResult {
+	result := types.SocialPostResult{Account: name}
 	
 	// Check if Mastodon is configured
-	if cfg.Mastodon.AccessToken == "" {
+	account, err := cfg.Mastodon.Account(name)
+	if err != nil {
+		errStr := err.Error()
+		result.Error = &errStr
+		return result
+	}
+	if !account.Configured() {
 		errStr := "not authenticated with Mastodon"
 		result.Error = &errStr
 		return result
@@ -1139,26 +1602,38 @@ func postToMastodonBatch(cfg *config.Config, images []uploadedImage, settings *t
 	
 	// Create Mastodon client
 	client := mastodon.NewClient(
-		cfg.Mastodon.InstanceURL,
-		cfg.Mastodon.ClientID,
-		cfg.Mastodon.ClientSecret,
-		cfg.Mastodon.AccessToken,
+		account.InstanceURL,
+		account.ClientID,
+		account.ClientSecret,
+		account.AccessToken,
 	)
+	client.TagPrefix = tagPrefix
+	client.HashtagStyle = cfg.Default.HashtagStyle
+	if settings.Poll != nil {
+		poll, err := batchPoll(settings.Poll)
+		if err != nil {
+			errStr := err.Error()
+			result.Error = &errStr
+			return result
+		}
+		client.Poll = poll
+	}
 	
-	// Upload all images to Mastodon and collect media IDs
+	// Upload all images to Mastodon in batch order and collect media IDs;
+	// checkPostMedia has made sure each one has an image URL
 	var mediaIDs []string
 	for _, img := range images {
-		// Get image URL for social posting
-		imageURL := img.ImageURL
-		if imageURL == "" {
-			// Fall back to fetching it based on service
-			// This would need the service info, but for now use what we have
-			continue
+		if os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Attaching image %d (%s) to the Mastodon post\n", img.Index, img.ImageURL)
 		}
-		
-		mediaID, err := client.UploadMediaFromURL(imageURL, img.Alt)
+		if err := imgup.CheckSocialFormat("mastodon", img.ImageURL); err != nil {
+			errStr := fmt.Sprintf("image %d: %v", img.Index, err)
+			result.Error = &errStr
+			return result
+		}
+		mediaID, err := client.UploadMediaFromURL(img.ImageURL, img.Alt)
 		if err != nil {
-			errStr := fmt.Sprintf("failed to upload media: %v", err)
+			errStr := fmt.Sprintf("failed to upload media for image %d: %v", img.Index, err)
 			result.Error = &errStr
 			return result
 		}
@@ -1167,17 +1642,19 @@ func postToMastodonBatch(cfg *config.Config, images []uploadedImage, settings *t
 	
 	// Build status text
 	statusText := settings.Post
-	if statusText == "" {
+	if statusText == "" && !noText {
 		statusText = "Photos uploaded with imgupv2"
 	}
 	
 	// Add URLs of all photos
-	statusText += "\n\n"
-	for i, img := range images {
-		if i > 0 {
-			statusText += "\n"
+	if !noSocialURL && !noText {
+		statusText += cfg.SocialURLSeparator()
+		for i, img := range images {
+			if i > 0 {
+				statusText += "\n"
+			}
+			statusText += img.URL
 		}
-		statusText += img.URL
 	}
 	
 	// Post the status with all media
@@ -1194,7 +1671,7 @@ func postToMastodonBatch(cfg *config.Config, images []uploadedImage, settings *t
 	
 	result.Success = true
 	// TODO: Get the actual Mastodon post URL from the response
-	result.URL = cfg.Mastodon.InstanceURL // Placeholder
+	result.URL = account.InstanceURL // Placeholder
 	
 	return result
 }
@@ -1202,53 +1679,85 @@ func postToMastodonBatch(cfg *config.Config, images []uploadedImage, settings *t
 // postToBlueskyBatch posts multiple images to Bluesky
 func postToBlueskyBatch(cfg *config.Config, images []uploadedImage, settings *types.BlueskySettings) types.SocialPostResult {
 	result := types.SocialPostResult{}
+	if err := checkPostMedia(images); err != nil {
+		errStr := err.Error()
+		result.Error = &errStr
+		return result
+	}
 	
 	// Check if Bluesky is configured
-	if cfg.Bluesky.Handle == "" || cfg.Bluesky.AppPassword == "" {
+	accountName := settings.Account
+	if accountName == "" {
+		accountName = blueskyAccount
+	}
+	account, err := cfg.Bluesky.Account(accountName)
+	if err != nil {
+		errStr := err.Error()
+		result.Error = &errStr
+		return result
+	}
+	if !account.Configured() {
 		errStr := "not authenticated with Bluesky"
 		result.Error = &errStr
 		return result
 	}
 	
 	// Create Bluesky client
-	client := bluesky.NewClient(cfg.Bluesky.PDS, cfg.Bluesky.Handle, cfg.Bluesky.AppPassword)
+	client := bluesky.NewClient(account.PDS, account.Handle, account.AppPassword)
+	client.TagPrefix = tagPrefix
+	client.HashtagStyle = cfg.Default.HashtagStyle
+	client.JPEGQuality = cfg.Default.SocialJPEGQuality
+	client.KeepEXIF = cfg.Default.SocialKeepEXIF
 	
 	// Upload all images to Bluesky and collect blobs
 	var blobs []bluesky.BlobResponse
 	var altTexts []string
 	
 	for _, img := range images {
-		imageURL := img.ImageURL
-		if imageURL == "" {
-			continue
+		if os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Attaching image %d (%s) to the Bluesky post\n", img.Index, img.ImageURL)
 		}
-		
-		blob, _, err := client.UploadMediaFromURL(imageURL, img.Alt)
+		if err := imgup.CheckSocialFormat("bluesky", img.ImageURL); err != nil {
+			errStr := fmt.Sprintf("image %d: %v", img.Index, err)
+			result.Error = &errStr
+			return result
+		}
+		blob, _, err := client.UploadMediaFromURL(img.ImageURL, img.Alt)
 		if err != nil {
-			errStr := fmt.Sprintf("failed to upload media: %v", err)
+			errStr := fmt.Sprintf("failed to upload media for image %d: %v", img.Index, err)
+			result.Error = &errStr
+			return result
+		}
+		if blob == nil {
+			errStr := fmt.Sprintf("failed to upload media for image %d: no blob returned", img.Index)
 			result.Error = &errStr
 			return result
 		}
 		
-		if blob != nil {
-			blobs = append(blobs, *blob)
-			altTexts = append(altTexts, img.Alt)
+		for _, warning := range blob.Warnings {
+			warnf("image %d: %s", img.Index, warning)
 		}
+		
+		// Alt texts stay paired with their blobs
+		blobs = append(blobs, *blob)
+		altTexts = append(altTexts, img.Alt)
 	}
 	
 	// Build status text
 	statusText := settings.Post
-	if statusText == "" {
+	if statusText == "" && !noText {
 		statusText = "Photos uploaded with imgupv2"
 	}
 	
 	// Add URLs
-	statusText += "\n\n"
-	for i, img := range images {
-		if i > 0 {
-			statusText += "\n"
+	if !noSocialURL && !noText {
+		statusText += cfg.SocialURLSeparator()
+		for i, img := range images {
+			if i > 0 {
+				statusText += "\n"
+			}
+			statusText += img.URL
 		}
-		statusText += img.URL
 	}
 	
 	// Check character limit
@@ -1270,18 +1779,65 @@ func postToBlueskyBatch(cfg *config.Config, images []uploadedImage, settings *ty
 	return result
 }
 
-func configShowCommand(cmd *cobra.Command, args []string) {
+func configShowCommand(cmd *cobra.Command, args []string) error {
 	if err := configShow(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		return err
 	}
+	return nil
 }
 
-func configSetCommand(cmd *cobra.Command, args []string) {
+func configSetCommand(cmd *cobra.Command, args []string) error {
 	if err := configSet(args[0], args[1]); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		return err
+	}
+	return nil
+}
+
+func configExportCommand(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	data, err := cfg.Export(exportFormat, includeSecrets)
+	if err != nil {
+		return err
 	}
+	os.Stdout.Write(data)
+	return nil
+}
+
+func configImportCommand(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	format := importFormat
+	if format == "" {
+		format = config.FormatForPath(path)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.Import(data, format); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Println("Imported configuration. Run 'imgup config show' to review it.")
+	return nil
 }
 
 func configShow() error {
@@ -1290,10 +1846,15 @@ func configShow() error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	fmt.Println("Configuration:")
+	fmt.Printf("Configuration (%s):\n", config.Path())
+	fmt.Printf("  Profile: %s\n", config.ActiveProfile())
 	
 	// Show defaults if any are set
-	if cfg.Default.Format != "" || cfg.Default.Service != "" || cfg.Default.DuplicateCheck != nil {
+	if cfg.Default.Format != "" || cfg.Default.Service != "" || cfg.Default.DuplicateCheck != nil ||
+		cfg.Default.LintAlt || cfg.Default.AltMinLength > 0 || cfg.Default.AltRequired || cfg.Default.AppendSeparator != "" || cfg.Default.SocialURLSeparator != "" ||
+		cfg.Default.SocialFallbacks || cfg.Default.FlickrShortURLs || cfg.Default.TitleFromFilename || cfg.Default.SanitizeFilename ||
+		cfg.Default.TitleCleanup != "" || cfg.Default.PruneCacheOnMiss || cfg.Default.MinDimension > 0 || cfg.Default.Validate != nil || cfg.Default.ConvertHEIC != nil || cfg.Default.EmbedMetadata != nil ||
+		cfg.Default.JPEGQuality > 0 || cfg.Default.SocialJPEGQuality > 0 || cfg.Default.SocialKeepEXIF || cfg.Default.HashtagStyle != "" || cfg.Default.UserAgent != "" || cfg.Default.KittyInTmux != "" || cfg.Default.PullPageSize > 0 || cfg.Default.SkipExistingMatch != "" {
 		fmt.Printf("  Default:\n")
 		if cfg.Default.Format != "" {
 			fmt.Printf("    Format: %s\n", cfg.Default.Format)
@@ -1302,6 +1863,78 @@ func configShow() error {
 			fmt.Printf("    Service: %s\n", cfg.Default.Service)
 		}
 		fmt.Printf("    Duplicate Check: %v\n", cfg.IsDuplicateCheckEnabled())
+		if cfg.Default.DuplicatePreference != "" {
+			fmt.Printf("    Duplicate Preference: %s\n", cfg.Default.DuplicatePreference)
+		}
+		if cfg.Default.PruneCacheOnMiss {
+			fmt.Printf("    Prune Cache On Miss: true\n")
+		}
+		if cfg.Default.SkipExistingMatch != "" {
+			fmt.Printf("    Skip Existing Match: %s\n", cfg.Default.SkipExistingMatch)
+		}
+		if cfg.Default.LintAlt {
+			fmt.Printf("    Lint Alt Text: true\n")
+		}
+		if cfg.Default.AltMinLength > 0 {
+			fmt.Printf("    Alt Min Length: %d\n", cfg.Default.AltMinLength)
+		}
+		if cfg.Default.AltRequired {
+			fmt.Printf("    Alt Required: true\n")
+		}
+		if cfg.Default.MinDimension > 0 {
+			fmt.Printf("    Min Dimension: %dpx\n", cfg.Default.MinDimension)
+		}
+		if cfg.Default.Validate != nil {
+			fmt.Printf("    Validate: %v\n", cfg.ValidateImages())
+		}
+		if cfg.Default.ConvertHEIC != nil {
+			fmt.Printf("    Convert HEIC: %v\n", cfg.ConvertHEICImages())
+		}
+		if cfg.Default.EmbedMetadata != nil {
+			fmt.Printf("    Embed Metadata: %v\n", cfg.EmbedMetadataInFiles())
+		}
+		if cfg.Default.JPEGQuality > 0 {
+			fmt.Printf("    JPEG Quality: %d\n", cfg.Default.JPEGQuality)
+		}
+		if cfg.Default.SocialJPEGQuality > 0 {
+			fmt.Printf("    Social JPEG Quality: %d\n", cfg.Default.SocialJPEGQuality)
+		}
+		if cfg.Default.HashtagStyle != "" {
+			fmt.Printf("    Hashtag Style: %s\n", cfg.Default.HashtagStyle)
+		}
+		if cfg.Default.SocialKeepEXIF {
+			fmt.Printf("    Social Keep EXIF: true\n")
+		}
+		if cfg.Default.PullPageSize > 0 {
+			fmt.Printf("    Pull Page Size: %d\n", cfg.Default.PullPageSize)
+		}
+		if cfg.Default.KittyInTmux != "" {
+			fmt.Printf("    Kitty In Tmux: %s\n", cfg.Default.KittyInTmux)
+		}
+		if cfg.Default.SocialFallbacks {
+			fmt.Printf("    Social Fallbacks: true\n")
+		}
+		if cfg.Default.FlickrShortURLs {
+			fmt.Printf("    Flickr Short URLs: true\n")
+		}
+		if cfg.Default.TitleFromFilename {
+			fmt.Printf("    Title From Filename: true\n")
+		}
+		if cfg.Default.TitleCleanup != "" {
+			fmt.Printf("    Title Cleanup: %s\n", cfg.Default.TitleCleanup)
+		}
+		if cfg.Default.SanitizeFilename {
+			fmt.Printf("    Sanitize Filename: true\n")
+		}
+		if cfg.Default.AppendSeparator != "" {
+			fmt.Printf("    Append Separator: %s\n", strings.ReplaceAll(cfg.Default.AppendSeparator, "\n", `\n`))
+		}
+		if cfg.Default.SocialURLSeparator != "" {
+			fmt.Printf("    Social URL Separator: %q\n", cfg.Default.SocialURLSeparator)
+		}
+		if cfg.Default.UserAgent != "" {
+			fmt.Printf("    User Agent: %s\n", cfg.Default.UserAgent)
+		}
 		fmt.Println()
 	}
 	
@@ -1310,12 +1943,26 @@ func configShow() error {
 	fmt.Printf("    Consumer Secret: %s\n", maskString(cfg.Flickr.ConsumerSecret))
 	fmt.Printf("    Access Token: %s\n", maskString(cfg.Flickr.AccessToken))
 	fmt.Printf("    Access Secret: %s\n", maskString(cfg.Flickr.AccessSecret))
+	if cfg.Flickr.SafetyLevel != "" {
+		fmt.Printf("    Safety Level: %s\n", cfg.Flickr.SafetyLevel)
+	}
+	if cfg.Flickr.ContentType != "" {
+		fmt.Printf("    Content Type: %s\n", cfg.Flickr.ContentType)
+	}
+	if cfg.Flickr.UploadAlbum != "" {
+		fmt.Printf("    Upload Album: %s\n", cfg.Flickr.UploadAlbum)
+	}
 
 	fmt.Printf("\n  Mastodon:\n")
 	fmt.Printf("    Instance URL: %s\n", cfg.Mastodon.InstanceURL)
 	fmt.Printf("    Client ID: %s\n", maskString(cfg.Mastodon.ClientID))
 	fmt.Printf("    Client Secret: %s\n", maskString(cfg.Mastodon.ClientSecret))
 	fmt.Printf("    Access Token: %s\n", maskString(cfg.Mastodon.AccessToken))
+	fmt.Printf("    Scopes: %s\n", cfg.Mastodon.RequestedScopes())
+	for _, name := range cfg.Mastodon.AccountNames() {
+		account := cfg.Mastodon.Accounts[name]
+		fmt.Printf("    Account %s: %s, Access Token: %s\n", name, account.InstanceURL, maskString(account.AccessToken))
+	}
 	
 	fmt.Printf("\n  Bluesky:\n")
 	fmt.Printf("    Handle: %s\n", cfg.Bluesky.Handle)
@@ -1325,6 +1972,14 @@ func configShow() error {
 		pds = "https://bsky.social (default)"
 	}
 	fmt.Printf("    PDS: %s\n", pds)
+	for _, name := range cfg.Bluesky.AccountNames() {
+		account := cfg.Bluesky.Accounts[name]
+		accountPDS := account.PDS
+		if accountPDS == "" {
+			accountPDS = "https://bsky.social (default)"
+		}
+		fmt.Printf("    Account %s: @%s on %s, App Password: %s\n", name, account.Handle, accountPDS, maskString(account.AppPassword))
+	}
 
 	fmt.Printf("\n  SmugMug:\n")
 	fmt.Printf("    Consumer Key: %s\n", maskString(cfg.SmugMug.ConsumerKey))
@@ -1332,11 +1987,39 @@ func configShow() error {
 	fmt.Printf("    Access Token: %s\n", maskString(cfg.SmugMug.AccessToken))
 	fmt.Printf("    Access Secret: %s\n", maskString(cfg.SmugMug.AccessSecret))
 	fmt.Printf("    Album ID: %s\n", cfg.SmugMug.AlbumID)
+	if cfg.SmugMug.UploadAlbum != "" {
+		fmt.Printf("    Upload Album: %s\n", cfg.SmugMug.UploadAlbum)
+	}
+	if cfg.SmugMug.PullAlbum != "" {
+		fmt.Printf("    Pull Album: %s\n", cfg.SmugMug.PullAlbum)
+	}
+	if cfg.SmugMug.CreatePullAlbum {
+		fmt.Printf("    Create Pull Album: yes\n")
+	}
+	if cfg.SmugMug.Privacy != "" {
+		fmt.Printf("    Privacy: %s\n", cfg.SmugMug.Privacy)
+	}
 
-	fmt.Printf("\n  Templates:\n")
-	for name, template := range cfg.Templates {
-		// Truncate long templates for display
-		display := template
+	if cfg.Describe.Endpoint != "" {
+		fmt.Printf("\n  Describe:\n")
+		fmt.Printf("    Endpoint: %s\n", cfg.Describe.Endpoint)
+	}
+
+	fmt.Printf("\n  GUI:\n")
+	if cfg.GUI.Path != "" {
+		fmt.Printf("    Path: %s\n", cfg.GUI.Path)
+	} else {
+		fmt.Printf("    Path: (search)\n")
+	}
+	fmt.Printf("    Search: %s\n", strings.Join(cfg.GUI.SearchOrder(), ", "))
+	fmt.Printf("    Upload retries: %d\n", cfg.GUI.UploadRetryCount())
+	fmt.Printf("    Window resize: %s\n", cfg.GUI.ResizeMode())
+
+	fmt.Printf("\n  Templates (use with --format):\n")
+	for _, name := range templateNames(cfg) {
+		template := cfg.Templates[name]
+		// Show multiline templates on one line and truncate long ones for display
+		display := strings.ReplaceAll(template, "\n", `\n`)
 		if len(display) > 60 {
 			display = display[:57] + "..."
 		}
@@ -1361,22 +2044,216 @@ func configSet(key, value string) error {
 		// Parse boolean value
 		boolValue := value == "true" || value == "yes" || value == "on" || value == "1"
 		cfg.Default.DuplicateCheck = &boolValue
+	case key == "default.duplicate_preference":
+		if value != "cache" && value != "remote" {
+			return fmt.Errorf("invalid duplicate preference '%s'. Must be 'cache' or 'remote'", value)
+		}
+		cfg.Default.DuplicatePreference = value
+	case key == "default.hashtag_style":
+		if value == "" || !hashtags.ValidStyle(value) {
+			return fmt.Errorf("invalid hashtag style '%s'. Must be one of: %s", value, strings.Join(hashtags.Styles, ", "))
+		}
+		cfg.Default.HashtagStyle = value
+	case key == "default.kitty_in_tmux":
+		if value != "text" && value != "passthrough" {
+			return fmt.Errorf("invalid kitty_in_tmux '%s'. Must be 'text' or 'passthrough'", value)
+		}
+		cfg.Default.KittyInTmux = value
+	case key == "default.prune_cache_on_miss":
+		cfg.Default.PruneCacheOnMiss = value == "true" || value == "yes" || value == "on" || value == "1"
+	case key == "default.lint_alt":
+		cfg.Default.LintAlt = value == "true" || value == "yes" || value == "on" || value == "1"
+	case key == "default.alt_required":
+		cfg.Default.AltRequired = value == "true" || value == "yes" || value == "on" || value == "1"
+	case key == "default.alt_min_length":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid alt_min_length '%s'. Must be a positive number", value)
+		}
+		cfg.Default.AltMinLength = n
+	case key == "default.pull_page_size":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid pull_page_size '%s'. Must be 0 (list everything) or more", value)
+		}
+		cfg.Default.PullPageSize = n
+	case key == "default.min_dimension":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid min_dimension '%s'. Must be a number of pixels (0 to disable)", value)
+		}
+		cfg.Default.MinDimension = n
+	case key == "default.skip_existing_match":
+		if err := imgup.ValidateAlbumMatchField(value); err != nil {
+			return err
+		}
+		cfg.Default.SkipExistingMatch = value
+	case key == "default.validate":
+		enabled := value == "true" || value == "yes" || value == "on" || value == "1"
+		cfg.Default.Validate = &enabled
+	case key == "default.convert_heic":
+		enabled := value == "true" || value == "yes" || value == "on" || value == "1"
+		cfg.Default.ConvertHEIC = &enabled
+	case key == "default.embed_metadata":
+		enabled := value == "true" || value == "yes" || value == "on" || value == "1"
+		cfg.Default.EmbedMetadata = &enabled
+	case key == "default.jpeg_quality" || key == "default.social_jpeg_quality":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s '%s'. Must be a number from %d to %d (0 for the default)", strings.TrimPrefix(key, "default."), value, imageproc.MinJPEGQuality, imageproc.MaxJPEGQuality)
+		}
+		if err := imageproc.ValidateJPEGQuality(n); err != nil {
+			return err
+		}
+		if key == "default.jpeg_quality" {
+			cfg.Default.JPEGQuality = n
+		} else {
+			cfg.Default.SocialJPEGQuality = n
+		}
+	case key == "default.user_agent":
+		cfg.Default.UserAgent = value
+	case key == "default.social_keep_exif":
+		cfg.Default.SocialKeepEXIF = value == "true" || value == "yes" || value == "on" || value == "1"
+	case key == "default.title_from_filename":
+		cfg.Default.TitleFromFilename = value == "true" || value == "yes" || value == "on" || value == "1"
+	case key == "default.sanitize_filename":
+		cfg.Default.SanitizeFilename = value == "true" || value == "yes" || value == "on" || value == "1"
+	case key == "default.title_cleanup":
+		if value != textutil.CleanupFull && value != textutil.CleanupSpaces && value != textutil.CleanupNone {
+			return fmt.Errorf("invalid title cleanup '%s'. Must be 'full', 'spaces' or 'none'", value)
+		}
+		cfg.Default.TitleCleanup = value
+	case key == "default.flickr_short_urls":
+		cfg.Default.FlickrShortURLs = value == "true" || value == "yes" || value == "on" || value == "1"
+	case key == "default.social_fallbacks":
+		cfg.Default.SocialFallbacks = value == "true" || value == "yes" || value == "on" || value == "1"
+	case key == "default.append_separator":
+		cfg.Default.AppendSeparator = strings.ReplaceAll(value, `\n`, "\n")
+	case key == "default.social_url_separator":
+		cfg.Default.SocialURLSeparator = strings.ReplaceAll(value, `\n`, "\n")
 	case key == "flickr.key":
 		cfg.Flickr.ConsumerKey = value
 	case key == "flickr.secret":
 		cfg.Flickr.ConsumerSecret = value
+	case key == "flickr.safety_level":
+		if err := backends.ValidateFlickrSafetyLevel(value); err != nil {
+			return err
+		}
+		cfg.Flickr.SafetyLevel = value
+	case key == "flickr.content_type":
+		if err := backends.ValidateFlickrContentType(value); err != nil {
+			return err
+		}
+		cfg.Flickr.ContentType = value
+	case key == "flickr.upload_album":
+		cfg.Flickr.UploadAlbum = value
+	case key == "mastodon.scopes":
+		// Stored as given; 'imgup auth mastodon' re-registers the app when scopes change
+		cfg.Mastodon.Scopes = value
+	case key == "gui.path":
+		// An empty value goes back to searching
+		cfg.GUI.Path = value
+	case key == "gui.upload_retries":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid upload_retries '%s'. Must be 0 (no retries) or more", value)
+		}
+		cfg.GUI.UploadRetries = &n
+	case key == "gui.window_resize":
+		valid := false
+		for _, mode := range config.WindowResizeModes {
+			valid = valid || value == mode
+		}
+		if !valid {
+			return fmt.Errorf("invalid window_resize '%s'. Must be one of: %s", value, strings.Join(config.WindowResizeModes, ", "))
+		}
+		cfg.GUI.WindowResize = value
+	case key == "gui.search":
+		var sources []string
+		for _, source := range strings.Split(value, ",") {
+			source = strings.TrimSpace(source)
+			if source == "" {
+				continue
+			}
+			valid := false
+			for _, known := range config.GUISearchSources {
+				valid = valid || source == known
+			}
+			if !valid {
+				return fmt.Errorf("invalid gui.search source '%s'. Must be a comma-separated list of: %s", source, strings.Join(config.GUISearchSources, ", "))
+			}
+			sources = append(sources, source)
+		}
+		cfg.GUI.Search = sources
+	case key == "describe.endpoint":
+		// An empty value turns alt text generation off
+		if value != "" && !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+			return fmt.Errorf("invalid describe endpoint '%s'. Must be an http:// or https:// URL", value)
+		}
+		cfg.Describe.Endpoint = value
 	case key == "mastodon.instance":
 		cfg.Mastodon.InstanceURL = value
 	case key == "mastodon.client_id":
 		cfg.Mastodon.ClientID = value
 	case key == "mastodon.client_secret":
 		cfg.Mastodon.ClientSecret = value
+	case strings.HasPrefix(key, "mastodon.accounts."):
+		// mastodon.accounts.<name>.<instance|access_token>
+		parts := strings.Split(strings.TrimPrefix(key, "mastodon.accounts."), ".")
+		if len(parts) != 2 || parts[0] == "" {
+			return fmt.Errorf("invalid key '%s'. Use mastodon.accounts.<name>.instance or .access_token", key)
+		}
+		if cfg.Mastodon.Accounts == nil {
+			cfg.Mastodon.Accounts = make(map[string]config.MastodonAccount)
+		}
+		account := cfg.Mastodon.Accounts[parts[0]]
+		switch parts[1] {
+		case "instance":
+			account.InstanceURL = value
+		case "access_token":
+			account.AccessToken = value
+		default:
+			return fmt.Errorf("invalid key '%s'. Use mastodon.accounts.<name>.instance or .access_token", key)
+		}
+		cfg.Mastodon.Accounts[parts[0]] = account
 	case key == "bluesky.handle":
 		cfg.Bluesky.Handle = value
 	case key == "bluesky.app_password":
 		cfg.Bluesky.AppPassword = value
 	case key == "bluesky.pds":
 		cfg.Bluesky.PDS = value
+	case strings.HasPrefix(key, "bluesky.accounts."):
+		// bluesky.accounts.<name>.<handle|app_password|pds>
+		parts := strings.Split(strings.TrimPrefix(key, "bluesky.accounts."), ".")
+		if len(parts) != 2 || parts[0] == "" {
+			return fmt.Errorf("invalid key '%s'. Use bluesky.accounts.<name>.handle, .app_password or .pds", key)
+		}
+		if cfg.Bluesky.Accounts == nil {
+			cfg.Bluesky.Accounts = make(map[string]config.BlueskyAccount)
+		}
+		account := cfg.Bluesky.Accounts[parts[0]]
+		switch parts[1] {
+		case "handle":
+			account.Handle = value
+		case "app_password":
+			account.AppPassword = value
+		case "pds":
+			account.PDS = value
+		default:
+			return fmt.Errorf("invalid key '%s'. Use bluesky.accounts.<name>.handle, .app_password or .pds", key)
+		}
+		cfg.Bluesky.Accounts[parts[0]] = account
+	case key == "smugmug.privacy":
+		if err := backends.ValidateSmugMugPrivacy(value); err != nil {
+			return err
+		}
+		cfg.SmugMug.Privacy = strings.ToLower(value)
+	case key == "smugmug.upload_album":
+		cfg.SmugMug.UploadAlbum = value
+	case key == "smugmug.pull_album":
+		cfg.SmugMug.PullAlbum = value
+	case key == "smugmug.create_pull_album":
+		cfg.SmugMug.CreatePullAlbum = value == "true" || value == "yes" || value == "on" || value == "1"
 	case key == "smugmug.key":
 		cfg.SmugMug.ConsumerKey = value
 	case key == "smugmug.secret":
@@ -1387,7 +2264,8 @@ func configSet(key, value string) error {
 		if cfg.Templates == nil {
 			cfg.Templates = make(map[string]string)
 		}
-		cfg.Templates[templateName] = value
+		// Allow multiline templates (e.g. rst directives) to be set from the shell
+		cfg.Templates[templateName] = strings.ReplaceAll(value, `\n`, "\n")
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
@@ -1400,194 +2278,90 @@ func configSet(key, value string) error {
 	return nil
 }
 
-func maskString(s string) string {
-	if s == "" {
-		return "(not set)"
+// applyDuplicatePreference lets --prefer-remote/--prefer-cache override the configured preference.
+// --prune-cache-on-miss needs the remote check, so it implies --prefer-remote.
+func applyDuplicatePreference(cfg *config.Config) {
+	if preferRemote || pruneCacheOnMiss {
+		cfg.Default.DuplicatePreference = "remote"
+	} else if preferCache {
+		cfg.Default.DuplicatePreference = "cache"
 	}
-	if len(s) <= 8 {
-		return "****"
+	if pruneCacheOnMiss {
+		cfg.Default.PruneCacheOnMiss = true
 	}
-	return s[:4] + "****" + s[len(s)-4:]
 }
 
-// getMapKeys is a helper function to get the keys from a map (for debugging)
-func getMapKeys(m map[string]interface{}) []string {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
+// outputTemplate returns the template text for a format: the --template-file
+// contents when given, otherwise the configured template, read from disk
+// when it's an @file reference
+func outputTemplate(cfg *config.Config, format string) (string, error) {
+	if templateFile != "" {
+		return templates.LoadFile(templateFile)
+	}
+	template, err := render.Template(cfg, format)
+	if errors.Is(err, render.ErrUnknownFormat) {
+		return "", unknownFormatError(cfg, format)
 	}
-	return keys
+	return template, err
 }
 
-func postToMastodonService(cfg *config.Config, service string, photoID string, photoURL string, photoTitle string, photoDescription string, altText string, photoTags []string) error {
-	// Check if Mastodon is configured
-	if cfg.Mastodon.AccessToken == "" {
-		return fmt.Errorf("not authenticated with Mastodon. Run 'imgup auth mastodon' first")
-	}
-	
-	// Validate we have required photo data
-	if photoID == "" {
-		return fmt.Errorf("cannot post to Mastodon: no photo ID available")
-	}
-	if photoURL == "" {
-		return fmt.Errorf("cannot post to Mastodon: no photo URL available")
-	}
-	
-	// Create Mastodon client
-	client := mastodon.NewClient(
-		cfg.Mastodon.InstanceURL,
-		cfg.Mastodon.ClientID,
-		cfg.Mastodon.ClientSecret,
-		cfg.Mastodon.AccessToken,
-	)
-	
-	// Use post text if provided, otherwise use title
-	statusText := post
-	if statusText == "" && photoTitle != "" {
-		statusText = photoTitle
+// displayFilename returns the image's filename, sanitized when
+// --sanitize-filename or default.sanitize_filename is set
+func displayFilename(cfg *config.Config, path string) string {
+	filename := filepath.Base(path)
+	if sanitizeFilename || cfg.Default.SanitizeFilename {
+		filename = textutil.SanitizeFilename(filename)
 	}
-	
-	// Add the photo URL to the post
-	statusText += "\n\n" + photoURL
-	
-	// Get a suitable image URL for Mastodon based on the service
-	imageURL, err := getImageURLForSocialPosting(cfg, service, photoID)
-	if err != nil {
-		return fmt.Errorf("failed to get image for social posting: %w", err)
-	}
-	
-	// Determine alt text: use explicit alt text, fall back to description
-	mastodonAltText := altText
-	if mastodonAltText == "" && photoDescription != "" {
-		mastodonAltText = photoDescription
-	}
-	
-	// Upload the resized image from photo service to Mastodon
-	mediaID, err := client.UploadMediaFromURL(imageURL, mastodonAltText)
-	if err != nil {
-		return fmt.Errorf("failed to upload media: %w", err)
-	}
-	
-	// Post the status
-	if err := client.PostStatus(statusText, []string{mediaID}, visibility, photoTags); err != nil {
-		return fmt.Errorf("failed to post status: %w", err)
-	}
-	
-	return nil
+	return filename
 }
 
-// getImageURLForSocialPosting fetches an appropriate image URL for social media posting
-// from either Flickr or SmugMug using the photo ID
-func getImageURLForSocialPosting(cfg *config.Config, service string, photoID string) (string, error) {
-	if os.Getenv("IMGUP_DEBUG") != "" {
-		fmt.Fprintf(os.Stderr, "DEBUG: getImageURLForSocialPosting called with service=%s, photoID=%s\n", service, photoID)
+// uploadName returns the path whose name the service stores: remote when
+// set, or path itself
+func uploadName(path, remote string) string {
+	if remote != "" {
+		return remote
 	}
-	
-	switch service {
-	case "flickr":
-		// Get photo sizes from Flickr to find a good size for social media
-		api := backends.NewFlickrAPI(&cfg.Flickr)
-		sizes, err := api.GetPhotoSizes(context.Background(), photoID)
-		if err != nil {
-			return "", fmt.Errorf("failed to get photo sizes from Flickr: %w", err)
-		}
-		
-		// Find a good size for social media (prefer Large or Medium)
-		var imageURL string
-		for _, size := range sizes {
-			// Prioritize these sizes for social media
-			if size.Label == "Large" || size.Label == "Large 1024" {
-				imageURL = size.Source
-				break
-			} else if size.Label == "Medium" || size.Label == "Medium 800" {
-				imageURL = size.Source
-				// Keep looking for Large
-			}
-		}
-		
-		// Fallback to whatever we have
-		if imageURL == "" && len(sizes) > 0 {
-			// Use a middle size if available
-			if len(sizes) > 2 {
-				imageURL = sizes[len(sizes)/2].Source
-			} else {
-				imageURL = sizes[0].Source
-			}
-		}
-		
-		if imageURL == "" {
-			return "", fmt.Errorf("no suitable image size found from Flickr")
-		}
-		
-		return imageURL, nil
-		
-	case "smugmug":
-		// For SmugMug, we need to construct the proper URI from the photo ID
-		// The photo ID from SmugMug is typically the AlbumImage URI
-		api := backends.NewSmugMugAPI(&cfg.SmugMug)
-		
-		// Get image sizes
-		sizes, err := api.GetImageSizes(context.Background(), photoID)
-		if err != nil {
-			return "", fmt.Errorf("failed to get image sizes from SmugMug (photo ID: %s): %w", photoID, err)
+	return path
+}
+
+// snippetFilename returns the %filename% value for an image
+func snippetFilename(cfg *config.Config, path string) string {
+	return render.Filename(path, sanitizeFilename || cfg.Default.SanitizeFilename)
+}
+
+// mastodonAccountNames splits a comma-separated --mastodon-account value and
+// checks that each account is configured. An empty value selects the main account.
+func mastodonAccountNames(cfg *config.Config, value string) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
 		}
-		
-		// Extract the image URL from the response
-		// SmugMug's response structure is complex, so we need to navigate it
-		if respData, ok := sizes["Response"].(map[string]interface{}); ok {
-			// Try to find the image URL in various possible locations
-			var imageURL string
-			
-			if os.Getenv("IMGUP_DEBUG") != "" {
-				fmt.Fprintf(os.Stderr, "DEBUG: SmugMug response keys: %v\n", getMapKeys(respData))
-			}
-			
-			// Check for AlbumImage.Image.ArchivedUri (for large size)
-			if albumImage, ok := respData["AlbumImage"].(map[string]interface{}); ok {
-				if img, ok := albumImage["Image"].(map[string]interface{}); ok {
-					if archivedUri, ok := img["ArchivedUri"].(string); ok && archivedUri != "" {
-						imageURL = archivedUri
-						if os.Getenv("IMGUP_DEBUG") != "" {
-							fmt.Fprintf(os.Stderr, "DEBUG: Found ArchivedUri: %s\n", imageURL)
-						}
-					}
-					
-					// If no ArchivedUri, try ImageDownloadUrl
-					if imageURL == "" {
-						if downloadUrl, ok := img["ImageDownloadUrl"].(string); ok && downloadUrl != "" {
-							imageURL = downloadUrl
-							if os.Getenv("IMGUP_DEBUG") != "" {
-								fmt.Fprintf(os.Stderr, "DEBUG: Found ImageDownloadUrl: %s\n", imageURL)
-							}
-						}
-					}
-				}
-			}
-			
-			// If still no URL, try the Image object directly
-			if imageURL == "" {
-				if img, ok := respData["Image"].(map[string]interface{}); ok {
-					if archivedUri, ok := img["ArchivedUri"].(string); ok && archivedUri != "" {
-						imageURL = archivedUri
-						if os.Getenv("IMGUP_DEBUG") != "" {
-							fmt.Fprintf(os.Stderr, "DEBUG: Found ArchivedUri in Image: %s\n", imageURL)
-						}
-					}
-				}
-			}
-			
-			if imageURL != "" {
-				return imageURL, nil
-			}
+		if _, err := cfg.Mastodon.Account(name); err != nil {
+			return nil, err
 		}
-		
-		return "", fmt.Errorf("could not extract image URL from SmugMug response - photo ID may be invalid or API response structure changed")
-		
-	default:
-		return "", fmt.Errorf("unsupported service: %s", service)
+		seen[name] = true
+		names = append(names, name)
 	}
+	return names, nil
 }
 
+// altLinter returns the alt text linter with thresholds from config
+func altLinter(cfg *config.Config) alttext.Linter {
+	return alttext.Linter{MinLength: cfg.Default.AltMinLength}
+}
+
+func maskString(s string) string {
+	if s == "" {
+		return "(not set)"
+	}
+	if len(s) <= 8 {
+		return "****"
+	}
+	return s[:4] + "****" + s[len(s)-4:]
+}
 
 func authBluesky() error {
 	// Load config
@@ -1596,6 +2370,11 @@ func authBluesky() error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 	
+	// Named accounts are checked on their own when there's no main account
+	if cfg.Bluesky.Handle == "" && len(cfg.Bluesky.Accounts) > 0 {
+		return authBlueskyAccounts(cfg)
+	}
+	
 	// Check if we have handle
 	if cfg.Bluesky.Handle == "" {
 		fmt.Println("Bluesky handle not found.")
@@ -1630,95 +2409,86 @@ func authBluesky() error {
 	
 	fmt.Printf("Successfully authenticated as @%s!\n", cfg.Bluesky.Handle)
 	
+	if len(cfg.Bluesky.Accounts) > 0 {
+		if err := authBlueskyAccounts(cfg); err != nil {
+			return err
+		}
+	}
+	
 	// Note: Unlike OAuth services, we don't save any tokens since Bluesky
 	// uses the app password directly for each session
 	
 	return nil
 }
 
-
-func postToBlueskyService(cfg *config.Config, service string, photoID string, photoURL string, photoTitle string, photoDescription string, altText string, photoTags []string) error {
-	// Check if Bluesky is configured
-	if cfg.Bluesky.Handle == "" || cfg.Bluesky.AppPassword == "" {
-		return fmt.Errorf("not authenticated with Bluesky. Run 'imgup auth bluesky' first")
-	}
-	
-	// Validate we have required photo data
-	if photoID == "" {
-		return fmt.Errorf("cannot post to Bluesky: no photo ID available")
-	}
-	if photoURL == "" {
-		return fmt.Errorf("cannot post to Bluesky: no photo URL available")
-	}
-	
-	// Create Bluesky client
-	client := bluesky.NewClient(cfg.Bluesky.PDS, cfg.Bluesky.Handle, cfg.Bluesky.AppPassword)
-	
-	// Use post text if provided, otherwise use title
-	statusText := post
-	if statusText == "" && photoTitle != "" {
-		statusText = photoTitle
+// authBlueskyAccounts tests the credentials of each named Bluesky account
+func authBlueskyAccounts(cfg *config.Config) error {
+	failed := 0
+	for _, name := range cfg.Bluesky.AccountNames() {
+		account := cfg.Bluesky.Accounts[name]
+		if !account.Configured() {
+			fmt.Fprintf(os.Stderr, "Account %s: missing handle or app password. Set them with:\n", name)
+			fmt.Fprintf(os.Stderr, "  imgup config set bluesky.accounts.%s.handle yourhandle.bsky.social\n", name)
+			fmt.Fprintf(os.Stderr, "  imgup config set bluesky.accounts.%s.app_password YOUR_APP_PASSWORD\n", name)
+			failed++
+			continue
+		}
+		
+		client := bluesky.NewClient(account.PDS, account.Handle, account.AppPassword)
+		if err := client.Authenticate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Account %s: authentication failed: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Successfully authenticated account %s as @%s!\n", name, account.Handle)
 	}
 	
-	// Add the photo URL to the post
-	statusText += "\n\n" + photoURL
-	
-	// Check character limit (300 for Bluesky)
-	if len(statusText) > 300 {
-		// Warn but continue with truncated text
-		fmt.Fprintf(os.Stderr, "Warning: Post text exceeds Bluesky's 300 character limit (%d chars). Truncating...\n", len(statusText))
-		// Leave room for "..."
-		statusText = statusText[:297] + "..."
+	if failed > 0 {
+		return fmt.Errorf("%d Bluesky account(s) failed to authenticate", failed)
 	}
-	
-	// Get a suitable image URL based on the service
-	if os.Getenv("IMGUP_DEBUG") != "" {
-		fmt.Fprintf(os.Stderr, "DEBUG: Getting image URL for Bluesky posting...\n")
+	return nil
+}
+
+
+func checkCommand(cmd *cobra.Command, args []string) error {
+	if (onlyNew || onlyDuplicates) && checkDir == "" {
+		return fmt.Errorf("--only-new and --only-duplicates need --dir")
 	}
-	imageURL, err := getImageURLForSocialPosting(cfg, service, photoID)
-	if err != nil {
-		return fmt.Errorf("failed to get image for social posting: %w", err)
+	if (checkJSONInput || cmd.Flags().Changed("concurrency")) && !checkBatch {
+		return fmt.Errorf("--json and --concurrency need --batch")
 	}
-	if os.Getenv("IMGUP_DEBUG") != "" {
-		fmt.Fprintf(os.Stderr, "DEBUG: Got image URL: %s\n", imageURL)
+	if checkBatch && checkConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
 	}
-	
-	// Determine alt text: use explicit alt text, fall back to description
-	blueskyAltText := altText
-	if blueskyAltText == "" && photoDescription != "" {
-		blueskyAltText = photoDescription
+	if checkBatch && len(args) > 0 {
+		return fmt.Errorf("pass an image or --batch, not both")
 	}
-	
-	// Upload the image from the photo service to Bluesky
-	blob, _, err := client.UploadMediaFromURL(imageURL, blueskyAltText)
-	if err != nil {
-		return fmt.Errorf("failed to upload media: %w", err)
+	if !checkBatch && checkDir == "" && len(args) == 0 {
+		return fmt.Errorf("nothing to check: pass an image, --dir or --batch")
 	}
-	
-	// Post the status
-	if err := client.PostStatus(statusText, []bluesky.BlobResponse{*blob}, []string{blueskyAltText}, photoTags); err != nil {
-		return fmt.Errorf("failed to post status: %w", err)
+	if checkDir != "" && len(args) > 0 {
+		return fmt.Errorf("pass an image or --dir, not both")
 	}
-	
-	return nil
-}
 
-func checkCommand(cmd *cobra.Command, args []string) {
-	imagePath := args[0]
+	var imagePath string
+	if len(args) > 0 {
+		imagePath = args[0]
 
-	// Check if file exists
-	if _, err := os.Stat(imagePath); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: File not found: %s\n", imagePath)
-		os.Exit(1)
+		// Check if file exists
+		if _, err := os.Stat(imagePath); os.IsNotExist(err) {
+			return fmt.Errorf("File not found: %s", imagePath)
+		}
 	}
 
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		os.Exit(1)
+		return failf("Error loading config: %v", err)
 	}
 
+	applyDuplicatePreference(cfg)
+	ctx := cmd.Context()
+
 	// Apply defaults from config if flags weren't explicitly set
 	if !cmd.Flags().Changed("format") && cfg.Default.Format != "" {
 		outputFormat = cfg.Default.Format
@@ -1727,6 +2497,11 @@ func checkCommand(cmd *cobra.Command, args []string) {
 		service = cfg.Default.Service
 	}
 
+	// Check every configured service at once
+	if checkAll {
+		return checkAllServices(ctx, cfg, imagePath)
+	}
+
 	// Determine which service to use (same logic as upload command)
 	if service == "" {
 		hasFlickr := cfg.Flickr.AccessToken != "" && cfg.Flickr.AccessSecret != ""
@@ -1736,90 +2511,64 @@ func checkCommand(cmd *cobra.Command, args []string) {
 			if cfg.Default.Service != "" {
 				service = cfg.Default.Service
 			} else {
-				fmt.Fprintf(os.Stderr, "Error: Both Flickr and SmugMug are configured. Please specify --service or set a default:\n")
-				fmt.Fprintf(os.Stderr, "  imgup config set default.service flickr\n")
-				fmt.Fprintf(os.Stderr, "  imgup config set default.service smugmug\n")
-				os.Exit(1)
+				return errAmbiguousService
 			}
 		} else if hasFlickr {
 			service = "flickr"
 		} else if hasSmugMug {
 			service = "smugmug"
 		} else {
-			fmt.Fprintf(os.Stderr, "Error: Not authenticated. Run 'imgup auth flickr' or 'imgup auth smugmug' first.\n")
-			os.Exit(1)
+			return fmt.Errorf("Not authenticated. Run 'imgup auth flickr' or 'imgup auth smugmug' first.")
 		}
 	}
 
-	// Create duplicate checker based on service
-	ctx := context.Background()
-	var checker *duplicate.RemoteChecker
-	
-	switch service {
-	case "flickr":
-		checker, err = duplicate.SetupFlickrDuplicateChecker(&cfg.Flickr)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error setting up duplicate checker: %v\n", err)
-			os.Exit(1)
-		}
-		
-	case "smugmug":
-		checker, err = duplicate.SetupSmugMugDuplicateChecker(&cfg.SmugMug)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error setting up duplicate checker: %v\n", err)
-			os.Exit(1)
-		}
-		
-	default:
-		fmt.Fprintf(os.Stderr, "Error: Unknown service: %s\n", service)
-		os.Exit(1)
+	if service != "flickr" && service != "smugmug" {
+		return fmt.Errorf("Unknown service: %s", service)
+	}
+
+	// Report a whole directory
+	if checkDir != "" {
+		return checkDirCommand(ctx, cfg, service)
+	}
+
+	// Report every path on stdin
+	if checkBatch {
+		return checkBatchCommand(ctx, cfg, service)
+	}
+
+	// List every copy on the service, in the same form as --all
+	if checkAllMatches {
+		return checkServices(ctx, cfg, []string{service}, imagePath)
 	}
-	defer checker.Close()
 
 	// Check for duplicate
 	
-	upload, err := checker.Check(ctx, imagePath)
+	client := imgup.New(cfg)
+	upload, err := client.CheckDuplicate(ctx, service, imagePath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error checking for duplicate: %v\n", err)
-		os.Exit(1)
+		return failf("Error checking for duplicate: %v", err)
 	}
 
 	if upload == nil {
 		// Not found - no output for silent operation
-		os.Exit(1)  // Exit with error code to indicate not found
+		return errSilent // Exit with error code to indicate not found
 	}
 
 	// Image found! Output using the same template system as upload
 	
 	// Output result using templates
-	template, exists := cfg.Templates[outputFormat]
-	if !exists {
-		fmt.Fprintf(os.Stderr, "Unknown format: %s\n", outputFormat)
-		fmt.Fprintf(os.Stderr, "Available formats: ")
-		var formats []string
-		for k := range cfg.Templates {
-			formats = append(formats, k)
-		}
-		fmt.Fprintf(os.Stderr, "%s\n", strings.Join(formats, ", "))
-		os.Exit(1)
+	template, err := outputTemplate(cfg, outputFormat)
+	if err != nil {
+		return err
 	}
 
 	// Build template variables
-	filename := filepath.Base(imagePath)
-	filenameNoExt := strings.TrimSuffix(filename, filepath.Ext(filename))
-	
-	// Build edit URL based on service
-	editURL := ""
-	if service == "flickr" {
-		editURL = "https://www.flickr.com/photos/upload/edit/?ids=" + upload.RemoteID
-	}
-	
 	vars := templates.Variables{
 		PhotoID:     upload.RemoteID,
-		URL:         upload.RemoteURL,
+		URL:         client.DisplayURL(service, upload.RemoteID, upload.RemoteURL),
 		ImageURL:    upload.ImageURL,
-		EditURL:     editURL,
-		Filename:    filenameNoExt,
+		EditURL:     render.EditURL(service, upload.RemoteID),
+		Filename:    snippetFilename(cfg, imagePath),
 		Title:       "", // We don't have title in cache
 		Description: "", // We don't have description in cache
 		Alt:         "", // We don't have alt text in cache
@@ -1828,4 +2577,6 @@ func checkCommand(cmd *cobra.Command, args []string) {
 
 	result := templates.Process(template, vars)
 	fmt.Println(result)
+	appendOutput(cfg, result)
+	return nil
 }
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/imgup"
+	"github.com/pdxmph/imgupv2/pkg/services/hashtags"
+	"github.com/pdxmph/imgupv2/pkg/term"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Post command flags
+	postService         string
+	postText            string
+	postTextFile        string
+	postAlt             string
+	postTags            []string
+	postVisibility      string
+	postTagPrefix       string
+	postMastodon        bool
+	postBluesky         bool
+	postBlueskyAccount  string
+	postMastodonAccount string
+	postDryRun          bool
+	postNoSocialURL     bool
+	postNoText          bool
+)
+
+// createPostCommand creates the post command
+func createPostCommand() *cobra.Command {
+	postCmd := &cobra.Command{
+		Use:   "post [url-or-id]",
+		Short: "Post an already-uploaded photo to social media",
+		Long: `Post a photo that's already on Flickr or SmugMug to Mastodon and/or Bluesky
+without uploading it again. Pass the photo page URL, or a photo ID together
+with --service.`,
+		Args: cobra.ExactArgs(1),
+		RunE: postCommand,
+	}
+
+	postCmd.Flags().StringVar(&postService, "service", "", "Photo service for a bare photo ID: flickr or smugmug")
+	postCmd.Flags().BoolVar(&postMastodon, "mastodon", false, "Post to Mastodon")
+	postCmd.Flags().BoolVar(&postBluesky, "bluesky", false, "Post to Bluesky")
+	postCmd.Flags().StringVar(&postMastodonAccount, "mastodon-account", "", "Comma-separated named Mastodon accounts to post to (see 'imgup config show')")
+	postCmd.Flags().StringVar(&postBlueskyAccount, "bluesky-account", "", "Named Bluesky account to post as (see 'imgup config show')")
+	postCmd.Flags().StringVar(&postText, "post", "", "Text for social media post (shared by Mastodon and Bluesky)")
+	postCmd.Flags().StringVar(&postText, "embed-text", "", "Social post body (same as --post)")
+	postCmd.Flags().StringVar(&postTextFile, "post-file", "", "Read the social post text from a file (--post - reads stdin)")
+	postCmd.MarkFlagsMutuallyExclusive("post", "embed-text", "post-file")
+	postCmd.Flags().StringVar(&postAlt, "alt", "", "Alt text for accessibility")
+	postCmd.Flags().StringSliceVar(&postTags, "tags", nil, "Comma-separated tags, posted as hashtags")
+	postCmd.Flags().StringVar(&postVisibility, "visibility", "public", "Mastodon post visibility: public, unlisted, followers, direct (Mastodon only)")
+	postCmd.Flags().StringVar(&postTagPrefix, "tag-prefix", "", "Prefix for social hashtags built from tags")
+	postCmd.Flags().BoolVar(&postNoSocialURL, "no-social-url", false, "Leave the photo URL out of the post (the image is still attached)")
+	postCmd.Flags().BoolVar(&postNoText, "no-text", false, "Post only the image, with no text, photo URL or hashtags")
+	postCmd.Flags().BoolVar(&postDryRun, "dry-run", false, "Show what would be posted without actually posting")
+
+	return postCmd
+}
+
+func postCommand(cmd *cobra.Command, args []string) error {
+	if !postMastodon && !postBluesky {
+		return fmt.Errorf("nothing to do: use --mastodon and/or --bluesky")
+	}
+	if err := readPostText(&postText, postTextFile); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return failf("Error loading config: %v", err)
+	}
+	var mastodonAccounts []string
+	if postMastodon {
+		if mastodonAccounts, err = mastodonAccountNames(cfg, postMastodonAccount); err != nil {
+			return err
+		}
+	}
+	if postBluesky {
+		if _, err := cfg.Bluesky.Account(postBlueskyAccount); err != nil {
+			return err
+		}
+	}
+
+	client := imgup.New(cfg)
+	ctx := cmd.Context()
+
+	req := &imgup.UploadRequest{
+		Alt:              postAlt,
+		Tags:             postTags,
+		Post:             postText,
+		Visibility:       postVisibility,
+		TagPrefix:        postTagPrefix,
+		BlueskyAccount:   postBlueskyAccount,
+		MastodonAccounts: mastodonAccounts,
+		NoSocialURL:      postNoSocialURL,
+		NoText:           postNoText,
+	}
+	if err := req.CheckNoText(); err != nil {
+		return err
+	}
+
+	result, err := client.ResolvePhoto(ctx, postService, args[0])
+	if err != nil {
+		return err
+	}
+
+	if postDryRun {
+		text := client.StatusText(req, result.URL)
+		if postMastodon {
+			fmt.Printf("[DRY RUN] Would post to Mastodon:\n")
+			if len(mastodonAccounts) > 0 {
+				fmt.Printf("  Accounts: %s\n", strings.Join(mastodonAccounts, ", "))
+			}
+			fmt.Printf("  Visibility: %s\n", postVisibility)
+			fmt.Printf("  Text: %s\n", text)
+			if missing := hashtags.Missing(text, req.SocialTags(), postTagPrefix, cfg.Default.HashtagStyle); len(missing) > 0 {
+				fmt.Printf("  Hashtags: %s\n", strings.Join(missing, " "))
+			}
+		}
+		if postBluesky {
+			blueskyText := hashtags.Append(text, req.SocialTags(), postTagPrefix, cfg.Default.HashtagStyle)
+			fmt.Printf("[DRY RUN] Would post to Bluesky:\n")
+			fmt.Printf("  Text (%d chars): %s\n", len(blueskyText), blueskyText)
+		}
+		fmt.Printf("  Photo: %s %s (%s)\n", result.Service, result.PhotoID, result.URL)
+		return nil
+	}
+
+	failed := 0
+	var targets []imgup.SocialResult
+	if postMastodon {
+		targets = append(targets, client.PostToMastodon(ctx, req, result)...)
+	}
+	if postBluesky {
+		targets = append(targets, client.PostToBluesky(ctx, req, result))
+	}
+
+	for _, social := range targets {
+		for _, warning := range social.Warnings {
+			warnf("%s", warning)
+		}
+		if social.Error != nil {
+			fmt.Fprintf(os.Stderr, "%s post failed: %v\n", socialLabel(social), social.Error)
+			if err := client.QueueSocialRetry(req, result, social); err != nil {
+				warnf("Failed to queue post for retry: %v", err)
+			} else {
+				fmt.Fprintf(os.Stderr, "Queued for retry. Run 'imgup retry-social' to try again.\n")
+			}
+			failed++
+			continue
+		}
+		fmt.Println(term.Success(os.Stdout, fmt.Sprintf("Posted to %s successfully!", socialLabel(social))))
+	}
+
+	if failed > 0 {
+		return errSilent
+	}
+	return nil
+}
+
+// socialLabel names the service and, for a named account, the account a
+// post went to, e.g. "Mastodon (work)"
+func socialLabel(social imgup.SocialResult) string {
+	if social.Account == "" {
+		return socialTargetName(social.Target)
+	}
+	return socialTargetName(social.Target) + " (" + social.Account + ")"
+}
+
+// socialTargetName returns the display name for a social target
+func socialTargetName(target string) string {
+	switch target {
+	case "mastodon":
+		return "Mastodon"
+	case "bluesky":
+		return "Bluesky"
+	default:
+		return target
+	}
+}
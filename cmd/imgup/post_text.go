@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// readPostText replaces text with the contents of file, or of stdin when
+// text is "-". A single trailing newline is dropped.
+func readPostText(text *string, file string) error {
+	var data []byte
+	var err error
+	switch {
+	case file != "":
+		data, err = os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read post text: %w", err)
+		}
+	case *text == "-":
+		data, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read post text from stdin: %w", err)
+		}
+	default:
+		return nil
+	}
+
+	s := string(data)
+	if strings.HasSuffix(s, "\r\n") {
+		s = strings.TrimSuffix(s, "\r\n")
+	} else {
+		s = strings.TrimSuffix(s, "\n")
+	}
+	*text = s
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/textutil"
+	"github.com/pdxmph/imgupv2/pkg/types"
+)
+
+// effectiveBatchRequest returns a batch request with the config defaults,
+// command line flags and resolved service it will run with filled in
+func effectiveBatchRequest(cfg *config.Config, request types.BatchUploadRequest, service, batchID string) types.BatchUploadRequest {
+	common := types.CommonSettings{}
+	if request.Common != nil {
+		common = *request.Common
+	}
+	common.Service = service
+	if common.SafetyLevel == "" {
+		common.SafetyLevel = cfg.Flickr.SafetyLevel
+	}
+	if common.ContentType == "" {
+		common.ContentType = cfg.Flickr.ContentType
+	}
+	if common.SmugMugPrivacy == "" {
+		common.SmugMugPrivacy = cfg.SmugMug.Privacy
+	}
+	if common.Transcode == "" {
+		common.Transcode = transcode
+	}
+	if common.CopyEXIFFrom == "" {
+		common.CopyEXIFFrom = copyEXIFFrom
+	}
+	if common.MinDimension == 0 {
+		common.MinDimension = minDimension
+	}
+	if common.Album == "" {
+		common.Album = uploadAlbum
+	}
+	if common.Album == "" && service == "flickr" {
+		common.Album = cfg.Flickr.UploadAlbum
+	}
+	if common.Album == "" && service == "smugmug" {
+		common.Album = cfg.SmugMug.UploadAlbum
+	}
+	common.Tags = mergeTags(common.Tags, fileTags)
+	request.Common = &common
+
+	images := make([]types.ImageUpload, len(request.Images))
+	for i, img := range request.Images {
+		if img.Title == "" && (titleFromFilename || cfg.Default.TitleFromFilename) {
+			img.Title = textutil.TitleizeWith(displayFilename(cfg, uploadName(img.Path, img.RemoteFilename)), cfg.Default.TitleCleanup)
+		}
+		if img.DateTaken == "" {
+			img.DateTaken = dateTaken
+		}
+		images[i] = img
+	}
+	request.Images = images
+
+	options := types.UploadOptions{}
+	if request.Options != nil {
+		options = *request.Options
+	}
+	options.Force = force
+	options.Replace = replaceUpload
+	options.SkipExistingInAlbum = skipExistingInAlbum
+	options.DryRun = dryRun
+	options.VerifyUpload = verifyUpload
+	options.BatchID = batchID
+	request.Options = &options
+
+	return request
+}
+
+// printBatchRequest writes the effective batch request to stderr as JSON
+func printBatchRequest(request types.BatchUploadRequest) error {
+	output, err := json.MarshalIndent(request, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	fmt.Fprintln(os.Stderr, string(output))
+	return nil
+}
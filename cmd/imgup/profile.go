@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/pdxmph/imgupv2/pkg/config"
+)
+
+var (
+	// Profile command flags
+	profileCreate bool
+)
+
+// createProfileCommand creates the profile command
+func createProfileCommand() *cobra.Command {
+	profileCmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Switch between separate configurations",
+		Long: `Keep separate configurations, e.g. "personal" and "client-a", each with its
+own credentials, defaults and last-used state, and switch between them. The
+original config file is the "default" profile. Use --config-profile to run a
+single command with another profile.`,
+	}
+
+	profileListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List profiles, marking the active one",
+		Args:  cobra.NoArgs,
+		RunE:  profileListCommand,
+	}
+
+	profileUseCmd := &cobra.Command{
+		Use:               "use <name>",
+		Short:             "Switch to a profile",
+		Args:              cobra.ExactArgs(1),
+		RunE:              profileUseCommand,
+		ValidArgsFunction: completeProfiles,
+	}
+	profileUseCmd.Flags().BoolVar(&profileCreate, "create", false, "Create the profile if it doesn't exist")
+
+	profileCmd.AddCommand(profileListCmd, profileUseCmd)
+	return profileCmd
+}
+
+func profileListCommand(cmd *cobra.Command, args []string) error {
+	profiles, err := config.Profiles()
+	if err != nil {
+		return err
+	}
+	active := config.ActiveProfile()
+	for _, name := range profiles {
+		marker := "  "
+		if name == active {
+			marker = "* "
+		}
+		fmt.Println(marker + name)
+	}
+	return nil
+}
+
+func profileUseCommand(cmd *cobra.Command, args []string) error {
+	if err := config.UseProfile(args[0], profileCreate); err != nil {
+		return err
+	}
+	fmt.Printf("Using profile %s (%s)\n", args[0], config.Path())
+	return nil
+}
+
+// completeProfiles completes profile names
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	profiles, _ := config.Profiles()
+	return profiles, cobra.ShellCompDirectiveNoFileComp
+}
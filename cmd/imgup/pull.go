@@ -18,9 +18,13 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/pdxmph/imgupv2/pkg/backends"
 	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/imgup"
 	"github.com/pdxmph/imgupv2/pkg/kitty"
+	"github.com/pdxmph/imgupv2/pkg/render"
 	"github.com/pdxmph/imgupv2/pkg/services/mastodon"
 	"github.com/pdxmph/imgupv2/pkg/services/bluesky"
+	"github.com/pdxmph/imgupv2/pkg/templates"
+	"github.com/pdxmph/imgupv2/pkg/term"
 	"github.com/pdxmph/imgupv2/pkg/types"
 )
 
@@ -35,9 +39,19 @@ var (
 	pullDryRun  bool
 	pullMastodon bool
 	pullBluesky  bool
+	pullBlueskyAccount string
 	pullVisibility string
 	pullPost    string
+	pullPostFile string
+	pullNoThumbnails bool
 	pullTags    string
+	pullTagPrefix string
+	pullNoRemember bool
+	pullOffset  int
+	pullPage    int
+	pullJSONSchema bool
+	pullGUIPath string
+	pullCreateAlbum bool
 )
 
 // createPullCommand creates the pull command
@@ -49,22 +63,35 @@ func createPullCommand() *cobra.Command {
 distribution and content generation. Fetches recent images from albums 
 and presents them for selection.`,
 		Args: cobra.MaximumNArgs(1),
-		Run:  pullCommand,
+		RunE: pullCommand,
 	}
 
 	// Add pull flags
 	pullCmd.Flags().StringVar(&pullService, "service", "", "Source service: smugmug, flickr (uses default if not set)")
 	pullCmd.Flags().StringVar(&pullAlbum, "album", "", "Album name (SmugMug default: 'Sharing', Flickr default: photostream)")
+	pullCmd.Flags().BoolVar(&pullCreateAlbum, "create-album", false, "Create the SmugMug album if it doesn't exist (see smugmug.create_pull_album)")
 	pullCmd.Flags().StringVar(&pullFormat, "format", "social", "Output format: social, markdown, html, json")
 	pullCmd.Flags().StringVar(&pullSize, "size", "", "Image size: large, medium, small (default: auto based on format)")
 	pullCmd.Flags().BoolVar(&pullJSON, "json", false, "Output JSON without interactive selection")
 	pullCmd.Flags().BoolVar(&pullGUI, "gui", false, "Open GUI instead of $EDITOR")
+	pullCmd.Flags().StringVar(&pullGUIPath, "gui-path", "", "GUI app bundle or binary for --gui (default: gui.path, then search)")
 	pullCmd.Flags().BoolVar(&pullDryRun, "dry-run", false, "Show what would be posted without posting")
 	pullCmd.Flags().BoolVar(&pullMastodon, "mastodon", false, "Post to Mastodon")
 	pullCmd.Flags().BoolVar(&pullBluesky, "bluesky", false, "Post to Bluesky")
+	pullCmd.Flags().StringVar(&pullBlueskyAccount, "bluesky-account", "", "Named Bluesky account to post as (see 'imgup config show')")
 	pullCmd.Flags().StringVar(&pullVisibility, "visibility", "public", "Mastodon visibility: public, unlisted, private (followers), direct")
 	pullCmd.Flags().StringVar(&pullPost, "post", "", "Social media post text (skips editor if provided)")
+	pullCmd.Flags().StringVar(&pullPost, "embed-text", "", "Social post body (same as --post)")
+	pullCmd.Flags().StringVar(&pullPostFile, "post-file", "", "Read the social post text from a file (--post - reads stdin)")
+	pullCmd.MarkFlagsMutuallyExclusive("post", "embed-text", "post-file")
 	pullCmd.Flags().StringVar(&pullTags, "tags", "", "Filter by tags (comma-separated)")
+	pullCmd.Flags().BoolVar(&pullNoRemember, "no-remember", false, "Don't use or update last-used service, album and visibility")
+	pullCmd.Flags().StringVar(&pullTagPrefix, "tag-prefix", "", "Prefix for social hashtags built from image tags")
+	pullCmd.Flags().IntVar(&pullOffset, "offset", 0, "Skip this many of the most recent images")
+	pullCmd.Flags().IntVar(&pullPage, "page", 0, "Page of results to fetch, counting from 1 (page size is the count)")
+	pullCmd.Flags().BoolVar(&pullNoThumbnails, "no-thumbnails", false, "List images as text, without downloading Kitty thumbnails")
+	pullCmd.Flags().BoolVar(&pullJSONSchema, "json-schema", false, "Print the JSON Schema for pull JSON and exit")
+	pullCmd.MarkFlagsMutuallyExclusive("offset", "page")
 
 	return pullCmd
 }
@@ -86,37 +113,77 @@ func showSpinner(done chan bool) {
 	}
 }
 
-func pullCommand(cmd *cobra.Command, args []string) {
+func pullCommand(cmd *cobra.Command, args []string) error {
+	if pullJSONSchema {
+		return printJSONSchema(types.PullRequestSchema())
+	}
+
+	// Choosing images reads stdin too, so only --json can take the post from it
+	if pullPost == "-" && !pullJSON {
+		return failf("pull reads your image selection from stdin, so --post - needs --json; use --post-file instead")
+	}
+	if err := readPostText(&pullPost, pullPostFile); err != nil {
+		return err
+	}
+
 	// Parse count argument
 	count := 10 // default
 	if len(args) > 0 {
 		var err error
 		count, err = strconv.Atoi(args[0])
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Invalid count: %v\n", err)
-			os.Exit(1)
+			return failf("Invalid count: %v", err)
+		}
+	}
+	if count < 1 {
+		return failf("Invalid count: %d. Must be at least 1", count)
+	}
+
+	// Work out where to start
+	offset := pullOffset
+	if offset < 0 {
+		return failf("Invalid offset: %d. Must be 0 or more", offset)
+	}
+	if cmd.Flags().Changed("page") {
+		if pullPage < 1 {
+			return failf("Invalid page: %d. Pages start at 1", pullPage)
 		}
+		offset = (pullPage - 1) * count
 	}
 
 	// Load config to get defaults
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
-		os.Exit(1)
+		return failf("Failed to load config: %v", err)
 	}
 
-	// Determine service (use flag, config default, or "smugmug")
+	// Load last-used options (flag > config default > last-used)
+	var state *config.State
+	if !pullNoRemember {
+		state, _ = config.LoadState()
+	}
+	if state == nil {
+		state = &config.State{Albums: map[string]string{}}
+	}
+
+	// Determine service (use flag, config default, last-used, or "smugmug")
 	service := pullService
 	if service == "" {
 		if cfg.Default.PullService != "" {
 			service = cfg.Default.PullService
 		} else if cfg.Default.Service != "" {
 			service = cfg.Default.Service
+		} else if state.Service != "" {
+			service = state.Service
 		} else {
 			service = "smugmug"
 		}
 	}
 
+	if !cmd.Flags().Changed("visibility") && state.Visibility != "" {
+		pullVisibility = state.Visibility
+	}
+
 	// Determine album
 	album := pullAlbum
 	if album == "" {
@@ -124,12 +191,16 @@ func pullCommand(cmd *cobra.Command, args []string) {
 		case "smugmug":
 			if cfg.SmugMug.PullAlbum != "" {
 				album = cfg.SmugMug.PullAlbum
+			} else if state.Album(service) != "" {
+				album = state.Album(service)
 			} else {
 				album = "Sharing" // SmugMug default
 			}
 		case "flickr":
 			if cfg.Flickr.PullAlbum != "" {
 				album = cfg.Flickr.PullAlbum
+			} else {
+				album = state.Album(service)
 			}
 			// For Flickr, empty album means photostream (not "Sharing")
 		}
@@ -150,6 +221,7 @@ func pullCommand(cmd *cobra.Command, args []string) {
 
 	// Fetch images from service with spinner
 	var images []types.PullImage
+	var total int
 	
 	if !pullJSON {
 		// Start spinner for interactive mode
@@ -157,46 +229,69 @@ func pullCommand(cmd *cobra.Command, args []string) {
 		go showSpinner(done)
 		
 		// Fetch images
-		images, err = fetchImages(service, album, count, pullTags)
+		images, total, err = fetchImages(service, album, count, offset, pullTags)
 		
 		// Stop spinner
 		done <- true
 		
 		// Print the fetch info after spinner clears
 		if service == "flickr" && album == "" {
-			fmt.Printf("Fetched from %s photostream\n\n", strings.Title(service))
+			fmt.Printf("Fetched from %s photostream\n", strings.Title(service))
 		} else {
-			fmt.Printf("Fetched from %s (album: %s)\n\n", strings.Title(service), album)
+			fmt.Printf("Fetched from %s (album: %s)\n", strings.Title(service), album)
+		}
+		if err == nil && len(images) > 0 {
+			fmt.Printf("Showing %d-%d of %d\n", offset+1, offset+len(images), total)
 		}
+		fmt.Println()
 	} else {
 		// No spinner for JSON output
-		images, err = fetchImages(service, album, count, pullTags)
+		images, total, err = fetchImages(service, album, count, offset, pullTags)
 	}
 	
+	// A missing SmugMug album, such as the default Sharing album, can be
+	// created; there's nothing to pull from it yet
+	if err != nil && service == "smugmug" {
+		var created bool
+		if created, err = createMissingAlbum(cmd.Context(), cfg, err, pullAlbum != ""); created {
+			return nil
+		}
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to fetch images: %v\n", err)
-		os.Exit(1)
+		return failf("Failed to fetch images: %v", err)
+	}
+
+	// Remember this session's choices for the next invocation
+	if !pullNoRemember {
+		rememberedVisibility := ""
+		if pullMastodon {
+			rememberedVisibility = pullVisibility
+		}
+		state.Remember(service, album, rememberedVisibility)
+		if err := state.Save(); err != nil && os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Failed to save last-used state: %v\n", err)
+		}
 	}
 
 	if len(images) == 0 {
+		if offset > 0 && offset >= total {
+			fmt.Printf("No images at offset %d; only %d available.\n", offset, total)
+			return nil
+		}
 		fmt.Println("No images found in the specified album.")
-		return
+		return nil
 	}
 
 	if pullJSON {
 		// Output JSON directly without selection
-		outputJSON(images, service, album)
-		return
+		return outputJSON(images, service, album, offset, total)
 	}
 
-	// Present numbered list for selection
-	displayImageList(images)
-
-	// Get user selection
-	selected := getUserSelection(images)
+	// Present numbered list for selection, a page at a time when configured
+	selected := chooseImages(images, cfg.Default.PullPageSize)
 	if len(selected) == 0 {
 		fmt.Println("No images selected.")
-		return
+		return nil
 	}
 
 	// Create JSON for selected images
@@ -204,165 +299,237 @@ func pullCommand(cmd *cobra.Command, args []string) {
 
 	if pullGUI {
 		// Launch GUI with pull data
-		if err := launchGUIWithPullData(pullReq); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to launch GUI: %v\n", err)
-			os.Exit(1)
+		if err := launchGUIWithPullData(cfg, pullReq); err != nil {
+			return failf("Failed to launch GUI: %v", err)
 		}
 	} else {
 		// If post text provided via flag, skip editor
 		if pullPost != "" {
-			processPullRequest(pullReq)
-		} else {
-			// Open in editor
-			editPullRequest(pullReq)
+			return processPullRequest(pullReq)
 		}
+		// Open in editor
+		return editPullRequest(pullReq)
 	}
+	return nil
 }
 
-func fetchImages(service, album string, count int, tags string) ([]types.PullImage, error) {
+// fetchImages fetches count images starting at offset, plus the total available
+func fetchImages(service, album string, count, offset int, tags string) ([]types.PullImage, int, error) {
 	ctx := context.Background()
 	
 	// Load config to get credentials
 	cfg, err := config.Load()
 	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %w", err)
+		return nil, 0, fmt.Errorf("failed to load config: %w", err)
 	}
 
 	switch service {
 	case "smugmug":
 		// Check if SmugMug is configured
 		if cfg.SmugMug.AccessToken == "" {
-			return nil, fmt.Errorf("SmugMug not authenticated. Run: imgup auth smugmug")
+			return nil, 0, fmt.Errorf("SmugMug not authenticated. Run: imgup auth smugmug")
 		}
 
 		client := backends.NewSmugMugPullClient(&cfg.SmugMug)
-		return client.PullImages(ctx, album, count, tags)
+		return client.PullImages(ctx, album, count, offset, tags)
 
 	case "flickr":
 		// Check if Flickr is configured
 		if cfg.Flickr.AccessToken == "" {
-			return nil, fmt.Errorf("Flickr not authenticated. Run: imgup auth flickr")
+			return nil, 0, fmt.Errorf("Flickr not authenticated. Run: imgup auth flickr")
 		}
 		
 		client := backends.NewFlickrPullClient(&cfg.Flickr)
-		return client.PullImages(ctx, album, count, tags)
+		return client.PullImages(ctx, album, count, offset, tags)
 
 	default:
-		return nil, fmt.Errorf("unsupported service: %s", service)
+		return nil, 0, fmt.Errorf("unsupported service: %s", service)
 	}
 }
 
-func displayImageList(images []types.PullImage) {
+// chooseImages lists images pageSize at a time (all at once when pageSize is
+// 0) and returns the ones picked. Numbers count from the first image, so
+// they stay the same on every page.
+func chooseImages(images []types.PullImage, pageSize int) []types.PullImage {
+	if pageSize <= 0 || pageSize > len(images) {
+		pageSize = len(images)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for start := 0; start < len(images); start += pageSize {
+		end := start + pageSize
+		if end > len(images) {
+			end = len(images)
+		}
+		displayImageList(images[start:end], start)
+
+		if end == len(images) {
+			fmt.Print("Select images (e.g., 1,3,5): ")
+		} else {
+			next := end + pageSize
+			if next > len(images) {
+				next = len(images)
+			}
+			more := fmt.Sprintf("%d-%d", end+1, next)
+			if next == end+1 {
+				more = fmt.Sprintf("%d", next)
+			}
+			fmt.Printf("Select images (e.g., 1,3,5), or press Enter for more (%s of %d): ", more, len(images))
+		}
+
+		input, err := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+		if input != "" && input != "more" && input != "m" {
+			return parseSelection(input, images[:end])
+		}
+		if err != nil {
+			if err != io.EOF {
+				errorf("couldn't read your selection: %v", err)
+			}
+			return nil
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// displayImageList shows images numbered from first+1, with Kitty thumbnails
+// when enabled and not turned off with --no-thumbnails
+func displayImageList(images []types.PullImage, first int) {
 	// Load config to check if Kitty thumbnails are enabled
 	cfg, err := config.Load()
-	if err == nil && cfg.Default.KittyThumbnails && kitty.IsKittyTerminal() {
+	if err == nil && cfg.Default.KittyThumbnails && !pullNoThumbnails && kitty.IsKittyTerminal() {
+		passthrough, ok := kittyPassthrough(cfg)
+		if !ok {
+			displayTextList(images, first)
+			return
+		}
 		// Try to display thumbnails in Kitty
-		if err := displayKittyThumbnails(images); err != nil {
+		if err := displayKittyThumbnails(images, first, passthrough); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to display Kitty thumbnails: %v\n", err)
 			fmt.Fprintln(os.Stderr, "Falling back to text display...")
-			displayTextList(images)
+			displayTextList(images, first)
 		}
 	} else {
 		// Fall back to text display
-		displayTextList(images)
+		displayTextList(images, first)
 	}
 }
 
-func displayTextList(images []types.PullImage) {
+func displayTextList(images []types.PullImage, first int) {
 	for i, img := range images {
-		fmt.Printf("%d) %s", i+1, img.Title)
-		if img.Description != "" {
-			fmt.Printf(" -- %s", img.Description)
-		}
+		displayTextItem(img, first+i+1)
 		fmt.Println()
 	}
 	fmt.Println()
 }
 
-func displayKittyThumbnails(images []types.PullImage) error {
+// displayTextItem prints an image's number, title and description, without a
+// line break
+func displayTextItem(img types.PullImage, number int) {
+	fmt.Printf("%d) %s", number, img.Title)
+	if img.Description != "" {
+		fmt.Printf(" -- %s", img.Description)
+	}
+}
+
+// kittyNoteShown keeps the note about multiplexers to once per pull
+var kittyNoteShown bool
+
+// kittyPassthrough picks icat's --passthrough for the terminal multiplexer
+// imgup runs in. It reports false, with a note, when thumbnails can't be
+// shown there and the list should be text.
+func kittyPassthrough(cfg *config.Config) (string, bool) {
+	var note string
+	switch kitty.Multiplexer() {
+	case "":
+		return "", true
+	case "tmux":
+		if cfg.Default.KittyInTmux == "passthrough" {
+			return "tmux", true
+		}
+		note = "Kitty thumbnails are off inside tmux. To show them, turn on tmux's allow-passthrough and run: imgup config set default.kitty_in_tmux passthrough"
+	case "screen":
+		note = "Kitty thumbnails don't work inside GNU screen"
+	}
+	if !kittyNoteShown {
+		fmt.Fprintf(os.Stderr, "Note: %s\n", note)
+		kittyNoteShown = true
+	}
+	return "", false
+}
+
+func displayKittyThumbnails(images []types.PullImage, first int, passthrough string) error {
 	display := kitty.NewImageDisplay()
+	display.Passthrough = passthrough
+	// Remove temp files however the list ends
+	defer display.Cleanup()
 	
-	// Clear any existing images first
-	display.ClearImages()
+	// Clear any existing images first, keeping earlier pages of this list
+	if first == 0 {
+		display.ClearImages()
+	}
 	
 	// Download and display thumbnails
-	fmt.Println("\nLoading thumbnails...\n")
+	fmt.Print("\nLoading thumbnails...\n\n")
 	
-	// Display each image with its info
+	// Display each image with its info; an image whose thumbnail fails is
+	// listed as text and the rest keep their thumbnails
 	for i, img := range images {
-		// Download thumbnail - prefer Small size for better visibility
-		thumbURL := img.Sizes.Small
-		if thumbURL == "" {
-			thumbURL = img.Sizes.Thumb // fallback to thumb if no small
-		}
-		if thumbURL == "" {
-			fmt.Printf("%d) %s [No thumbnail available]\n\n", i+1, img.Title)
-			continue
-		}
-		
-		resp, err := http.Get(thumbURL)
-		if err != nil {
-			fmt.Printf("%d) %s [Failed to download thumbnail]\n\n", i+1, img.Title)
-			continue
-		}
-		
-		// Check response status
-		if resp.StatusCode != http.StatusOK {
-			resp.Body.Close()
-			fmt.Printf("%d) %s [HTTP error: %d]\n\n", i+1, img.Title, resp.StatusCode)
-			continue
+		problem := displayKittyThumbnail(display, img)
+		displayTextItem(img, first+i+1)
+		if problem != "" {
+			fmt.Printf(" [%s]", problem)
 		}
-		
-		// Read the image data
-		data, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			fmt.Printf("%d) %s [Failed to read thumbnail]\n\n", i+1, img.Title)
-			continue
-		}
-		
-		// Check data size
-		if len(data) == 0 {
-			fmt.Printf("%d) %s [Empty thumbnail data]\n\n", i+1, img.Title)
-			continue
-		}
-		
-		// Display the thumbnail flush left
-		reader := bytes.NewReader(data)
-		if err := display.DisplayImage(reader, 0, 0); err != nil {
-			fmt.Printf("%d) %s [Failed to display thumbnail]\n\n", i+1, img.Title)
-			continue
-		}
-		
-		// Display metadata directly below the image
-		fmt.Printf("%d) %s", i+1, img.Title)
-		if img.Description != "" {
-			fmt.Printf(" -- %s", img.Description)
-		}
-		fmt.Println("\n") // Extra line for spacing between items
+		fmt.Print("\n\n") // Extra line for spacing between items
 	}
 	
-	// Clean up temp files when done
-	display.Cleanup()
-	
 	return nil
 }
 
-func getUserSelection(images []types.PullImage) []types.PullImage {
-	fmt.Print("Select images (e.g., 1,3,5): ")
+// displayKittyThumbnail shows an image's thumbnail above the text that
+// follows it, returning why it couldn't instead
+func displayKittyThumbnail(display *kitty.ImageDisplay, img types.PullImage) string {
+	// Download thumbnail - prefer Small size for better visibility
+	thumbURL := img.Sizes.Small
+	if thumbURL == "" {
+		thumbURL = img.Sizes.Thumb // fallback to thumb if no small
+	}
+	if thumbURL == "" {
+		return "No thumbnail available"
+	}
 	
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
+	resp, err := http.Get(thumbURL)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
-		return nil
+		return "Failed to download thumbnail"
 	}
-
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return nil
+	defer resp.Body.Close()
+	
+	// Check response status
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Sprintf("HTTP error: %d", resp.StatusCode)
+	}
+	
+	// Read the image data
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "Failed to read thumbnail"
+	}
+	
+	// Check data size
+	if len(data) == 0 {
+		return "Empty thumbnail data"
+	}
+	
+	// Display the thumbnail flush left
+	if err := display.DisplayImage(bytes.NewReader(data), 0, 0); err != nil {
+		return "Failed to display thumbnail"
 	}
+	return ""
+}
 
+// parseSelection picks the images numbered in a comma-separated list
+func parseSelection(input string, images []types.PullImage) []types.PullImage {
 	var selected []types.PullImage
 	parts := strings.Split(input, ",")
 	
@@ -412,23 +579,24 @@ func createPullRequest(images []types.PullImage, service, album string) *types.P
 	}
 }
 
-func outputJSON(images []types.PullImage, service, album string) {
+func outputJSON(images []types.PullImage, service, album string, offset, total int) error {
 	pullReq := createPullRequest(images, service, album)
+	pullReq.Offset = offset
+	pullReq.Total = total
 	
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
 	if err := encoder.Encode(pullReq); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to encode JSON: %v\n", err)
-		os.Exit(1)
+		return failf("Failed to encode JSON: %v", err)
 	}
+	return nil
 }
 
-func editPullRequest(pullReq *types.PullRequest) {
+func editPullRequest(pullReq *types.PullRequest) error {
 	// Create temporary file
 	tmpfile, err := os.CreateTemp("", "imgup-pull-*.json")
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to create temp file: %v\n", err)
-		os.Exit(1)
+		return failf("Failed to create temp file: %v", err)
 	}
 	defer os.Remove(tmpfile.Name())
 
@@ -436,15 +604,15 @@ func editPullRequest(pullReq *types.PullRequest) {
 	encoder := json.NewEncoder(tmpfile)
 	encoder.SetIndent("", "  ")
 	if err := encoder.Encode(pullReq); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to write JSON: %v\n", err)
-		os.Exit(1)
+		return failf("Failed to write JSON: %v", err)
 	}
 	tmpfile.Close()
 
 	// Give user instructions
 	fmt.Println("\nOpening editor. Fill in the 'post' field at the top for your social media text.")
 	fmt.Println("Example: \"post\": \"Check out these photos from the show!\"")
-	fmt.Println("You can also edit 'alt' text for individual images.\n")
+	fmt.Println("Add 'mastodon_post' or 'bluesky_post' to write different text for one of them.")
+	fmt.Print("You can also edit 'alt' text for individual images.\n\n")
 
 	// Get editor
 	editor := os.Getenv("EDITOR")
@@ -459,28 +627,30 @@ func editPullRequest(pullReq *types.PullRequest) {
 	cmd.Stderr = os.Stderr
 	
 	if err := cmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to open editor: %v\n", err)
-		os.Exit(1)
+		return failf("Failed to open editor: %v", err)
 	}
 
 	// Read back the edited JSON
 	data, err := os.ReadFile(tmpfile.Name())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to read edited file: %v\n", err)
-		os.Exit(1)
+		return failf("Failed to read edited file: %v", err)
 	}
 
 	// Parse the edited JSON
 	var editedReq types.PullRequest
 	if err := json.Unmarshal(data, &editedReq); err != nil {
-		fmt.Fprintf(os.Stderr, "Invalid JSON: %v\n", err)
-		os.Exit(1)
+		return failf("Invalid JSON: %v", err)
 	}
 
 	// Debug output in dry-run mode
 	if pullDryRun {
 		fmt.Println("\n[DRY RUN] Parsed JSON successfully")
 		fmt.Printf("Post text: %q\n", editedReq.Post)
+		for _, target := range editedReq.Targets {
+			if text := editedReq.PostFor(target); text != editedReq.Post {
+				fmt.Printf("Post text for %s: %q\n", target, text)
+			}
+		}
 		fmt.Printf("Images selected: %d\n", len(editedReq.Images))
 		for i, img := range editedReq.Images {
 			fmt.Printf("  %d. %s\n", i+1, img.Title)
@@ -489,27 +659,49 @@ func editPullRequest(pullReq *types.PullRequest) {
 	}
 
 	// Process the edited request
-	processPullRequest(&editedReq)
+	return processPullRequest(&editedReq)
 }
 
-func processPullRequest(pullReq *types.PullRequest) {
+func processPullRequest(pullReq *types.PullRequest) error {
 	// Check if post text exists
-	if pullReq.Post == "" {
+	if pullReq.Post == "" && pullReq.MastodonPost == "" && pullReq.BlueskyPost == "" {
 		fmt.Println("No post text provided. Use the 'post' field at the top of the JSON or --post flag.")
-		return
+		return nil
+	}
+	for _, target := range pullReq.Targets {
+		if pullReq.PostFor(target) == "" {
+			fmt.Printf("No post text for %s. Fill in 'post' or '%s_post' in the JSON.\n", target, target)
+			return nil
+		}
 	}
 
 	if len(pullReq.Images) == 0 {
 		fmt.Println("No images selected.")
-		return
+		return nil
+	}
+	imageURLs, err := pullImageURLs(pullReq.Images, pullSize)
+	if err != nil {
+		return err
+	}
+	usable := 0
+	for _, imageURL := range imageURLs {
+		if imageURL != "" {
+			usable++
+		}
 	}
 
-	fmt.Printf("Posting %d images with text: %q\n\n", len(pullReq.Images), pullReq.Post)
+	var progress pullReporter
+	progress.printf("Posting %d images with text: %q\n", usable, pullReq.Post)
+	for _, target := range pullReq.Targets {
+		if text := pullReq.PostFor(target); text != pullReq.Post {
+			progress.printf("  Text for %s: %q\n", target, text)
+		}
+	}
+	progress.printf("\n")
 	// Load config for social media credentials
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
-		os.Exit(1)
+		return failf("Failed to load config: %v", err)
 	}
 
 	// Initialize social media clients if needed
@@ -523,19 +715,29 @@ func processPullRequest(pullReq *types.PullRequest) {
 			cfg.Mastodon.ClientSecret,
 			cfg.Mastodon.AccessToken,
 		)
+		mastodonClient.TagPrefix = pullTagPrefix
+		mastodonClient.HashtagStyle = cfg.Default.HashtagStyle
 	}
 
-	if contains(pullReq.Targets, "bluesky") && cfg.Bluesky.AppPassword != "" {
+	account, err := cfg.Bluesky.Account(pullBlueskyAccount)
+	if contains(pullReq.Targets, "bluesky") && err != nil {
+		return err
+	}
+	if contains(pullReq.Targets, "bluesky") && account.AppPassword != "" {
 		blueskyClient = bluesky.NewClient(
-			"", // Uses default bsky.social
-			cfg.Bluesky.Handle,
-			cfg.Bluesky.AppPassword,
+			account.PDS,
+			account.Handle,
+			account.AppPassword,
 		)
+		blueskyClient.TagPrefix = pullTagPrefix
+		blueskyClient.HashtagStyle = cfg.Default.HashtagStyle
+		blueskyClient.JPEGQuality = cfg.Default.SocialJPEGQuality
+		blueskyClient.KeepEXIF = cfg.Default.SocialKeepEXIF
 		if err := blueskyClient.Authenticate(); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to authenticate with Bluesky: %v\n", err)
 			if !pullDryRun {
-				os.Exit(1)
+				return failf("Failed to authenticate with Bluesky: %v", err)
 			}
+			fmt.Fprintf(os.Stderr, "Failed to authenticate with Bluesky: %v\n", err)
 		}
 	}
 
@@ -554,23 +756,27 @@ func processPullRequest(pullReq *types.PullRequest) {
 		uniqueTags = append(uniqueTags, tag)
 	}
 
-	// Build post text with tags
-	postText := pullReq.Post
-
 	if pullDryRun {
 		fmt.Printf("[DRY RUN] Would post to: %v\n", pullReq.Targets)
-		fmt.Printf("  Text: %s\n", postText)
-		fmt.Printf("  Images: %d\n", len(pullReq.Images))
+		fmt.Printf("  Text: %s\n", pullReq.Post)
+		for _, target := range pullReq.Targets {
+			if text := pullReq.PostFor(target); text != pullReq.Post {
+				fmt.Printf("  Text for %s: %s\n", target, text)
+			}
+		}
+		fmt.Printf("  Images: %d\n", usable)
 		for i, img := range pullReq.Images {
-			imageURL := selectImageSize(img.Sizes, pullSize)
-			fmt.Printf("    %d. %s (%s)\n", i+1, img.Title, imageURL)
+			if imageURLs[i] == "" {
+				continue
+			}
+			fmt.Printf("    %d. %s (%s)\n", i+1, img.Title, imageURLs[i])
 			if img.Alt != "" {
 				fmt.Printf("       Alt: %s\n", img.Alt)
 			}
 		}
 		fmt.Printf("  Tags: %v\n", uniqueTags)
 		fmt.Printf("  Visibility: %s\n", pullReq.Visibility)
-		return
+		return nil
 	}
 
 	// Upload all images and collect media IDs/blobs
@@ -579,33 +785,59 @@ func processPullRequest(pullReq *types.PullRequest) {
 	var blueskyAltTexts []string
 
 	if mastodonClient != nil && contains(pullReq.Targets, "mastodon") {
-		fmt.Println("Uploading images to Mastodon...")
-		for _, img := range pullReq.Images {
-			imageURL := selectImageSize(img.Sizes, pullSize)
-			fmt.Printf("  Uploading %s...", img.Title)
-			mediaID, err := mastodonClient.UploadMediaFromURL(imageURL, img.Alt)
+		progress.printf("Uploading images to Mastodon...\n")
+		for i, img := range pullReq.Images {
+			imageURL := imageURLs[i]
+			if imageURL == "" {
+				continue
+			}
+			step := types.PullProgress{Service: "mastodon", Image: i + 1, Title: img.Title}
+			step.Event = types.PullUploading
+			progress.report(step)
+			err := imgup.CheckSocialFormat("mastodon", imageURL)
+			var mediaID string
+			if err == nil {
+				mediaID, err = mastodonClient.UploadMediaFromURL(imageURL, img.Alt)
+			}
 			if err != nil {
-				fmt.Printf(" failed: %v\n", err)
+				step.Event, step.Error = types.PullFailed, err.Error()
+				progress.report(step)
 				continue
 			}
 			mastodonMediaIDs = append(mastodonMediaIDs, mediaID)
-			fmt.Printf(" done\n")
+			step.Event = types.PullUploaded
+			progress.report(step)
 		}
 	}
 
 	if blueskyClient != nil && contains(pullReq.Targets, "bluesky") {
-		fmt.Println("Uploading images to Bluesky...")
-		for _, img := range pullReq.Images {
-			imageURL := selectImageSize(img.Sizes, pullSize)
-			fmt.Printf("  Uploading %s...", img.Title)
-			blob, altText, err := blueskyClient.UploadMediaFromURL(imageURL, img.Alt)
+		progress.printf("Uploading images to Bluesky...\n")
+		for i, img := range pullReq.Images {
+			imageURL := imageURLs[i]
+			if imageURL == "" {
+				continue
+			}
+			step := types.PullProgress{Service: "bluesky", Image: i + 1, Title: img.Title}
+			step.Event = types.PullUploading
+			progress.report(step)
+			err := imgup.CheckSocialFormat("bluesky", imageURL)
+			var blob *bluesky.BlobResponse
+			var altText string
+			if err == nil {
+				blob, altText, err = blueskyClient.UploadMediaFromURL(imageURL, img.Alt)
+			}
 			if err != nil {
-				fmt.Printf(" failed: %v\n", err)
+				step.Event, step.Error = types.PullFailed, err.Error()
+				progress.report(step)
 				continue
 			}
+			for _, warning := range blob.Warnings {
+				warnf("%s", warning)
+			}
 			blueskyBlobs = append(blueskyBlobs, *blob)
 			blueskyAltTexts = append(blueskyAltTexts, altText)
-			fmt.Printf(" done\n")
+			step.Event = types.PullUploaded
+			progress.report(step)
 		}
 	}
 
@@ -613,37 +845,47 @@ func processPullRequest(pullReq *types.PullRequest) {
 	posted := false
 
 	if mastodonClient != nil && contains(pullReq.Targets, "mastodon") && len(mastodonMediaIDs) > 0 {
-		fmt.Printf("\nPosting to Mastodon...")
+		step := types.PullProgress{Event: types.PullPosting, Service: "mastodon"}
+		progress.report(step)
 		visibility := pullReq.Visibility
 		if visibility == "" {
 			visibility = "public"
 		}
-		err = mastodonClient.PostStatus(postText, mastodonMediaIDs, visibility, uniqueTags)
+		err = mastodonClient.PostStatus(pullReq.PostFor("mastodon"), mastodonMediaIDs, visibility, uniqueTags)
 		if err != nil {
-			fmt.Printf(" failed: %v\n", err)
+			step.Event, step.Error = types.PullPostFailed, err.Error()
 		} else {
-			fmt.Printf(" done\n")
+			step.Event = types.PullPosted
 			posted = true
 		}
+		progress.report(step)
 	}
 
 	if blueskyClient != nil && contains(pullReq.Targets, "bluesky") && len(blueskyBlobs) > 0 {
-		fmt.Printf("Posting to Bluesky...")
-		err = blueskyClient.PostStatus(postText, blueskyBlobs, blueskyAltTexts, uniqueTags)
+		step := types.PullProgress{Event: types.PullPosting, Service: "bluesky"}
+		progress.report(step)
+		err = blueskyClient.PostStatus(pullReq.PostFor("bluesky"), blueskyBlobs, blueskyAltTexts, uniqueTags)
 		if err != nil {
-			fmt.Printf(" failed: %v\n", err)
+			step.Event, step.Error = types.PullPostFailed, err.Error()
 		} else {
-			fmt.Printf(" done\n")
+			step.Event = types.PullPosted
 			posted = true
 		}
+		progress.report(step)
 	}
 
 	// Generate output based on format
 	if posted && pullReq.Format != "social" {
 		fmt.Println("\nOutput:")
-		for _, img := range pullReq.Images {
-			imageURL := selectImageSize(img.Sizes, pullSize)
-			output := generateOutput(img, pullReq.Format, imageURL)
+		for i, img := range pullReq.Images {
+			if imageURLs[i] == "" {
+				continue
+			}
+			if img.Private {
+				warnf("%s is in a private album, so its links only work for you; leaving it out of the output", img.Title)
+				continue
+			}
+			output := generateOutput(cfg, img, pullReq.Format, imageURLs[i])
 			if output != "" {
 				fmt.Println(output)
 			}
@@ -651,10 +893,11 @@ func processPullRequest(pullReq *types.PullRequest) {
 	}
 
 	if posted {
-		fmt.Printf("\nSuccessfully posted %d images\n", len(pullReq.Images))
+		fmt.Printf("\n%s\n", term.Success(os.Stdout, fmt.Sprintf("Successfully posted %d images", usable)))
 	} else {
 		fmt.Println("\nNo posts were made")
 	}
+	return nil
 }
 
 func selectImageSize(sizes types.ImageSizes, requestedSize string) string {
@@ -689,26 +932,37 @@ func selectImageSize(sizes types.ImageSizes, requestedSize string) string {
 	}
 }
 
-func generateOutput(img types.PullImage, format string, imageURL string) string {
-	switch format {
-	case "url":
-		return img.SourceURL
-	case "markdown":
-		if img.Alt != "" {
-			return fmt.Sprintf("![%s](%s)", img.Alt, imageURL)
-		}
-		return fmt.Sprintf("![%s](%s)", img.Title, imageURL)
-	case "html":
-		if img.Alt != "" {
-			return fmt.Sprintf(`<img src="%s" alt="%s">`, imageURL, img.Alt)
-		}
-		return fmt.Sprintf(`<img src="%s" alt="%s">`, imageURL, img.Title)
-	case "social":
-		// For social format, we've already posted, so return empty
+// generateOutput renders a pulled image with the template for format, the
+// same way upload renders --format. Formats without a template print the
+// photo page URL.
+func generateOutput(cfg *config.Config, img types.PullImage, format string, imageURL string) string {
+	// For social format, we've already posted, so return empty
+	if format == "social" {
 		return ""
-	default:
+	}
+
+	vars := templates.Variables{
+		URL:         img.SourceURL,
+		ImageURL:    imageURL,
+		Title:       img.Title,
+		Description: img.Description,
+		Alt:         img.Alt,
+		Tags:        img.Tags,
+	}
+	// img.ID only numbers the selection; the photo ID is in the page URL
+	if strings.Contains(img.SourceURL, "://") {
+		if photo, err := imgup.New(cfg).ResolvePhoto(context.Background(), "", img.SourceURL); err == nil {
+			vars.PhotoID = photo.PhotoID
+			vars.EditURL = render.EditURL(photo.Service, photo.PhotoID)
+		}
+	}
+
+	output, err := render.Snippet(format, vars, cfg)
+	if err != nil {
+		warnf("Failed to render %s output for %s: %v", format, img.Title, err)
 		return img.SourceURL
 	}
+	return output
 }
 
 func contains(slice []string, item string) bool {
@@ -721,7 +975,7 @@ func contains(slice []string, item string) bool {
 }
 
 // launchGUIWithPullData launches the GUI app with pull request data
-func launchGUIWithPullData(pullReq *types.PullRequest) error {
+func launchGUIWithPullData(cfg *config.Config, pullReq *types.PullRequest) error {
 	// Serialize pull request to JSON
 	jsonData, err := json.Marshal(pullReq)
 	if err != nil {
@@ -739,9 +993,9 @@ func launchGUIWithPullData(pullReq *types.PullRequest) error {
 	}
 	
 	// Find the GUI app
-	guiPath := findGUIApp()
-	if guiPath == "" {
-		return fmt.Errorf("imgupv2-gui.app not found. Please ensure the GUI is installed.")
+	guiPath, err := findGUIApp(cfg, pullGUIPath)
+	if err != nil {
+		return err
 	}
 	
 	// Set up the command
@@ -749,13 +1003,13 @@ func launchGUIWithPullData(pullReq *types.PullRequest) error {
 	
 	if strings.HasSuffix(guiPath, ".app") {
 		// It's an app bundle - run the binary inside it directly
-		binaryPath := filepath.Join(guiPath, "Contents", "MacOS", "imgupv2-gui")
+		binaryPath := filepath.Join(guiPath, "Contents", "MacOS", guiBinaryName)
 		if _, err := os.Stat(binaryPath); err == nil {
 			// Run the binary directly with stdin
 			cmd = exec.Command(binaryPath, "--pull-data", "-")
 		} else {
 			// Can't use open command with stdin
-			return fmt.Errorf("cannot access GUI binary inside app bundle")
+			return fmt.Errorf("cannot access GUI binary inside app bundle: %s", binaryPath)
 		}
 	} else {
 		// Direct binary path
@@ -791,32 +1045,3 @@ func launchGUIWithPullData(pullReq *types.PullRequest) error {
 	
 	return nil
 }
-
-// findGUIApp locates the imgupv2-gui.app
-func findGUIApp() string {
-	// Check common locations - prioritize development build
-	searchPaths := []string{
-		// Development build location FIRST
-		filepath.Join(os.Getenv("HOME"), "code", "imgupv2", "gui", "build", "bin", "imgupv2-gui.app"),
-		// Then installed versions
-		"/Applications/imgupv2-gui.app",
-		filepath.Join(os.Getenv("HOME"), "Applications", "imgupv2-gui.app"),
-	}
-	
-	for _, path := range searchPaths {
-		if info, err := os.Stat(path); err == nil && info.IsDir() {
-			return path
-		}
-	}
-	
-	// Try to find using mdfind (Spotlight)
-	cmd := exec.Command("mdfind", "kMDItemCFBundleIdentifier == 'com.wails.imgupv2-gui'")
-	if output, err := cmd.Output(); err == nil {
-		apps := strings.Split(strings.TrimSpace(string(output)), "\n")
-		if len(apps) > 0 && apps[0] != "" {
-			return apps[0]
-		}
-	}
-	
-	return ""
-}
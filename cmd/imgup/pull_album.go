@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pdxmph/imgupv2/pkg/backends"
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/term"
+)
+
+// createMissingAlbum handles a pull from a SmugMug album that doesn't exist
+// yet, like the default Sharing album on a new account. With --create-album,
+// or when the user agrees at the prompt, it creates the album and returns
+// true. smugmug.create_pull_album only creates the configured or default
+// album, not one named with --album. A name with similar albums is likely a
+// typo, so only --create-album creates it. Otherwise it returns fetchErr
+// with a hint.
+func createMissingAlbum(ctx context.Context, cfg *config.Config, fetchErr error, albumFromFlag bool) (bool, error) {
+	var notFound *backends.AlbumNotFoundError
+	if !errors.As(fetchErr, &notFound) {
+		return false, fetchErr
+	}
+
+	create := pullCreateAlbum || (cfg.SmugMug.CreatePullAlbum && !albumFromFlag && len(notFound.Suggestions) == 0)
+	if !create && len(notFound.Suggestions) > 0 {
+		return false, fmt.Errorf("%w (run again with --create-album to create '%s' anyway)", fetchErr, notFound.Name)
+	}
+	if !create && !pullJSON && term.IsTerminal(os.Stdin) {
+		fmt.Printf("SmugMug has no album named '%s'. Create it? [y/N] ", notFound.Name)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		create = answer == "y" || answer == "yes"
+	}
+	if !create {
+		return false, fmt.Errorf("%w (run again with --create-album to create it)", fetchErr)
+	}
+
+	if _, err := backends.NewSmugMugAPI(&cfg.SmugMug).CreateAlbum(ctx, notFound.Name); err != nil {
+		return false, fmt.Errorf("failed to create album '%s': %w", notFound.Name, err)
+	}
+	out := os.Stdout
+	if pullJSON {
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, "Created unlisted SmugMug album '%s'. Upload photos to it, then pull again.\n", notFound.Name)
+	return true, nil
+}
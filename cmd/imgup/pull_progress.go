@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pdxmph/imgupv2/pkg/types"
+)
+
+// pullReporter prints progress while pulled images are posted. The GUI
+// reports the same types.PullProgress steps as events.
+type pullReporter struct{}
+
+// report prints one progress event
+func (r pullReporter) report(p types.PullProgress) {
+	switch p.Event {
+	case types.PullUploading:
+		fmt.Printf("  Uploading %s...", p.Title)
+	case types.PullPosting:
+		fmt.Printf("\nPosting to %s...", socialTargetName(p.Service))
+	case types.PullUploaded, types.PullPosted:
+		fmt.Printf(" done\n")
+	case types.PullFailed, types.PullPostFailed:
+		fmt.Printf(" failed: %s\n", p.Error)
+	}
+}
+
+// printf prints progress prose
+func (r pullReporter) printf(format string, args ...interface{}) {
+	fmt.Printf(format, args...)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pdxmph/imgupv2/pkg/term"
+	"github.com/pdxmph/imgupv2/pkg/types"
+)
+
+// pullImageURLs picks the URL each selected image is posted from, before
+// anything is posted. Images without any size are reported and left out,
+// and so are images missing the --size asked for, when the user declines
+// the next size at the prompt. Without a terminal the next size is used.
+// An empty URL leaves the image out; it's an error when that leaves none.
+func pullImageURLs(images []types.PullImage, size string) ([]string, error) {
+	urls := make([]string, len(images))
+	var missing, resized []string
+	var resizedIndexes []int
+	for i, img := range images {
+		urls[i] = selectImageSize(img.Sizes, size)
+		name := fmt.Sprintf("%d (%s)", i+1, img.Title)
+		switch {
+		case urls[i] == "":
+			missing = append(missing, name)
+		case exactImageSize(img.Sizes, size) == "":
+			resized = append(resized, name)
+			resizedIndexes = append(resizedIndexes, i)
+		}
+	}
+
+	if len(missing) > 0 {
+		warnf("%s no image URL to post; left out", pullImageList(missing, "has", "have"))
+	}
+
+	if len(resized) > 0 {
+		useNext := true
+		if !pullJSON && term.IsTerminal(os.Stdin) {
+			fmt.Printf("%s no %s size. Post the next size available instead? [Y/n] ", pullImageList(resized, "has", "have"), size)
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			useNext = answer == "" || answer == "y" || answer == "yes"
+		} else {
+			warnf("%s no %s size; posting the next size available", pullImageList(resized, "has", "have"), size)
+		}
+		if !useNext {
+			for _, i := range resizedIndexes {
+				urls[i] = ""
+			}
+		}
+	}
+
+	for _, url := range urls {
+		if url != "" {
+			return urls, nil
+		}
+	}
+	return nil, failf("None of the selected images has an image URL to post, so nothing was posted")
+}
+
+// exactImageSize returns the URL for size only, without falling back to
+// another. Sizes other than small, medium and large always match the URL
+// selectImageSize picks.
+func exactImageSize(sizes types.ImageSizes, size string) string {
+	switch size {
+	case "small":
+		return sizes.Small
+	case "medium":
+		return sizes.Medium
+	case "large":
+		return sizes.Large
+	}
+	return selectImageSize(sizes, size)
+}
+
+// pullImageList names images like "image 2 (Harbor) has" or
+// "images 2 (Harbor), 4 (Pier) have"
+func pullImageList(names []string, one, many string) string {
+	if len(names) == 1 {
+		return fmt.Sprintf("image %s %s", names[0], one)
+	}
+	return fmt.Sprintf("images %s %s", strings.Join(names, ", "), many)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/duplicate"
+	"github.com/pdxmph/imgupv2/pkg/imgup"
+	"github.com/pdxmph/imgupv2/pkg/term"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Retry-social command flags
+	retryList bool
+)
+
+// createRetrySocialCommand creates the retry-social command
+func createRetrySocialCommand() *cobra.Command {
+	retryCmd := &cobra.Command{
+		Use:   "retry-social",
+		Short: "Retry social posts that failed after a successful upload",
+		Long: `Retry Mastodon and Bluesky posts that failed after the image was uploaded.
+Failed posts from single-image uploads, watch and post are queued
+automatically; each is tried at most 5 times, then given up on. Batch (JSON)
+uploads and pull post several images at once, so their failures aren't
+queued; run them again instead.`,
+		Args: cobra.NoArgs,
+		RunE: retrySocialCommand,
+	}
+
+	retryCmd.Flags().BoolVar(&retryList, "list", false, "List queued posts without retrying them")
+
+	return retryCmd
+}
+
+func retrySocialCommand(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return failf("Error loading config: %v", err)
+	}
+
+	cache, err := duplicate.OpenDefaultCache()
+	if err != nil {
+		return fmt.Errorf("failed to open cache: %w", err)
+	}
+	posts, err := cache.PendingSocialPosts(cmd.Context())
+	cache.Close()
+	if err != nil {
+		return err
+	}
+
+	if len(posts) == 0 {
+		fmt.Println("No queued social posts.")
+		return nil
+	}
+
+	client := imgup.New(cfg)
+	failed, gaveUp := 0, 0
+
+	for _, post := range posts {
+		target := post.Target
+		if post.Account != "" {
+			target += " (" + post.Account + ")"
+		}
+
+		if retryList {
+			fmt.Printf("%s: %s (attempts: %d, last error: %s)\n", target, post.PhotoURL, post.Attempts, post.LastError)
+			continue
+		}
+
+		// Posts queued before given_up was recorded can already be out of attempts
+		if post.Attempts >= duplicate.MaxSocialAttempts {
+			if err := giveUpSocialPost(post.ID); err != nil {
+				warnf("Failed to update retry queue: %v", err)
+			}
+			errorf("gave up on %s post for %s after %d attempts (last error: %s)", target, post.PhotoURL, post.Attempts, post.LastError)
+			gaveUp++
+			continue
+		}
+
+		social := client.RetrySocialPost(cmd.Context(), post)
+		for _, warning := range social.Warnings {
+			warnf("%s", warning)
+		}
+		if social.Error != nil {
+			fmt.Fprintf(os.Stderr, "%s post for %s failed: %v\n", target, post.PhotoURL, social.Error)
+			if post.Attempts+1 >= duplicate.MaxSocialAttempts {
+				errorf("gave up on %s post for %s after %d attempts", target, post.PhotoURL, post.Attempts+1)
+				gaveUp++
+			} else {
+				failed++
+			}
+			continue
+		}
+		fmt.Println(term.Success(os.Stdout, fmt.Sprintf("Posted %s to %s successfully!", post.PhotoURL, target)))
+	}
+
+	switch {
+	case failed > 0 && gaveUp > 0:
+		return failf("%d social post(s) failed and remain queued, and %d were given up on", failed, gaveUp)
+	case failed > 0:
+		return failf("%d social post(s) failed and remain queued", failed)
+	case gaveUp > 0:
+		return failf("%d social post(s) were given up on", gaveUp)
+	}
+	return nil
+}
+
+// giveUpSocialPost stops retrying a queued social post
+func giveUpSocialPost(id int64) error {
+	cache, err := duplicate.OpenDefaultCache()
+	if err != nil {
+		return err
+	}
+	defer cache.Close()
+	return cache.GiveUpSocialPost(id)
+}
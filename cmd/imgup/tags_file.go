@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// fileTags are the tags read from --tags-file, added to every image of a
+// batch as well as to a single upload
+var fileTags []string
+
+// readTagsFile reads a tag list, one tag per line or separated by commas.
+// Blank lines and lines starting with # are skipped.
+func readTagsFile(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tags: %w", err)
+	}
+
+	var tags []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, tag := range strings.Split(line, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags, nil
+}
+
+// mergeTags appends the extra tags to tags, leaving out any already there.
+// Tags match ignoring case, as they do on Flickr.
+func mergeTags(tags, extra []string) []string {
+	var merged []string
+	seen := map[string]bool{}
+	for _, tag := range append(append([]string{}, tags...), extra...) {
+		if seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		merged = append(merged, tag)
+	}
+	return merged
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/imgup"
+)
+
+var (
+	// Update-metadata command flags
+	updateService     string
+	updateTitle       string
+	updateDescription string
+	updateTags        []string
+)
+
+// createUpdateMetadataCommand creates the update-metadata command
+func createUpdateMetadataCommand() *cobra.Command {
+	updateCmd := &cobra.Command{
+		Use:   "update-metadata [url-or-id]",
+		Short: "Fix the title, description or tags of an uploaded photo",
+		Long: `Change the title, description (SmugMug caption) or tags (SmugMug keywords)
+of a photo already on Flickr or SmugMug. Pass the photo page URL, or a photo ID
+together with --service. Fields you don't pass are left as they are, and tags
+are added to the photo's existing ones.`,
+		Args: cobra.ExactArgs(1),
+		RunE: updateMetadataCommand,
+	}
+
+	updateCmd.Flags().StringVar(&updateService, "service", "", "Photo service for a bare photo ID: flickr or smugmug")
+	updateCmd.Flags().StringVar(&updateTitle, "title", "", "New title")
+	updateCmd.Flags().StringVar(&updateDescription, "description", "", "New description (SmugMug caption)")
+	updateCmd.Flags().StringSliceVar(&updateTags, "tags", nil, "Comma-separated tags to add")
+
+	return updateCmd
+}
+
+func updateMetadataCommand(cmd *cobra.Command, args []string) error {
+	update := imgup.MetadataUpdate{
+		Title:       updateTitle,
+		Description: updateDescription,
+		Tags:        updateTags,
+	}
+	if update.Empty() {
+		return fmt.Errorf("nothing to do: use --title, --description and/or --tags")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return failf("Error loading config: %v", err)
+	}
+
+	client := imgup.New(cfg)
+	ctx := cmd.Context()
+
+	photo, err := client.ResolvePhoto(ctx, updateService, args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := client.UpdateMetadata(ctx, photo, update); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated %s photo %s\n", photo.Service, photo.PhotoID)
+	return nil
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/imgup"
+	"github.com/pdxmph/imgupv2/pkg/render"
+	"github.com/pdxmph/imgupv2/pkg/templates"
+	"github.com/pdxmph/imgupv2/pkg/textutil"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Watch command flags
+	watchService          string
+	watchFormat           string
+	watchTags             []string
+	watchPrivate          bool
+	watchManifest         string
+	watchSettle           time.Duration
+	watchMastodon         bool
+	watchBluesky          bool
+	watchBlueskyAccount   string
+	watchMastodonAccount  string
+	watchMastodonAccounts []string
+	watchPost             string
+	watchVisibility       string
+	watchTagPrefix        string
+)
+
+// watchExtensions are the file types watch uploads
+var watchExtensions = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".gif": true,
+	".heic": true, ".tif": true, ".tiff": true, ".webp": true,
+}
+
+// watchEntry is one line of the watch manifest
+type watchEntry struct {
+	Time      time.Time `json:"time"`
+	Path      string    `json:"path"`
+	Service   string    `json:"service,omitempty"`
+	PhotoID   string    `json:"photo_id,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	ImageURL  string    `json:"image_url,omitempty"`
+	Duplicate bool      `json:"duplicate,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Social    []string  `json:"social,omitempty"` // failed social targets, queued for retry-social
+}
+
+// pendingFile tracks a file that is still being written
+type pendingFile struct {
+	size     int64
+	lastSeen time.Time
+}
+
+// createWatchCommand creates the watch command
+func createWatchCommand() *cobra.Command {
+	watchCmd := &cobra.Command{
+		Use:   "watch <dir>",
+		Short: "Upload new images as they appear in a directory",
+		Long: `Watch a directory (for example a tethered shooting folder) and upload each
+new image once it has been fully written. Uploads use your configured
+defaults; each result is printed and added to a JSON lines manifest.
+Existing files are left alone. Stop with Ctrl-C.`,
+		Args: cobra.ExactArgs(1),
+		RunE: watchCommand,
+	}
+
+	watchCmd.Flags().StringVar(&watchService, "service", "", "Upload service: flickr or smugmug (auto-detected if not specified)")
+	watchCmd.Flags().StringVar(&watchFormat, "format", "url", "Output format: url, markdown, html, json, org, bbcode (see 'imgup config show')")
+	watchCmd.Flags().StringSliceVar(&watchTags, "tags", nil, "Comma-separated tags for every upload")
+	watchCmd.Flags().BoolVar(&watchPrivate, "private", false, "Make the photos private")
+	watchCmd.Flags().StringVar(&watchManifest, "manifest", "", "Manifest file (default: imgup-manifest.jsonl in the watched directory)")
+	watchCmd.Flags().DurationVar(&watchSettle, "settle", 2*time.Second, "How long a file must stay unchanged before it's uploaded")
+	watchCmd.Flags().BoolVar(&watchMastodon, "mastodon", false, "Post each upload to Mastodon")
+	watchCmd.Flags().BoolVar(&watchBluesky, "bluesky", false, "Post each upload to Bluesky")
+	watchCmd.Flags().StringVar(&watchMastodonAccount, "mastodon-account", "", "Comma-separated named Mastodon accounts to post to (see 'imgup config show')")
+	watchCmd.Flags().StringVar(&watchBlueskyAccount, "bluesky-account", "", "Named Bluesky account to post as (see 'imgup config show')")
+	watchCmd.Flags().StringVar(&watchPost, "post", "", "Text for each social media post")
+	watchCmd.Flags().StringVar(&watchVisibility, "visibility", "public", "Mastodon post visibility: public, unlisted, followers, direct (Mastodon only)")
+	watchCmd.Flags().StringVar(&watchTagPrefix, "tag-prefix", "", "Prefix for social hashtags built from tags")
+	watchCmd.RegisterFlagCompletionFunc("format", completeFormats)
+
+	return watchCmd
+}
+
+func watchCommand(cmd *cobra.Command, args []string) error {
+	dir, err := filepath.Abs(args[0])
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", args[0])
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return failf("Error loading config: %v", err)
+	}
+	applyDuplicatePreference(cfg)
+	client := imgup.New(cfg)
+
+	if !cmd.Flags().Changed("format") && cfg.Default.Format != "" {
+		watchFormat = cfg.Default.Format
+	}
+	template, err := outputTemplate(cfg, watchFormat)
+	if err != nil {
+		return err
+	}
+	service, err := client.ResolveService(watchService)
+	if err == imgup.ErrAmbiguousService {
+		return errAmbiguousService
+	} else if err != nil {
+		return err
+	}
+	if err := client.CheckAuth(service); err != nil {
+		return err
+	}
+	if watchMastodon {
+		if watchMastodonAccounts, err = mastodonAccountNames(cfg, watchMastodonAccount); err != nil {
+			return err
+		}
+	}
+	if watchBluesky {
+		if _, err := cfg.Bluesky.Account(watchBlueskyAccount); err != nil {
+			return err
+		}
+	}
+	if watchSettle <= 0 {
+		return fmt.Errorf("--settle must be positive")
+	}
+	if watchManifest == "" {
+		watchManifest = filepath.Join(dir, "imgup-manifest.jsonl")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start watcher: %w", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(dir); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+
+	ctx := cmd.Context()
+	fmt.Fprintf(os.Stderr, "Watching %s for new images (uploading to %s). Press Ctrl-C to stop.\n", dir, service)
+
+	// Uploads run one at a time off the event loop
+	queue := make(chan string, 100)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for path := range queue {
+			if ctx.Err() != nil {
+				continue // stopping; drain without uploading
+			}
+			watchUpload(ctx, client, service, template, path)
+		}
+	}()
+	defer wg.Wait()
+	defer close(queue)
+
+	pending := map[string]*pendingFile{}
+	ticker := time.NewTicker(watchSettle / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintf(os.Stderr, "\nStopped watching %s\n", dir)
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			if !isWatchedImage(event.Name) {
+				continue
+			}
+			// Any activity restarts the settle period
+			if p, ok := pending[event.Name]; ok {
+				p.lastSeen = time.Now()
+			} else {
+				pending[event.Name] = &pendingFile{size: -1, lastSeen: time.Now()}
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			warnf("watch error: %v", err)
+
+		case <-ticker.C:
+			for path, p := range pending {
+				if time.Since(p.lastSeen) < watchSettle {
+					continue
+				}
+				info, err := os.Stat(path)
+				if err != nil {
+					// Removed or renamed before it settled
+					delete(pending, path)
+					continue
+				}
+				// Still growing, or empty: wait another settle period
+				if info.Size() == 0 || info.Size() != p.size {
+					p.size = info.Size()
+					p.lastSeen = time.Now()
+					continue
+				}
+				delete(pending, path)
+				queue <- path
+			}
+		}
+	}
+}
+
+// isWatchedImage reports whether a file is an image watch should upload,
+// skipping hidden and temporary files
+func isWatchedImage(path string) bool {
+	name := filepath.Base(path)
+	if strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+		return false
+	}
+	return watchExtensions[strings.ToLower(filepath.Ext(name))]
+}
+
+// watchUpload uploads one settled file, prints its output and records it in the manifest
+func watchUpload(ctx context.Context, client *imgup.Client, service, template, path string) {
+	cfg := client.Config()
+	req := &imgup.UploadRequest{
+		Path:             path,
+		Tags:             watchTags,
+		Private:          watchPrivate,
+		Service:          service,
+		SafetyLevel:      cfg.Flickr.SafetyLevel,
+		ContentType:      cfg.Flickr.ContentType,
+		SmugMugPrivacy:   cfg.SmugMug.Privacy,
+		SkipValidation:   !cfg.ValidateImages(),
+		AltRequired:      cfg.Default.AltRequired,
+		Mastodon:         watchMastodon,
+		Bluesky:          watchBluesky,
+		BlueskyAccount:   watchBlueskyAccount,
+		MastodonAccounts: watchMastodonAccounts,
+		Post:             watchPost,
+		Visibility:       watchVisibility,
+		TagPrefix:        watchTagPrefix,
+	}
+	if cfg.Default.TitleFromFilename {
+		req.Title = textutil.TitleizeWith(displayFilename(cfg, path), cfg.Default.TitleCleanup)
+	}
+
+	entry := watchEntry{Time: time.Now(), Path: path, Service: service}
+	result, err := client.Upload(ctx, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Upload of %s failed: %v\n", filepath.Base(path), err)
+		entry.Error = err.Error()
+		writeWatchManifest(entry)
+		return
+	}
+
+	for _, warning := range result.Warnings {
+		warnf("%s: %s", filepath.Base(path), warning)
+	}
+	// PostSocial has already queued failed posts for retry-social
+	for _, social := range result.Social {
+		for _, warning := range social.Warnings {
+			warnf("%s: %s", filepath.Base(path), warning)
+		}
+		if social.Error == nil {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "%s post for %s failed: %v\n", socialLabel(social), filepath.Base(path), social.Error)
+		entry.Social = append(entry.Social, social.Target)
+	}
+
+	entry.PhotoID = result.PhotoID
+	entry.URL = result.URL
+	entry.ImageURL = result.ImageURL
+	entry.Duplicate = result.Duplicate
+
+	output := templates.Process(template, templates.Variables{
+		PhotoID:  result.PhotoID,
+		URL:      result.URL,
+		ImageURL: result.ImageURL,
+		EditURL:  render.EditURL(service, result.PhotoID),
+		Filename: snippetFilename(cfg, path),
+		Title:    req.Title,
+		Tags:     req.Tags,
+	})
+	fmt.Println(output)
+	appendOutput(cfg, output)
+	writeWatchManifest(entry)
+}
+
+// writeWatchManifest appends an entry to the manifest as a JSON line
+func writeWatchManifest(entry watchEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(watchManifest, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		warnf("Failed to write manifest: %v", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		warnf("Failed to write manifest: %v", err)
+	}
+}
@@ -12,11 +12,13 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 	"github.com/pdxmph/imgupv2/pkg/config"
 	"github.com/pdxmph/imgupv2/pkg/duplicate"
+	"github.com/pdxmph/imgupv2/pkg/imageproc"
 	"github.com/pdxmph/imgupv2/pkg/services/bluesky"
 	"github.com/pdxmph/imgupv2/pkg/services/mastodon"
 	"github.com/pdxmph/imgupv2/pkg/thumbnail"
@@ -31,6 +33,11 @@ type App struct {
 	currentPullRequest *types.PullRequest // Store current pull request
 	pullDataPath string // Path to pull data file if launched from CLI
 	pullDataJSON string // Pull data JSON if provided via stdin
+	launchFiles []string // Files passed on the command line, e.g. by a Linux file manager
+	photosExports map[string]photosExport // Photos.app exports by PhotosID, reused within a session
+	exportMu     sync.Mutex
+	resizeMu     sync.Mutex
+	resizeGen    int // bumped by each resize, so a newer one stops an animation in progress
 }
 
 // PhotoMetadata represents the metadata for a photo
@@ -54,9 +61,10 @@ type PhotoMetadata struct {
 	FileSize     int64  `json:"fileSize"`     // File size in bytes
 	IsTemporary  bool   `json:"isTemporary"`  // True if from Photos app
 	IsFromPhotos bool   `json:"isFromPhotos"` // True if selected from Photos
-	PhotosIndex  int    `json:"photosIndex"`  // Index of photo in Photos selection
+	PhotosIndex  int    `json:"photosIndex"`  // Index of photo in Photos selection; 0 outside it
 	PhotosID     string `json:"photosId"`     // Unique ID from Photos.app
 	PhotosFilename string `json:"photosFilename"` // Original filename in Photos
+	NoEmbedMetadata bool `json:"noEmbedMetadata"` // Upload a Photos export without writing metadata into it
 }
 
 // UploadResult represents the result of an upload operation
@@ -78,6 +86,7 @@ type MultiPhotoUploadRequest struct {
 	Bluesky    bool                   `json:"bluesky"`
 	Visibility string                 `json:"visibility"`
 	Format     string                 `json:"format"`
+	NoEmbedMetadata bool              `json:"noEmbedMetadata"`
 }
 
 // MultiPhotoImageData represents a single image in the multi-photo upload
@@ -89,6 +98,7 @@ type MultiPhotoImageData struct {
 	IsFromPhotos bool   `json:"isFromPhotos"`
 	PhotosIndex  int    `json:"photosIndex"`
 	PhotosID     string `json:"photosId"`
+	PhotosFilename string `json:"photosFilename"`
 }
 
 // MultiPhotoUploadResult represents the result of a multi-photo upload
@@ -116,6 +126,7 @@ type MultiPhotoOutputResult struct {
 func NewApp() *App {
 	return &App{
 		cachedPhotoIDs: make(map[string]bool),
+		photosExports:  make(map[string]photosExport),
 	}
 }
 
@@ -126,9 +137,12 @@ func (a *App) startup(ctx context.Context) {
 	fmt.Printf("DEBUG: startup - pullDataPath = %s\n", a.pullDataPath)
 	a.ctx = ctx
 	
+	// Clear out Photos exports that earlier sessions left behind
+	go sweepPhotosExports()
+	
 	// Initialize thumbnail generator with cache
 	fmt.Println("DEBUG: initializing cache")
-	cache, err := duplicate.NewSQLiteCache(duplicate.DefaultCachePath())
+	cache, err := duplicate.OpenDefaultCache()
 	if err == nil {
 		fmt.Println("DEBUG: cache initialized successfully")
 		a.thumbGen = thumbnail.NewGenerator(cache)
@@ -210,16 +224,16 @@ func (a *App) startup(ctx context.Context) {
 
 // shutdown is called when the app is closing
 func (a *App) shutdown(ctx context.Context) {
-	// Cleanup if needed
+	a.cleanupPhotosExports()
 }
 
 // ResizeWindow adjusts the window height based on whether Mastodon options are shown
 func (a *App) ResizeWindow(showMastodon bool) {
 	// Maintain horizontal layout, adjust height for social media fields
 	if showMastodon {
-		wailsRuntime.WindowSetSize(a.ctx, 900, 600)  // Extra height for social fields
+		a.resizeWindow(900, 600)  // Extra height for social fields
 	} else {
-		wailsRuntime.WindowSetSize(a.ctx, 900, 500)  // Standard height
+		a.resizeWindow(900, 500)  // Standard height
 	}
 }
 
@@ -243,7 +257,7 @@ func (a *App) ResizeWindowForMultiPhoto(photoCount int, showSocial bool) {
 		baseHeight = 800
 	}
 	
-	wailsRuntime.WindowSetSize(a.ctx, 900, baseHeight)
+	a.resizeWindow(900, baseHeight)
 }
 
 // GetSelectedPhoto gets the currently selected photo from Finder/Photos
@@ -308,9 +322,12 @@ func (a *App) GetSelectedPhoto() (*PhotoMetadata, error) {
 			return nil, fmt.Errorf("selected item is a directory, not a file")
 		}
 	} else {
-		// Linux: Could check for nautilus/dolphin selection via DBus
-		// For now, return empty - GUI can show file picker
-		return &PhotoMetadata{}, nil
+		// Linux: use the first file the file manager handed over
+		paths, err := a.selection().SelectedFiles()
+		if err != nil || len(paths) == 0 {
+			return &PhotoMetadata{}, nil
+		}
+		path = paths[0]
 	}
 
 	// Extract EXIF metadata using exiftool
@@ -585,6 +602,29 @@ func (a *App) exportPhotoFromPhotosApp() (string, error) {
 
 // exportPhotoFromPhotosAppByIndex exports a specific photo from Photos.app by index (1-based)
 func (a *App) exportPhotoFromPhotosAppByIndex(photoIndex int) (string, error) {
+	return a.exportPhotosItem(selectionItemScript(photoIndex))
+}
+
+// exportPhotoFromPhotosAppByID exports a photo from Photos.app by its Photos
+// ID, for photos that aren't part of the selection, such as an album's
+func (a *App) exportPhotoFromPhotosAppByID(photoID string) (string, error) {
+	return a.exportPhotosItem(mediaItemScript(photoID))
+}
+
+// exportPhotosItem exports the photo that lookup (see selectionItemScript)
+// sets p to
+func (a *App) exportPhotosItem(lookup string) (string, error) {
+	// Reuse an earlier export of the same, unedited photo
+	photoID, version, err := photosVersion(lookup)
+	if err == nil {
+		if path, ok := a.cachedPhotosExport(photoID, version); ok {
+			fmt.Printf("DEBUG: Reusing Photos export for %s: %s\n", photoID, path)
+			return path, nil
+		}
+	} else {
+		fmt.Printf("DEBUG: Photos export cache unavailable: %v\n", err)
+	}
+	
 	// Create temp directory
 	tempDir, err := os.MkdirTemp("", "imgupv2-photos-*")
 	if err != nil {
@@ -596,20 +636,13 @@ func (a *App) exportPhotoFromPhotosAppByIndex(photoIndex int) (string, error) {
 	set tempFolder to "%s"
 	
 	tell application "Photos"
-		set sel to selection
-		if sel is {} then
-			return "ERROR:No photo selected"
-		end if
-		if (count of sel) < %d then
-			return "ERROR:Photo index %d is out of range"
-		end if
-		set photo to item %d of sel
+%s
 		
 		-- Export with most recent edits
-		export {photo} to (POSIX file tempFolder)
+		export {p} to (POSIX file tempFolder)
 		
 		return "OK"
-	end tell`, tempDir, photoIndex, photoIndex, photoIndex)
+	end tell`, tempDir, lookup)
 	
 	cmd := exec.Command("osascript", "-e", exportScript)
 	out, err := cmd.CombinedOutput()
@@ -624,27 +657,27 @@ func (a *App) exportPhotoFromPhotosAppByIndex(photoIndex int) (string, error) {
 		return "", fmt.Errorf(strings.TrimPrefix(result, "ERROR:"))
 	}
 	
-	// Wait for export to complete
-	time.Sleep(1 * time.Second)
-	
-	// Find the exported file
-	files, err := os.ReadDir(tempDir)
-	if err != nil || len(files) == 0 {
+	// Wait for the export to finish writing
+	exportedPath, err := waitForPhotosExport(tempDir, photosExportTimeout, photosExportInterval)
+	if err != nil {
 		os.RemoveAll(tempDir)
-		return "", fmt.Errorf("no file exported from Photos")
+		return "", err
 	}
-	
-	// Get the most recent file
-	exportedPath := filepath.Join(tempDir, files[0].Name())
 	fmt.Printf("DEBUG: Photos exported file: %s\n", exportedPath)
 	
-	// Check if it's a HEIC file and convert to JPEG if needed
+	// Check if it's a HEIC file and convert to JPEG if needed. With
+	// default.convert_heic off the original is kept, and the CLI converts it
+	// only for services that don't accept HEIC.
 	ext := strings.ToLower(filepath.Ext(exportedPath))
-	if ext == ".heic" || ext == ".heif" {
+	quality, convertHEIC := imageproc.DefaultJPEGQuality, true
+	if cfg, err := config.Load(); err == nil {
+		quality, convertHEIC = imageproc.ResolveJPEGQuality(cfg.Default.JPEGQuality, quality), cfg.ConvertHEICImages()
+	}
+	if (ext == ".heic" || ext == ".heif") && convertHEIC {
 		fmt.Printf("DEBUG: Converting HEIC to JPEG: %s\n", exportedPath)
 		// Convert HEIC to JPEG using sips (built into macOS)
 		jpegPath := strings.TrimSuffix(exportedPath, ext) + ".jpg"
-		cmd := exec.Command("sips", "-s", "format", "jpeg", "-s", "formatOptions", "high", exportedPath, "--out", jpegPath)
+		cmd := exec.Command("sips", "-s", "format", "jpeg", "-s", "formatOptions", fmt.Sprint(quality), exportedPath, "--out", jpegPath)
 		if err := cmd.Run(); err != nil {
 			// Try to continue with HEIC file anyway
 			fmt.Printf("Warning: failed to convert HEIC to JPEG: %v\n", err)
@@ -656,6 +689,12 @@ func (a *App) exportPhotoFromPhotosAppByIndex(photoIndex int) (string, error) {
 		}
 	}
 	
+	// Cached exports are cleaned up at shutdown
+	if photoID != "" {
+		a.storePhotosExport(photoID, version, exportedPath)
+		return exportedPath, nil
+	}
+	
 	// Schedule cleanup after 5 minutes (giving plenty of time for upload)
 	go func(dir string) {
 		time.Sleep(5 * time.Minute)
@@ -677,8 +716,50 @@ func (a *App) GetRecentTags() []string {
 	}
 }
 
+// emitUploadStarted sends the upload-started event for a single-image upload
+func (a *App) emitUploadStarted(path string) {
+	wailsRuntime.EventsEmit(a.ctx, "upload-started", map[string]interface{}{
+		"index": 0,
+		"path": path,
+		"total": 1,
+	})
+}
+
+// emitUploadResult sends upload-completed or upload-failed for a single-image
+// upload, matching the events sent for each image in a batch
+func (a *App) emitUploadResult(metadata PhotoMetadata, result *UploadResult, err error) {
+	if err == nil && result != nil && result.Success {
+		wailsRuntime.EventsEmit(a.ctx, "upload-completed", map[string]interface{}{
+			"index": 0,
+			"path": metadata.Path,
+			"snippet": result.Snippet,
+			"duplicate": result.Duplicate,
+		})
+		return
+	}
+
+	message := "upload failed"
+	if err != nil {
+		message = err.Error()
+	} else if result != nil && result.Error != "" {
+		message = result.Error
+	}
+	wailsRuntime.EventsEmit(a.ctx, "upload-failed", map[string]interface{}{
+		"index": 0,
+		"path": metadata.Path,
+		"error": message,
+	})
+}
+
 // Upload handles the actual upload via imgup CLI
 func (a *App) Upload(metadata PhotoMetadata) (*UploadResult, error) {
+	result, err := a.upload(metadata)
+	a.emitUploadResult(metadata, result, err)
+	return result, err
+}
+
+// upload runs a single-image upload; Upload wraps it with progress events
+func (a *App) upload(metadata PhotoMetadata) (*UploadResult, error) {
 	// If this is from Photos.app and path is still empty, wait a bit or export now
 	if metadata.IsFromPhotos && metadata.Path == "" {
 		// Check if an export is already in progress by waiting briefly
@@ -697,8 +778,9 @@ func (a *App) Upload(metadata PhotoMetadata) (*UploadResult, error) {
 			metadata.IsTemporary = true
 		}
 		
-		// Re-embed metadata using exiftool if we have any
-		if metadata.Title != "" || metadata.Description != "" || len(metadata.Tags) > 0 {
+		// Re-embed metadata using exiftool if we have any, unless
+		// Don't embed metadata is checked for this upload
+		if (metadata.Title != "" || metadata.Description != "" || len(metadata.Tags) > 0) && !metadata.NoEmbedMetadata {
 			// Find exiftool with full path
 			exiftoolPath := "/usr/local/bin/exiftool"
 			if _, err := os.Stat(exiftoolPath); err != nil {
@@ -797,6 +879,14 @@ func (a *App) Upload(metadata PhotoMetadata) (*UploadResult, error) {
 		args = append(args, "--post", postText)
 	}
 
+	// Store the Photos filename rather than the temporary export's
+	if metadata.PhotosFilename != "" {
+		args = append(args, "--remote-filename", metadata.PhotosFilename)
+	}
+	if metadata.NoEmbedMetadata {
+		args = append(args, "--no-embed-metadata")
+	}
+
 	// Add the file path at the end
 	args = append(args, metadata.Path)
 
@@ -820,10 +910,9 @@ func (a *App) Upload(metadata PhotoMetadata) (*UploadResult, error) {
 	}
 
 	// Run imgup CLI
-	cmd := exec.Command(imgupPath, args...)
-	
-	// Use Output() which waits for the command to complete
-	output, err := cmd.Output()
+	a.emitUploadStarted(metadata.Path)
+	// Wait for the command to complete, retrying uploads that fail in transit
+	output, err := runImgup(imgupPath, args, false)
 	if err != nil {
 		// Get stderr if available
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -871,6 +960,7 @@ func (a *App) Upload(metadata PhotoMetadata) (*UploadResult, error) {
 				Duplicate bool   `json:"duplicate"`
 				PhotoID   string `json:"photoId"`
 				ImageURL  string `json:"imageUrl,omitempty"`
+				Service   string `json:"service"`
 			}
 			if err := json.Unmarshal([]byte(jsonLine), &jsonResponse); err == nil {
 				isDuplicate = jsonResponse.Duplicate
@@ -903,37 +993,14 @@ func (a *App) Upload(metadata PhotoMetadata) (*UploadResult, error) {
 					}
 				}
 				
-				// Convert to requested format
-				switch metadata.Format {
-				case "url":
-					snippet = jsonResponse.URL
-				case "markdown":
-					// Use imageURL if available, fall back to URL
-					imageURL := jsonResponse.ImageURL
-					if imageURL == "" {
-						imageURL = jsonResponse.URL
-					}
-					// Basic markdown format
-					title := metadata.Title
-					if title == "" {
-						title = "Image"
-					}
-					snippet = fmt.Sprintf("![%s](%s)", title, imageURL)
-				case "html":
-					// Basic HTML format
-					altText := metadata.Alt
-					if altText == "" {
-						altText = metadata.Title
-						if altText == "" {
-							altText = "Image"
-						}
-					}
-					snippet = fmt.Sprintf(`<img src="%s" alt="%s">`, jsonResponse.URL, altText)
-				case "json":
+				// Render the requested format with the same templates as the CLI
+				if metadata.Format == "json" {
 					// Keep the original JSON
 					snippet = jsonLine
-				default:
-					// Default to URL
+				} else if rendered, err := renderSnippet(metadata, jsonResponse.Service, jsonResponse.PhotoID, jsonResponse.URL, jsonResponse.ImageURL); err == nil {
+					snippet = rendered
+				} else {
+					fmt.Fprintf(os.Stderr, "Failed to render %s snippet: %v\n", metadata.Format, err)
 					snippet = jsonResponse.URL
 				}
 			} else {
@@ -967,6 +1034,13 @@ func fileExists(path string) bool {
 
 // ForceUpload handles upload with --force flag for duplicates
 func (a *App) ForceUpload(metadata PhotoMetadata) (*UploadResult, error) {
+	result, err := a.forceUpload(metadata)
+	a.emitUploadResult(metadata, result, err)
+	return result, err
+}
+
+// forceUpload runs a single-image upload with --force; ForceUpload wraps it with progress events
+func (a *App) forceUpload(metadata PhotoMetadata) (*UploadResult, error) {
 	// If this is from Photos.app and hasn't been exported yet, export it now
 	if metadata.IsFromPhotos && metadata.Path == "" {
 		exportPath, err := a.exportPhotoFromPhotosApp()
@@ -979,8 +1053,9 @@ func (a *App) ForceUpload(metadata PhotoMetadata) (*UploadResult, error) {
 		metadata.Path = exportPath
 		metadata.IsTemporary = true
 		
-		// Re-embed metadata using exiftool if we have any
-		if metadata.Title != "" || metadata.Description != "" || len(metadata.Tags) > 0 {
+		// Re-embed metadata using exiftool if we have any, unless
+		// Don't embed metadata is checked for this upload
+		if (metadata.Title != "" || metadata.Description != "" || len(metadata.Tags) > 0) && !metadata.NoEmbedMetadata {
 			// Find exiftool with full path
 			exiftoolPath := "/usr/local/bin/exiftool"
 			if _, err := os.Stat(exiftoolPath); err != nil {
@@ -1079,6 +1154,14 @@ func (a *App) ForceUpload(metadata PhotoMetadata) (*UploadResult, error) {
 		args = append(args, "--post", postText)
 	}
 
+	// Store the Photos filename rather than the temporary export's
+	if metadata.PhotosFilename != "" {
+		args = append(args, "--remote-filename", metadata.PhotosFilename)
+	}
+	if metadata.NoEmbedMetadata {
+		args = append(args, "--no-embed-metadata")
+	}
+
 	// Add the file path at the end
 	args = append(args, metadata.Path)
 
@@ -1102,10 +1185,9 @@ func (a *App) ForceUpload(metadata PhotoMetadata) (*UploadResult, error) {
 	}
 
 	// Run imgup CLI
-	cmd := exec.Command(imgupPath, args...)
-	
-	// Use Output() which waits for the command to complete
-	output, err := cmd.Output()
+	a.emitUploadStarted(metadata.Path)
+	// Wait for the command to complete, retrying uploads that fail in transit
+	output, err := runImgup(imgupPath, args, false)
 	if err != nil {
 		// Get stderr if available
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -1150,39 +1232,17 @@ func (a *App) ForceUpload(metadata PhotoMetadata) (*UploadResult, error) {
 				Duplicate bool   `json:"duplicate"`
 				PhotoID   string `json:"photoId"`
 				ImageURL  string `json:"imageUrl,omitempty"`
+				Service   string `json:"service"`
 			}
 			if err := json.Unmarshal([]byte(jsonLine), &jsonResponse); err == nil {
-				// Convert to requested format
-				switch metadata.Format {
-				case "url":
-					snippet = jsonResponse.URL
-				case "markdown":
-					// Use imageURL if available, fall back to URL
-					imageURL := jsonResponse.ImageURL
-					if imageURL == "" {
-						imageURL = jsonResponse.URL
-					}
-					// Basic markdown format
-					title := metadata.Title
-					if title == "" {
-						title = "Image"
-					}
-					snippet = fmt.Sprintf("![%s](%s)", title, imageURL)
-				case "html":
-					// Basic HTML format
-					altText := metadata.Alt
-					if altText == "" {
-						altText = metadata.Title
-						if altText == "" {
-							altText = "Image"
-						}
-					}
-					snippet = fmt.Sprintf(`<img src="%s" alt="%s">`, jsonResponse.URL, altText)
-				case "json":
+				// Render the requested format with the same templates as the CLI
+				if metadata.Format == "json" {
 					// Keep the original JSON
 					snippet = jsonLine
-				default:
-					// Default to URL
+				} else if rendered, err := renderSnippet(metadata, jsonResponse.Service, jsonResponse.PhotoID, jsonResponse.URL, jsonResponse.ImageURL); err == nil {
+					snippet = rendered
+				} else {
+					fmt.Fprintf(os.Stderr, "Failed to render %s snippet: %v\n", metadata.Format, err)
 					snippet = jsonResponse.URL
 				}
 			} else {
@@ -1503,6 +1563,8 @@ func (a *App) HandlePullRequest(pullJSON string) error {
 		"service":    pullReq.Source.Service,
 		"album":      pullReq.Source.Album,
 		"postText":   pullReq.Post,
+		"mastodonPost": pullReq.PostFor("mastodon"),
+		"blueskyPost":  pullReq.PostFor("bluesky"),
 		"targets":    pullReq.Targets,
 		"visibility": pullReq.Visibility,
 		"format":     pullReq.Format,
@@ -1558,6 +1620,12 @@ func (a *App) downloadPullThumbnails(photos []PullPhotoData) {
 	}
 }
 
+// emitPullProgress sends a pull-progress event for one step of posting
+// pulled images
+func (a *App) emitPullProgress(step types.PullProgress) {
+	wailsRuntime.EventsEmit(a.ctx, "pull-progress", step)
+}
+
 // PostPullSelection handles the social media posting for selected pull images
 func (a *App) PostPullSelection(request types.PullRequest) (*MultiPhotoUploadResult, error) {
 	fmt.Printf("DEBUG: PostPullSelection called with %d images, targets: %v\n", len(request.Images), request.Targets)
@@ -1603,14 +1671,18 @@ func (a *App) PostPullSelection(request types.PullRequest) (*MultiPhotoUploadRes
 				cfg.Mastodon.ClientSecret,
 				cfg.Mastodon.AccessToken,
 			)
+			mastodonClient.HashtagStyle = cfg.Default.HashtagStyle
 		}
 		
 		if target == "bluesky" && blueskyClient == nil {
 			blueskyClient = bluesky.NewClient(
-				"", // Uses default bsky.social
+				cfg.Bluesky.PDS,
 				cfg.Bluesky.Handle,
 				cfg.Bluesky.AppPassword,
 			)
+			blueskyClient.JPEGQuality = cfg.Default.SocialJPEGQuality
+			blueskyClient.KeepEXIF = cfg.Default.SocialKeepEXIF
+			blueskyClient.HashtagStyle = cfg.Default.HashtagStyle
 			if err := blueskyClient.Authenticate(); err != nil {
 				return &MultiPhotoUploadResult{
 					Success: false,
@@ -1666,37 +1738,54 @@ func (a *App) PostPullSelection(request types.PullRequest) (*MultiPhotoUploadRes
 	// Upload to Mastodon if needed
 	if mastodonClient != nil && contains(request.Targets, "mastodon") {
 		fmt.Println("Uploading to Mastodon...")
-		for _, img := range request.Images {
+		for i, img := range request.Images {
 			imageURL := selectImageSize(img.Sizes, "")
 			fmt.Printf("  Uploading %s...", img.Title)
+			step := types.PullProgress{Event: types.PullUploading, Service: "mastodon", Image: i + 1, Title: img.Title}
+			a.emitPullProgress(step)
 			if imageURL == "" {
 				fmt.Printf(" skipped: no image URL available\n")
+				step.Event, step.Error = types.PullFailed, "no image URL available"
+				a.emitPullProgress(step)
 				continue
 			}
 			mediaID, err := mastodonClient.UploadMediaFromURL(imageURL, img.Alt)
 			if err != nil {
 				fmt.Printf(" failed: %v\n", err)
+				step.Event, step.Error = types.PullFailed, err.Error()
+				a.emitPullProgress(step)
 				continue
 			}
 			mastodonMediaIDs = append(mastodonMediaIDs, mediaID)
 			fmt.Printf(" done\n")
+			step.Event = types.PullUploaded
+			a.emitPullProgress(step)
 		}
 	}
 	
 	// Upload to Bluesky if needed
 	if blueskyClient != nil && contains(request.Targets, "bluesky") {
 		fmt.Println("Uploading to Bluesky...")
-		for _, img := range request.Images {
+		for i, img := range request.Images {
 			imageURL := selectImageSize(img.Sizes, "")
 			fmt.Printf("  Uploading %s...", img.Title)
+			step := types.PullProgress{Event: types.PullUploading, Service: "bluesky", Image: i + 1, Title: img.Title}
+			a.emitPullProgress(step)
 			blob, altText, err := blueskyClient.UploadMediaFromURL(imageURL, img.Alt)
 			if err != nil {
 				fmt.Printf(" failed: %v\n", err)
+				step.Event, step.Error = types.PullFailed, err.Error()
+				a.emitPullProgress(step)
 				continue
 			}
 			blueskyBlobs = append(blueskyBlobs, *blob)
 			blueskyAltTexts = append(blueskyAltTexts, altText)
 			fmt.Printf(" done\n")
+			for _, warning := range blob.Warnings {
+				fmt.Printf("  Warning: %s\n", warning)
+			}
+			step.Event = types.PullUploaded
+			a.emitPullProgress(step)
 		}
 	}
 	
@@ -1720,11 +1809,19 @@ func (a *App) PostPullSelection(request types.PullRequest) (*MultiPhotoUploadRes
 	// Post to Mastodon
 	if mastodonClient != nil && len(mastodonMediaIDs) > 0 {
 		fmt.Print("Posting to Mastodon...")
+		step := types.PullProgress{Event: types.PullPosting, Service: "mastodon"}
+		a.emitPullProgress(step)
 		visibility := request.Visibility
 		if visibility == "" {
 			visibility = "public"
 		}
-		err := mastodonClient.PostStatus(request.Post, mastodonMediaIDs, visibility, uniqueTags)
+		err := mastodonClient.PostStatus(request.PostFor("mastodon"), mastodonMediaIDs, visibility, uniqueTags)
+		if err != nil {
+			step.Event, step.Error = types.PullPostFailed, err.Error()
+		} else {
+			step.Event = types.PullPosted
+		}
+		a.emitPullProgress(step)
 		if err != nil {
 			errMsg := fmt.Sprintf("Mastodon failed: %v", err)
 			fmt.Printf(" %s\n", errMsg)
@@ -1744,7 +1841,15 @@ func (a *App) PostPullSelection(request types.PullRequest) (*MultiPhotoUploadRes
 	// Post to Bluesky
 	if blueskyClient != nil && len(blueskyBlobs) > 0 {
 		fmt.Print("Posting to Bluesky...")
-		err := blueskyClient.PostStatus(request.Post, blueskyBlobs, blueskyAltTexts, uniqueTags)
+		step := types.PullProgress{Event: types.PullPosting, Service: "bluesky"}
+		a.emitPullProgress(step)
+		err := blueskyClient.PostStatus(request.PostFor("bluesky"), blueskyBlobs, blueskyAltTexts, uniqueTags)
+		if err != nil {
+			step.Event, step.Error = types.PullPostFailed, err.Error()
+		} else {
+			step.Event = types.PullPosted
+		}
+		a.emitPullProgress(step)
 		if err != nil {
 			errMsg := fmt.Sprintf("Bluesky failed: %v", err)
 			fmt.Printf(" %s\n", errMsg)
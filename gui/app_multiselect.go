@@ -22,9 +22,8 @@ func (a *App) GetSelectedPhotos() ([]PhotoMetadata, error) {
 	}
 	
 	if runtime.GOOS != "darwin" {
-		// Linux: Could check for nautilus/dolphin selection via DBus
-		// For now, return empty
-		return nil, fmt.Errorf("multi-selection not implemented for Linux")
+		// Linux: use whatever the file manager handed over
+		return a.getFileManagerSelections()
 	}
 
 	// First check if Photos has a selection
@@ -50,17 +49,22 @@ func (a *App) GetSelectedPhotos() ([]PhotoMetadata, error) {
 	}
 	
 	// Otherwise try Finder
-	return a.getMultipleFinderSelections()
+	return a.getFileManagerSelections()
 }
 
 // getMultiplePhotosMetadata gets metadata for all selected photos in Photos.app
 func (a *App) getMultiplePhotosMetadata() ([]PhotoMetadata, error) {
-	// AppleScript to get metadata for ALL selected photos
+	return photosMetadata("selection", "No photos selected")
+}
+
+// photosMetadata gets metadata for the Photos.app media items the AppleScript
+// expression items evaluates to, failing with empty when there are none
+func photosMetadata(items, empty string) ([]PhotoMetadata, error) {
 	metadataScript := `
 	tell application "Photos"
-		set sel to selection
+		set sel to ` + items + `
 		if sel is {} then
-			return "ERROR:No photos selected"
+			return "ERROR:` + empty + `"
 		end if
 		
 		set allResults to {}
@@ -197,49 +201,13 @@ func (a *App) getMultiplePhotosMetadata() ([]PhotoMetadata, error) {
 	return photos, nil
 }
 
-// getMultipleFinderSelections gets all selected files from Finder
-func (a *App) getMultipleFinderSelections() ([]PhotoMetadata, error) {
-	script := `
-	tell application "Finder"
-		set theSelection to selection
-		if length of theSelection is 0 then
-			return ""
-		end if
-		
-		set allPaths to {}
-		repeat with theFile in theSelection
-			set thePath to POSIX path of (theFile as alias)
-			-- Remove trailing slash if it's there
-			if thePath ends with "/" then
-				set thePath to text 1 thru -2 of thePath
-			end if
-			
-			-- Check if it's a file (not directory) by looking for extension
-			if thePath contains "." then
-				copy thePath to end of allPaths
-			end if
-		end repeat
-		
-		-- Join paths with newline
-		set AppleScript's text item delimiters to "\n"
-		set pathList to (allPaths as string)
-		set AppleScript's text item delimiters to ""
-		
-		return pathList
-	end tell`
-
-	cmd := exec.Command("osascript", "-e", script)
-	out, err := cmd.Output()
+// getFileManagerSelections gets metadata for all files selected in the file manager
+func (a *App) getFileManagerSelections() ([]PhotoMetadata, error) {
+	paths, err := a.selection().SelectedFiles()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get Finder selection: %w", err)
-	}
-	
-	pathList := strings.TrimSpace(string(out))
-	if pathList == "" {
-		return nil, fmt.Errorf("no files selected in Finder")
+		return nil, err
 	}
 	
-	paths := strings.Split(pathList, "\n")
 	var photos []PhotoMetadata
 	
 	// Process each file
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/render"
+	"github.com/pdxmph/imgupv2/pkg/templates"
+)
+
+// Placeholder values shown in snippet previews until the photo is uploaded
+const (
+	previewPhotoID  = "PHOTO_ID"
+	previewURL      = "https://example.com/photos/PHOTO_ID"
+	previewImageURL = "https://example.com/images/PHOTO_ID.jpg"
+)
+
+// PreviewSnippet renders the output snippet for metadata with placeholder
+// URLs, so the user can see what they'll get before uploading
+func (a *App) PreviewSnippet(metadata PhotoMetadata) (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	return renderSnippet(metadata, cfg.Default.Service, previewPhotoID, previewURL, previewImageURL)
+}
+
+// renderSnippet renders metadata.Format with the configured template, the
+// same way the CLI renders --format, so previews, uploads and the CLI agree.
+// service is the one the photo was uploaded to, for %edit_url%.
+func renderSnippet(metadata PhotoMetadata, service, photoID, url, imageURL string) (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	format := metadata.Format
+	if format == "" {
+		format = "markdown"
+	}
+
+	filename := metadata.PhotosFilename
+	if filename == "" {
+		filename = metadata.Path
+	}
+
+	vars := templates.Variables{
+		PhotoID:     photoID,
+		URL:         url,
+		ImageURL:    imageURL,
+		EditURL:     render.EditURL(service, photoID),
+		Filename:    render.Filename(filename, cfg.Default.SanitizeFilename),
+		Title:       metadata.Title,
+		Description: metadata.Description,
+		Alt:         metadata.Alt,
+		Tags:        metadata.Tags,
+	}
+
+	return render.Snippet(format, vars, cfg)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"github.com/pdxmph/imgupv2/pkg/config"
+)
+
+// GetConvertHEIC returns whether Photos exports in HEIC are converted to JPEG
+// for every service
+func (a *App) GetConvertHEIC() bool {
+	cfg, err := config.Load()
+	if err != nil {
+		return true
+	}
+	return cfg.ConvertHEICImages()
+}
+
+// SetConvertHEIC saves default.convert_heic. It applies to photos exported
+// from now on.
+func (a *App) SetConvertHEIC(convert bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	cfg.Default.ConvertHEIC = &convert
+	return cfg.Save()
+}
+
+// GetEmbedMetadata returns default.embed_metadata, the starting state of
+// the Don't embed metadata checkbox. Each upload passes the checkbox along.
+func (a *App) GetEmbedMetadata() bool {
+	cfg, err := config.Load()
+	if err != nil {
+		return true
+	}
+	return cfg.EmbedMetadataInFiles()
+}
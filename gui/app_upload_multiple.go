@@ -5,14 +5,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 	
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
-	"github.com/pdxmph/imgupv2/pkg/config"
-	"github.com/pdxmph/imgupv2/pkg/templates"
 )
 
 // UploadMultiplePhotos handles uploading multiple photos with shared metadata
@@ -90,8 +87,15 @@ func (a *App) UploadMultiplePhotos(request MultiPhotoUploadRequest) (*MultiPhoto
 		imagePath := img.Path
 		
 		if img.IsFromPhotos && imagePath == "" {
-			// Export the photo from Photos.app using 1-based index
-			exportPath, err := a.exportPhotoFromPhotosAppByIndex(img.PhotosIndex)
+			// Export the photo from Photos.app using its 1-based index in the
+			// selection, or by ID for photos from outside it (PhotosIndex 0)
+			var exportPath string
+			var err error
+			if img.PhotosIndex == 0 && img.PhotosID != "" {
+				exportPath, err = a.exportPhotoFromPhotosAppByID(img.PhotosID)
+			} else {
+				exportPath, err = a.exportPhotoFromPhotosAppByIndex(img.PhotosIndex)
+			}
 			if err != nil {
 				// Add failed result
 				result.Outputs = append(result.Outputs, MultiPhotoOutputResult{
@@ -111,8 +115,9 @@ func (a *App) UploadMultiplePhotos(request MultiPhotoUploadRequest) (*MultiPhoto
 			imagePath = exportPath
 			tempFiles = append(tempFiles, exportPath) // Track for cleanup
 			
-			// Re-embed metadata using exiftool if we have any
-			if img.Title != "" || img.Description != "" || len(request.Tags) > 0 {
+			// Re-embed metadata using exiftool if we have any, unless
+			// Don't embed metadata is checked for this upload
+			if (img.Title != "" || img.Description != "" || len(request.Tags) > 0) && !request.NoEmbedMetadata {
 				exiftoolPath := a.findExiftoolBinary()
 				
 				exifArgs := []string{"-overwrite_original"}
@@ -154,6 +159,9 @@ func (a *App) UploadMultiplePhotos(request MultiPhotoUploadRequest) (*MultiPhoto
 		if img.Description != "" {
 			imageData["description"] = img.Description
 		}
+		if img.PhotosFilename != "" {
+			imageData["remote_filename"] = img.PhotosFilename
+		}
 		
 		jsonRequest["images"] = append(jsonRequest["images"].([]map[string]interface{}), imageData)
 		
@@ -199,12 +207,11 @@ func (a *App) UploadMultiplePhotos(request MultiPhotoUploadRequest) (*MultiPhoto
 	imgupPath := a.findImgupBinary()
 	
 	// Run imgup CLI with JSON file
-	cmd := exec.Command(imgupPath, "upload", "--json-file", jsonFile.Name())
 	fmt.Printf("DEBUG: Executing command: %s upload --json-file %s\n", imgupPath, jsonFile.Name())
 	fmt.Printf("DEBUG: JSON content:\n%s\n", string(jsonData))
 	
-	// Capture both stdout and stderr
-	output, err := cmd.CombinedOutput()
+	// Capture both stdout and stderr, retrying transient failures
+	output, err := runImgup(imgupPath, batchArgs(jsonFile.Name(), request.NoEmbedMetadata), true)
 	outputStr := string(output)
 	fmt.Printf("DEBUG: Raw output:\n%s\n", outputStr)
 	
@@ -225,6 +232,7 @@ func (a *App) UploadMultiplePhotos(request MultiPhotoUploadRequest) (*MultiPhoto
 			URL       string   `json:"url"`
 			ImageURL  string   `json:"imageUrl"`
 			PhotoID   string   `json:"photoId"`
+			Service   string   `json:"service"`
 			Duplicate bool     `json:"duplicate"`
 			Error     *string  `json:"error"`
 			Warnings  []string `json:"warnings"`
@@ -304,46 +312,24 @@ func (a *App) UploadMultiplePhotos(request MultiPhotoUploadRequest) (*MultiPhoto
 				// Debug: Check what URLs we have
 				fmt.Printf("DEBUG: Format=%s, URL=%s, ImageURL=%s\n", request.Format, upload.URL, upload.ImageURL)
 				
-				// Load config to get templates
-				cfg, err := config.Load()
+				// Render with the same templates as the CLI and single uploads
+				metadata := PhotoMetadata{
+					Path:        request.Images[i].Path,
+					Title:       request.Images[i].Title,
+					Description: request.Images[i].Description,
+					Alt:         request.Images[i].Alt,
+					Tags:        request.Tags,
+					Format:      request.Format,
+				}
+				snippet, err := renderSnippet(metadata, upload.Service, upload.PhotoID, upload.URL, upload.ImageURL)
 				if err != nil {
-					// If config fails to load, continue without templates
-					fmt.Printf("ERROR: Failed to load config for templates: %v\n", err)
-				} else {
-					fmt.Printf("DEBUG: Config loaded, Templates=%v\n", cfg.Templates)
-					if cfg.Templates != nil {
-						// Create template variables
-						vars := templates.Variables{
-							PhotoID:     upload.PhotoID,
-							URL:         upload.URL,      // Photo page URL
-							ImageURL:    upload.ImageURL, // Direct image URL (this is what we want!)
-							Filename:    filepath.Base(request.Images[i].Path),
-							Title:       request.Images[i].Title,
-							Description: request.Images[i].Description,
-							Alt:         request.Images[i].Alt,
-						}
-						
-						fmt.Printf("DEBUG: Template vars - ImageURL=%s, Alt=%s\n", vars.ImageURL, vars.Alt)
-						
-						// Debug: Show what template we're using
-						if tmpl, ok := cfg.Templates[request.Format]; ok {
-							fmt.Printf("DEBUG: Using template for %s: %s\n", request.Format, tmpl)
-							
-							// Process the template for the requested format
-							switch request.Format {
-							case "markdown":
-								output.Markdown = templates.Process(tmpl, vars)
-								fmt.Printf("DEBUG: Processed markdown: %s\n", output.Markdown)
-							case "html":
-								output.HTML = templates.Process(tmpl, vars)
-								fmt.Printf("DEBUG: Processed HTML: %s\n", output.HTML)
-							}
-						} else {
-							fmt.Printf("ERROR: No template found for format %s\n", request.Format)
-						}
-					} else {
-						fmt.Printf("ERROR: Templates is nil in config\n")
-					}
+					fmt.Printf("ERROR: Failed to render %s snippet: %v\n", request.Format, err)
+				}
+				switch request.Format {
+				case "markdown":
+					output.Markdown = snippet
+				case "html":
+					output.HTML = snippet
 				}
 			}
 			
@@ -398,3 +384,12 @@ func (a *App) UploadMultiplePhotos(request MultiPhotoUploadRequest) (*MultiPhoto
 	
 	return result, nil
 }
+
+// batchArgs builds the imgup command line for a multi-photo batch file
+func batchArgs(jsonFile string, noEmbedMetadata bool) []string {
+	args := []string{"upload", "--json-file", jsonFile}
+	if noEmbedMetadata {
+		args = append(args, "--no-embed-metadata")
+	}
+	return args
+}
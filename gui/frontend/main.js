@@ -20,6 +20,13 @@ document.addEventListener('DOMContentLoaded', async () => {
     // Set up tag autocomplete
     setupTagAutocomplete();
     
+    // Keep the snippet preview in step with the form
+    setupSnippetPreview();
+    
+    // Reflect and save the HEIC conversion setting
+    setupKeepHEIC();
+    setupKeepFile();
+    
     // Handle form submission
     document.getElementById('upload-form').onsubmit = handleUpload;
     
@@ -135,6 +142,7 @@ function setupEventListeners() {
         if (data.tags && data.tags.length > 0 && !document.getElementById('tags').value) {
             document.getElementById('tags').value = data.tags.join(' ');
         }
+        updateSnippetPreview();
     });
     
     // Handle Escape key
@@ -295,6 +303,46 @@ function setupEventListeners() {
         }
     });
     
+    // Listen for single-image upload progress (batch uploads report through the same events)
+    window.runtime.EventsOn('upload-started', (data) => {
+        console.log('Upload started:', data);
+        if (!window.multiPhotoData) {
+            showProgress('Uploading...');
+        }
+    });
+    
+    window.runtime.EventsOn('upload-completed', (data) => {
+        console.log('Upload completed:', data);
+        if (!window.multiPhotoData) {
+            showProgress(data.duplicate ? 'Already uploaded' : 'Uploaded');
+        }
+    });
+    
+    window.runtime.EventsOn('upload-failed', (data) => {
+        console.log('Upload failed:', data);
+    });
+    
+    // Listen for per-image progress while pulled photos are posted
+    window.runtime.EventsOn('pull-progress', (data) => {
+        console.log('Pull progress:', data);
+        const service = data.service === 'bluesky' ? 'Bluesky' : 'Mastodon';
+        const total = window.multiPhotoData ? window.multiPhotoData.length : 0;
+        switch (data.event) {
+            case 'uploading':
+                showProgress(`Sending photo ${data.image} of ${total} to ${service}...`);
+                break;
+            case 'failed':
+                showProgress(`Photo ${data.image} failed on ${service}: ${data.error}`);
+                break;
+            case 'posting':
+                showProgress(`Posting to ${service}...`);
+                break;
+            case 'post_failed':
+                showProgress(`${service} post failed: ${data.error}`);
+                break;
+        }
+    });
+    
     // Listen for pull mode starting - prevents normal photo loading
     window.runtime.EventsOn('pull-mode-starting', () => {
         console.log('Pull mode starting, skipping normal photo load');
@@ -413,8 +461,8 @@ function initializePullMode(data) {
         if (data.targets.includes('mastodon')) {
             document.getElementById('mastodon-enabled').checked = true;
             document.getElementById('mastodon-options').classList.remove('hidden');
-            if (data.postText) {
-                document.getElementById('mastodon-text').value = data.postText;
+            if (data.mastodonPost) {
+                document.getElementById('mastodon-text').value = data.mastodonPost;
             }
             if (data.visibility) {
                 document.getElementById('mastodon-visibility').value = data.visibility;
@@ -424,8 +472,8 @@ function initializePullMode(data) {
         if (data.targets.includes('bluesky')) {
             document.getElementById('bluesky-enabled').checked = true;
             document.getElementById('bluesky-options').classList.remove('hidden');
-            if (data.postText) {
-                document.getElementById('bluesky-text').value = data.postText;
+            if (data.blueskyPost) {
+                document.getElementById('bluesky-text').value = data.blueskyPost;
             }
         }
     }
@@ -441,6 +489,12 @@ function initializePullMode(data) {
     if (privateContainer) {
         privateContainer.style.display = 'none';
     }
+    
+    // Hide HEIC toggle - pulled photos are already on the service
+    const keepHEICContainer = document.getElementById('keep-heic')?.parentElement?.parentElement;
+    if (keepHEICContainer) {
+        keepHEICContainer.style.display = 'none';
+    }
 }
 
 // Show UI for pull photo selection (adapted from showMultiPhotoUI)
@@ -678,6 +732,7 @@ function populateForm(metadata) {
     
     // Always call loadPreview to handle all cases (thumbnail, loading, Photos)
     loadPreview(metadata);
+    updateSnippetPreview();
     
     // For Photos.app selections without a thumbnail, trigger thumbnail generation
     if (metadata.isFromPhotos && !metadata.thumbnail) {
@@ -781,6 +836,90 @@ function formatFileSize(bytes) {
     return `${size.toFixed(1)} ${units[unitIndex]}`;
 }
 
+// Refresh the snippet preview whenever metadata or format changes
+function setupSnippetPreview() {
+    let timer = null;
+    const schedule = () => {
+        clearTimeout(timer);
+        timer = setTimeout(updateSnippetPreview, 250);
+    };
+    
+    ['title', 'alt', 'description', 'tags'].forEach(id => {
+        document.getElementById(id).addEventListener('input', schedule);
+    });
+    document.getElementById('format').addEventListener('change', updateSnippetPreview);
+}
+
+// Keep HEIC saves default.convert_heic; it applies to the next export from Photos
+async function setupKeepHEIC() {
+    const checkbox = document.getElementById('keep-heic');
+    if (!checkbox) return;
+    
+    try {
+        checkbox.checked = !(await window.go.main.App.GetConvertHEIC());
+    } catch (err) {
+        console.error('Failed to load HEIC setting:', err);
+    }
+    
+    checkbox.addEventListener('change', async (e) => {
+        try {
+            await window.go.main.App.SetConvertHEIC(!e.target.checked);
+        } catch (err) {
+            console.error('Failed to save HEIC setting:', err);
+            e.target.checked = !e.target.checked;
+        }
+    });
+}
+
+// Don't embed metadata starts from default.embed_metadata and is sent
+// with each upload; changing it doesn't change the config
+async function setupKeepFile() {
+    const checkbox = document.getElementById('keep-file');
+    if (!checkbox) return;
+    
+    try {
+        checkbox.checked = !(await window.go.main.App.GetEmbedMetadata());
+    } catch (err) {
+        console.error('Failed to load embed metadata setting:', err);
+    }
+}
+
+// noEmbedMetadata reports whether Don't embed metadata is checked
+function noEmbedMetadata() {
+    const checkbox = document.getElementById('keep-file');
+    return checkbox ? checkbox.checked : false;
+}
+
+// Render the snippet the current form would produce, with placeholder URLs
+async function updateSnippetPreview() {
+    const preview = document.getElementById('snippet-preview');
+    if (!preview) return;
+    
+    // Pull mode always posts to social, and multi-photo mode has no single snippet
+    if (window.multiPhotoData || !currentPhotoMetadata) {
+        preview.classList.add('hidden');
+        return;
+    }
+    
+    const metadata = {
+        path: currentPhotoMetadata.path || '',
+        photosFilename: currentPhotoMetadata.photosFilename || '',
+        title: document.getElementById('title').value.trim(),
+        alt: document.getElementById('alt').value.trim(),
+        description: document.getElementById('description').value.trim(),
+        tags: document.getElementById('tags').value.split(/\s+/).filter(t => t),
+        format: document.getElementById('format').value
+    };
+    
+    try {
+        preview.textContent = await window.go.main.App.PreviewSnippet(metadata);
+        preview.classList.remove('hidden');
+    } catch (err) {
+        console.error('Failed to preview snippet:', err);
+        preview.classList.add('hidden');
+    }
+}
+
 async function setupTagAutocomplete() {
     const tagsInput = document.getElementById('tags');
     const suggestionsDiv = document.getElementById('tag-suggestions');
@@ -865,7 +1004,8 @@ async function handleUpload(e) {
         mastodonText: form['mastodon-text'].value.trim(),
         mastodonVisibility: form['mastodon-visibility'].value,
         blueskyEnabled: form['bluesky-enabled'].checked,
-        blueskyText: form['bluesky-text'].value.trim()
+        blueskyText: form['bluesky-text'].value.trim(),
+        noEmbedMetadata: noEmbedMetadata()
     };
     
     // Show progress with appropriate message
@@ -1011,9 +1151,11 @@ async function handleMultiPhotoUpload() {
                 description: photo.description || '',
                 isFromPhotos: photo.isFromPhotos || false,
                 photosIndex: photo.photosIndex || 0,
-                photosId: photo.photosId || ''
+                photosId: photo.photosId || '',
+                photosFilename: photo.photosFilename || ''
             })),
             tags: [], // Collect common tags if needed
+            noEmbedMetadata: noEmbedMetadata(),
             mastodon: socialPost.mastodonEnabled,
             bluesky: socialPost.blueskyEnabled,
             visibility: socialPost.mastodonVisibility || 'public',
@@ -1039,6 +1181,8 @@ async function handleMultiPhotoUpload() {
                     album: window.pullModeData.album
                 },
                 post: uploadData.post,
+                mastodon_post: socialPost.mastodonText,
+                bluesky_post: socialPost.blueskyText,
                 images: window.multiPhotoData.map((photo, idx) => {
                     console.log(`DEBUG: Photo ${idx} imageUrls:`, photo.imageUrls);
                     console.log(`DEBUG: Photo ${idx} full object:`, photo);
@@ -5,6 +5,14 @@ import {types} from '../models';
 
 export function ForceUpload(arg1:main.PhotoMetadata):Promise<main.UploadResult>;
 
+export function GetConvertHEIC():Promise<boolean>;
+
+export function GetEmbedMetadata():Promise<boolean>;
+
+export function GetPhotosAlbumPhotos(arg1:string):Promise<Array<main.PhotoMetadata>>;
+
+export function GetPhotosAlbums():Promise<Array<main.PhotosAlbum>>;
+
 export function GetRecentTags():Promise<Array<string>>;
 
 export function GetSelectedPhoto():Promise<main.PhotoMetadata>;
@@ -15,10 +23,15 @@ export function HandlePullRequest(arg1:string):Promise<void>;
 
 export function PostPullSelection(arg1:types.PullRequest):Promise<main.MultiPhotoUploadResult>;
 
+export function PreviewSnippet(arg1:main.PhotoMetadata):Promise<string>;
+
 export function ResizeWindow(arg1:boolean):Promise<void>;
 
 export function ResizeWindowForMultiPhoto(arg1:number,arg2:boolean):Promise<void>;
 
+export function SetConvertHEIC(arg1:boolean):Promise<void>;
+
+
 export function StartThumbnailGeneration(arg1:Array<main.PhotoMetadata>):Promise<void>;
 
 export function TestMultiSelect():Promise<string>;
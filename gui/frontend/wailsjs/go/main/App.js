@@ -6,6 +6,22 @@ export function ForceUpload(arg1) {
   return window['go']['main']['App']['ForceUpload'](arg1);
 }
 
+export function GetConvertHEIC() {
+  return window['go']['main']['App']['GetConvertHEIC']();
+}
+
+export function GetEmbedMetadata() {
+  return window['go']['main']['App']['GetEmbedMetadata']();
+}
+
+export function GetPhotosAlbumPhotos(arg1) {
+  return window['go']['main']['App']['GetPhotosAlbumPhotos'](arg1);
+}
+
+export function GetPhotosAlbums() {
+  return window['go']['main']['App']['GetPhotosAlbums']();
+}
+
 export function GetRecentTags() {
   return window['go']['main']['App']['GetRecentTags']();
 }
@@ -26,6 +42,10 @@ export function PostPullSelection(arg1) {
   return window['go']['main']['App']['PostPullSelection'](arg1);
 }
 
+export function PreviewSnippet(arg1) {
+  return window['go']['main']['App']['PreviewSnippet'](arg1);
+}
+
 export function ResizeWindow(arg1) {
   return window['go']['main']['App']['ResizeWindow'](arg1);
 }
@@ -34,6 +54,10 @@ export function ResizeWindowForMultiPhoto(arg1, arg2) {
   return window['go']['main']['App']['ResizeWindowForMultiPhoto'](arg1, arg2);
 }
 
+export function SetConvertHEIC(arg1) {
+  return window['go']['main']['App']['SetConvertHEIC'](arg1);
+}
+
 export function StartThumbnailGeneration(arg1) {
   return window['go']['main']['App']['StartThumbnailGeneration'](arg1);
 }
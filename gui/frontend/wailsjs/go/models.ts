@@ -8,6 +8,7 @@ export namespace main {
 	    isFromPhotos: boolean;
 	    photosIndex: number;
 	    photosId: string;
+	    photosFilename: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new MultiPhotoImageData(source);
@@ -22,6 +23,7 @@ export namespace main {
 	        this.isFromPhotos = source["isFromPhotos"];
 	        this.photosIndex = source["photosIndex"];
 	        this.photosId = source["photosId"];
+	        this.photosFilename = source["photosFilename"];
 	    }
 	}
 	export class MultiPhotoOutputResult {
@@ -58,6 +60,7 @@ export namespace main {
 	    bluesky: boolean;
 	    visibility: string;
 	    format: string;
+	    noEmbedMetadata: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new MultiPhotoUploadRequest(source);
@@ -72,6 +75,7 @@ export namespace main {
 	        this.bluesky = source["bluesky"];
 	        this.visibility = source["visibility"];
 	        this.format = source["format"];
+	        this.noEmbedMetadata = source["noEmbedMetadata"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -152,6 +156,7 @@ export namespace main {
 	    photosIndex: number;
 	    photosId: string;
 	    photosFilename: string;
+	    noEmbedMetadata: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new PhotoMetadata(source);
@@ -180,6 +185,23 @@ export namespace main {
 	        this.photosIndex = source["photosIndex"];
 	        this.photosId = source["photosId"];
 	        this.photosFilename = source["photosFilename"];
+	        this.noEmbedMetadata = source["noEmbedMetadata"];
+	    }
+	}
+	export class PhotosAlbum {
+	    id: string;
+	    name: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PhotosAlbum(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.count = source["count"];
 	    }
 	}
 	export class UploadResult {
@@ -286,6 +308,8 @@ export namespace types {
 	export class PullRequest {
 	    source: PullSource;
 	    post: string;
+	    mastodon_post?: string;
+	    bluesky_post?: string;
 	    images: PullImage[];
 	    targets?: string[];
 	    visibility?: string;
@@ -299,6 +323,8 @@ export namespace types {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.source = this.convertValues(source["source"], PullSource);
 	        this.post = source["post"];
+	        this.mastodon_post = source["mastodon_post"];
+	        this.bluesky_post = source["bluesky_post"];
 	        this.images = this.convertValues(source["images"], PullImage);
 	        this.targets = source["targets"];
 	        this.visibility = source["visibility"];
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+)
+
+// imgupUploadFailed is the exit status imgup uses when an upload failed in
+// transit. Auth, usage and other errors exit with 1 and aren't retried.
+const imgupUploadFailed = 3
+
+// runImgup runs the imgup CLI, running it again up to gui.upload_retries
+// times while it exits with imgupUploadFailed. With combined, stderr is
+// captured along with stdout.
+func runImgup(imgupPath string, args []string, combined bool) ([]byte, error) {
+	retries := config.DefaultUploadRetries
+	if cfg, err := config.Load(); err == nil {
+		retries = cfg.GUI.UploadRetryCount()
+	}
+
+	for attempt := 1; ; attempt++ {
+		cmd := exec.Command(imgupPath, args...)
+		var output []byte
+		var err error
+		if combined {
+			output, err = cmd.CombinedOutput()
+		} else {
+			output, err = cmd.Output()
+		}
+
+		var exitErr *exec.ExitError
+		if err == nil || attempt > retries || !errors.As(err, &exitErr) || exitErr.ExitCode() != imgupUploadFailed {
+			return output, err
+		}
+		fmt.Printf("DEBUG: Upload failed in transit, retrying (%d of %d)\n", attempt, retries)
+		time.Sleep(time.Duration(attempt) * 2 * time.Second)
+	}
+}
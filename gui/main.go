@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/httpx"
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
@@ -16,6 +19,9 @@ import (
 //go:embed all:frontend/dist
 var assets embed.FS
 
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
 // main is the application entry point
 func main() {
 	// Debug: Log raw arguments
@@ -32,9 +38,23 @@ func main() {
 	fmt.Printf("DEBUG: main() - args: %v\n", os.Args)
 	fmt.Printf("DEBUG: main() - pullDataPath after parsing: %s\n", pullDataPath)
 	
+	// Identify the GUI on the requests it makes itself, like social posts
+	userAgent := httpx.UserAgent(version)
+	if cfg, err := config.Load(); err == nil && cfg.Default.UserAgent != "" {
+		userAgent = cfg.Default.UserAgent
+	}
+	httpx.Install(userAgent)
+
 	// Create an instance of the app structure
 	app := NewApp()
 	
+	// Files passed by a file manager integration (e.g. a Dolphin service menu)
+	for _, arg := range flag.Args() {
+		if abs, err := filepath.Abs(arg); err == nil {
+			app.launchFiles = append(app.launchFiles, abs)
+		}
+	}
+	
 	// If pull data is provided, handle it
 	if pullDataPath != "" {
 		if pullDataPath == "-" {
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// errPhotosUnavailable is returned for Photos.app requests off macOS
+var errPhotosUnavailable = errors.New("Photos albums are only available on macOS")
+
+// PhotosAlbum is an album in the Photos.app library
+type PhotosAlbum struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`  // folders it's in come first, separated by "/"
+	Count int    `json:"count"` // photos and videos in the album
+}
+
+// GetPhotosAlbums lists the albums in the Photos.app library, including
+// those inside folders, with how many items each holds
+func (a *App) GetPhotosAlbums() ([]PhotosAlbum, error) {
+	if runtime.GOOS != "darwin" {
+		return nil, errPhotosUnavailable
+	}
+
+	script := `
+	on collectAlbums(container, prefix)
+		set found to {}
+		tell application "Photos"
+			repeat with anAlbum in albums of container
+				copy ("ALBUM|ID:" & (id of anAlbum) & "|COUNT:" & (count of media items of anAlbum) & "|NAME:" & prefix & (name of anAlbum)) to end of found
+			end repeat
+			repeat with aFolder in folders of container
+				set found to found & my collectAlbums(aFolder, prefix & (name of aFolder) & "/")
+			end repeat
+		end tell
+		return found
+	end collectAlbums
+
+	tell application "Photos"
+		set allAlbums to my collectAlbums(it, "")
+	end tell
+
+	set AppleScript's text item delimiters to "\n"
+	set albumList to (allAlbums as string)
+	set AppleScript's text item delimiters to ""
+	return albumList`
+
+	out, err := exec.Command("osascript", "-e", script).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Photos albums: %w", err)
+	}
+	return parsePhotosAlbums(string(out)), nil
+}
+
+// parsePhotosAlbums reads the lines GetPhotosAlbums' script prints. The name
+// comes last, so it may contain the "|" separator.
+func parsePhotosAlbums(out string) []PhotosAlbum {
+	var albums []PhotosAlbum
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if !strings.HasPrefix(line, "ALBUM|") {
+			continue
+		}
+		fields, name, _ := strings.Cut(strings.TrimPrefix(line, "ALBUM|"), "|NAME:")
+		album := PhotosAlbum{Name: name}
+		for _, field := range strings.Split(fields, "|") {
+			if id, ok := strings.CutPrefix(field, "ID:"); ok {
+				album.ID = id
+			} else if count, ok := strings.CutPrefix(field, "COUNT:"); ok {
+				album.Count, _ = strconv.Atoi(count)
+			}
+		}
+		albums = append(albums, album)
+	}
+	return albums
+}
+
+// GetPhotosAlbumPhotos gets metadata for the photos in a Photos.app album,
+// by the ID GetPhotosAlbums returned. PhotosIndex is left at zero, since the
+// photos aren't in the selection; uploads export them by PhotosID instead.
+func (a *App) GetPhotosAlbumPhotos(albumID string) ([]PhotoMetadata, error) {
+	if runtime.GOOS != "darwin" {
+		return nil, errPhotosUnavailable
+	}
+	items := fmt.Sprintf("media items of album id %s", strconv.Quote(albumID))
+	photos, err := photosMetadata(items, "The album has no photos")
+	if err != nil {
+		return nil, err
+	}
+	for i := range photos {
+		photos[i].PhotosIndex = 0
+	}
+	return photos, nil
+}
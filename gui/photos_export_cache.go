@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// photosExport is a Photos.app export kept for reuse within a session
+type photosExport struct {
+	path    string
+	version string // Photos' date, dimensions and modification date for the photo at export time
+}
+
+// photosExportSweepAge is how old a leftover Photos export directory must be
+// before startup removes it. Anything younger may belong to another running
+// copy of the app that is still uploading it.
+const photosExportSweepAge = time.Hour
+
+// photosUUID matches the UUID that starts a Photos ID like "UUID/L0/001"
+var photosUUID = regexp.MustCompile(`^[0-9A-Fa-f-]+$`)
+
+// selectionItemScript returns AppleScript, run inside a Photos tell block,
+// that sets p to the selected photo at index (1-based) or returns an
+// "ERROR:" result when there isn't one
+func selectionItemScript(photoIndex int) string {
+	return fmt.Sprintf(`
+		set sel to selection
+		if sel is {} then
+			return "ERROR:No photo selected"
+		end if
+		if (count of sel) < %d then
+			return "ERROR:Photo index %d is out of range"
+		end if
+		set p to item %d of sel`, photoIndex, photoIndex, photoIndex)
+}
+
+// mediaItemScript is selectionItemScript for a photo looked up by its
+// Photos ID anywhere in the library
+func mediaItemScript(photoID string) string {
+	return fmt.Sprintf(`
+		try
+			set p to media item id %s
+		on error
+			return "ERROR:The photo isn't in the Photos library"
+		end try`, strconv.Quote(photoID))
+}
+
+// photosVersion returns the Photos ID of the photo lookup sets p to and a
+// version stamp that changes when the photo is edited. The stamp combines the
+// photo's date, its dimensions, which change on crops and rotations, and the
+// modification date from the library's database, which catches other edits.
+func photosVersion(lookup string) (string, string, error) {
+	script := fmt.Sprintf(`
+	tell application "Photos"
+%s
+		return (id of p) & "|" & ((date of p) as string) & "|" & (width of p) & "x" & (height of p)
+	end tell`, lookup)
+	
+	out, err := exec.Command("osascript", "-e", script).Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read photo info: %w", err)
+	}
+	
+	result := strings.TrimSpace(string(out))
+	if strings.HasPrefix(result, "ERROR:") {
+		return "", "", fmt.Errorf(strings.TrimPrefix(result, "ERROR:"))
+	}
+	parts := strings.SplitN(result, "|", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("unexpected photo info: %q", result)
+	}
+	
+	return parts[0], parts[1] + "|" + photosModificationDate(parts[0]), nil
+}
+
+// photosModificationDate returns when Photos last changed the photo, read
+// from the system library's database since AppleScript doesn't expose it. It
+// returns "" when the database can't be read, e.g. without Full Disk Access
+// or for a library outside ~/Pictures; the rest of the version stamp still
+// applies then.
+func photosModificationDate(photoID string) string {
+	uuid := strings.SplitN(photoID, "/", 2)[0]
+	if !photosUUID.MatchString(uuid) {
+		return ""
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	db := filepath.Join(home, "Pictures", "Photos Library.photoslibrary", "database", "Photos.sqlite")
+	query := fmt.Sprintf("SELECT ZMODIFICATIONDATE FROM ZASSET WHERE ZUUID = '%s'", uuid)
+	out, err := exec.Command("sqlite3", "-readonly", db, query).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// sweepPhotosExports removes Photos export directories left in the temp
+// directory by earlier sessions that didn't shut down cleanly
+func sweepPhotosExports() {
+	dirs, err := filepath.Glob(filepath.Join(os.TempDir(), "imgupv2-photos-*"))
+	if err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() || time.Since(info.ModTime()) < photosExportSweepAge {
+			continue
+		}
+		if err := os.RemoveAll(dir); err == nil {
+			fmt.Printf("DEBUG: Removed old Photos export: %s\n", dir)
+		}
+	}
+}
+
+// cachedPhotosExport returns a previous export of the photo if Photos
+// still reports the same version and the file is still there
+func (a *App) cachedPhotosExport(photoID, version string) (string, bool) {
+	a.exportMu.Lock()
+	defer a.exportMu.Unlock()
+	
+	export, ok := a.photosExports[photoID]
+	if !ok {
+		return "", false
+	}
+	if export.version != version || !fileExists(export.path) {
+		// The photo changed or the export was cleaned up
+		delete(a.photosExports, photoID)
+		os.RemoveAll(filepath.Dir(export.path))
+		return "", false
+	}
+	
+	return export.path, true
+}
+
+// storePhotosExport remembers an export for reuse
+func (a *App) storePhotosExport(photoID, version, path string) {
+	a.exportMu.Lock()
+	defer a.exportMu.Unlock()
+	
+	a.photosExports[photoID] = photosExport{path: path, version: version}
+}
+
+// cleanupPhotosExports removes every cached export
+func (a *App) cleanupPhotosExports() {
+	a.exportMu.Lock()
+	defer a.exportMu.Unlock()
+	
+	for photoID, export := range a.photosExports {
+		os.RemoveAll(filepath.Dir(export.path))
+		delete(a.photosExports, photoID)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Photos can keep writing a large HEIC export after osascript returns, so the
+// export folder is polled until a file appears and stops growing
+const (
+	photosExportTimeout  = 60 * time.Second
+	photosExportInterval = 250 * time.Millisecond
+)
+
+// waitForPhotosExport waits until the newest file in dir has the same size on
+// two checks in a row and returns its path
+func waitForPhotosExport(dir string, timeout, interval time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	lastPath, lastSize := "", int64(-1)
+
+	for {
+		path, size, err := newestExport(dir)
+		if err != nil {
+			return "", err
+		}
+		if path != "" && path == lastPath && size == lastSize && size > 0 {
+			return path, nil
+		}
+		lastPath, lastSize = path, size
+
+		if time.Now().After(deadline) {
+			if path == "" {
+				return "", fmt.Errorf("no file exported from Photos within %s", timeout)
+			}
+			return "", fmt.Errorf("Photos export of %s didn't finish within %s", filepath.Base(path), timeout)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// newestExport returns the most recently modified file in dir and its size,
+// or an empty path when there's none yet. Ties go to the first name in
+// sort order, so the choice doesn't depend on directory order.
+func newestExport(dir string) (string, int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read export folder: %w", err)
+	}
+
+	var newest os.FileInfo
+	for _, entry := range entries {
+		// Skip folders and the hidden files Photos writes while exporting
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if newest == nil || info.ModTime().After(newest.ModTime()) ||
+			(info.ModTime().Equal(newest.ModTime()) && info.Name() < newest.Name()) {
+			newest = info
+		}
+	}
+
+	if newest == nil {
+		return "", 0, nil
+	}
+	return filepath.Join(dir, newest.Name()), newest.Size(), nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// selectionProvider finds the files selected in the platform's file manager
+type selectionProvider interface {
+	SelectedFiles() ([]string, error)
+}
+
+// selection returns the selection provider for this OS
+func (a *App) selection() selectionProvider {
+	if runtime.GOOS == "darwin" {
+		return finderSelection{}
+	}
+	return linuxSelection{ctx: a.ctx, launchFiles: a.launchFiles}
+}
+
+// finderSelection reads the Finder selection with AppleScript
+type finderSelection struct{}
+
+// SelectedFiles returns the paths selected in Finder
+func (finderSelection) SelectedFiles() ([]string, error) {
+	script := `
+	tell application "Finder"
+		set theSelection to selection
+		if length of theSelection is 0 then
+			return ""
+		end if
+		
+		set allPaths to {}
+		repeat with theFile in theSelection
+			set thePath to POSIX path of (theFile as alias)
+			-- Remove trailing slash if it's there
+			if thePath ends with "/" then
+				set thePath to text 1 thru -2 of thePath
+			end if
+			
+			-- Check if it's a file (not directory) by looking for extension
+			if thePath contains "." then
+				copy thePath to end of allPaths
+			end if
+		end repeat
+		
+		-- Join paths with newline
+		set AppleScript's text item delimiters to "\n"
+		set pathList to (allPaths as string)
+		set AppleScript's text item delimiters to ""
+		
+		return pathList
+	end tell`
+
+	cmd := exec.Command("osascript", "-e", script)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Finder selection: %w", err)
+	}
+	
+	pathList := strings.TrimSpace(string(out))
+	if pathList == "" {
+		return nil, fmt.Errorf("no files selected in Finder")
+	}
+	
+	return strings.Split(pathList, "\n"), nil
+}
+
+// linuxSelection finds the files handed over by a Linux file manager.
+// Linux file managers don't expose their selection to other programs, so
+// the selection has to come from the integration that launched the GUI:
+// a Nautilus, Nemo or Caja script, or a Dolphin service menu passing %F.
+// Without one, the user picks files in a dialog opened on the current folder.
+type linuxSelection struct {
+	ctx         context.Context
+	launchFiles []string // files passed on the command line
+}
+
+// fileManagerScriptVars are the variables Nautilus-style file managers set
+// for scripts, holding the newline-separated selected paths
+var fileManagerScriptVars = []string{
+	"NAUTILUS_SCRIPT_SELECTED_FILE_PATHS",
+	"NEMO_SCRIPT_SELECTED_FILE_PATHS",
+	"CAJA_SCRIPT_SELECTED_FILE_PATHS",
+}
+
+// SelectedFiles returns the paths selected in the file manager
+func (s linuxSelection) SelectedFiles() ([]string, error) {
+	// Dolphin service menus and .desktop launchers pass files as arguments
+	if len(s.launchFiles) > 0 {
+		return s.launchFiles, nil
+	}
+	
+	// Nautilus, Nemo and Caja scripts get the selection in the environment
+	for _, name := range fileManagerScriptVars {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			fmt.Printf("DEBUG: Using selection from %s\n", name)
+			return strings.Split(value, "\n"), nil
+		}
+	}
+	
+	return s.prompt()
+}
+
+// prompt asks the user to pick images, starting in the folder the GUI was
+// launched from
+func (s linuxSelection) prompt() ([]string, error) {
+	if s.ctx == nil {
+		return nil, fmt.Errorf("no files selected")
+	}
+	
+	paths, err := wailsRuntime.OpenMultipleFilesDialog(s.ctx, wailsRuntime.OpenDialogOptions{
+		Title:            "Select images to upload",
+		DefaultDirectory: promptDirectory(),
+		Filters: []wailsRuntime.FileFilter{
+			{DisplayName: "Images", Pattern: "*.jpg;*.jpeg;*.png;*.gif;*.webp;*.heic;*.tif;*.tiff"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file dialog: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files selected")
+	}
+	
+	return paths, nil
+}
+
+// promptDirectory picks the folder the file dialog opens in: the file
+// manager's current folder, the working directory, or ~/Pictures
+func promptDirectory() string {
+	for _, name := range []string{"NAUTILUS_SCRIPT_CURRENT_URI", "NEMO_SCRIPT_CURRENT_URI", "CAJA_SCRIPT_CURRENT_URI"} {
+		if u, err := url.Parse(os.Getenv(name)); err == nil && u.Scheme == "file" && dirExists(u.Path) {
+			return u.Path
+		}
+	}
+	
+	if cwd, err := os.Getwd(); err == nil && cwd != "/" && dirExists(cwd) {
+		return cwd
+	}
+	
+	if home, err := os.UserHomeDir(); err == nil {
+		pictures := filepath.Join(home, "Pictures")
+		if dirExists(pictures) {
+			return pictures
+		}
+		return home
+	}
+	
+	return ""
+}
+
+// dirExists reports whether path is an existing directory
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"time"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Smooth resizes move to the new size in resizeSteps steps, resizeStep apart
+const (
+	resizeSteps = 10
+	resizeStep  = 15 * time.Millisecond
+)
+
+// resizeWindow sets the window size the way gui.window_resize asks: at once,
+// animated, or not at all
+func (a *App) resizeWindow(width, height int) {
+	mode := "instant"
+	if cfg, err := config.Load(); err == nil {
+		mode = cfg.GUI.ResizeMode()
+	}
+
+	a.resizeMu.Lock()
+	a.resizeGen++
+	gen := a.resizeGen
+	a.resizeMu.Unlock()
+
+	switch mode {
+	case "off":
+		return
+	case "smooth":
+		go a.animateResize(gen, width, height)
+	default:
+		wailsRuntime.WindowSetSize(a.ctx, width, height)
+	}
+}
+
+// animateResize eases the window from its current size to width x height.
+// It stops early when a newer resize starts, so quick toggles of the social
+// fields don't fight over the window.
+func (a *App) animateResize(gen, width, height int) {
+	startWidth, startHeight := wailsRuntime.WindowGetSize(a.ctx)
+	for step := 1; step <= resizeSteps; step++ {
+		a.resizeMu.Lock()
+		current := gen == a.resizeGen
+		a.resizeMu.Unlock()
+		if !current {
+			return
+		}
+
+		// Ease out: big steps first, small ones as it settles
+		t := float64(step) / resizeSteps
+		t = 1 - (1-t)*(1-t)
+		w := startWidth + int(float64(width-startWidth)*t)
+		h := startHeight + int(float64(height-startHeight)*t)
+		wailsRuntime.WindowSetSize(a.ctx, w, h)
+		time.Sleep(resizeStep)
+	}
+}
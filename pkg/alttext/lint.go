@@ -0,0 +1,62 @@
+package alttext
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultMinLength is the shortest alt text that isn't flagged as too short
+const DefaultMinLength = 15
+
+// redundantPrefixes are openings screen readers make redundant, since they
+// already announce the element as an image
+var redundantPrefixes = []string{
+	"image of",
+	"photo of",
+	"picture of",
+	"an image of",
+	"a photo of",
+	"a picture of",
+}
+
+// Linter checks alt text for common accessibility problems
+type Linter struct {
+	MinLength int // alt text shorter than this is flagged; 0 means DefaultMinLength
+}
+
+// Lint checks alt text with the default thresholds
+func Lint(alt, title string) []string {
+	return Linter{}.Lint(alt, title)
+}
+
+// Lint returns a warning for each problem found in alt
+func (l Linter) Lint(alt, title string) []string {
+	alt = strings.TrimSpace(alt)
+	if alt == "" {
+		return []string{"alt text is missing"}
+	}
+
+	var warnings []string
+
+	if title = strings.TrimSpace(title); title != "" && strings.EqualFold(alt, title) {
+		warnings = append(warnings, "alt text is the same as the title; describe what's in the image instead")
+	}
+
+	minLength := l.MinLength
+	if minLength <= 0 {
+		minLength = DefaultMinLength
+	}
+	if n := len([]rune(alt)); n < minLength {
+		warnings = append(warnings, fmt.Sprintf("alt text is very short (%d characters, at least %d recommended)", n, minLength))
+	}
+
+	lower := strings.ToLower(alt)
+	for _, prefix := range redundantPrefixes {
+		if strings.HasPrefix(lower, prefix+" ") {
+			warnings = append(warnings, fmt.Sprintf("alt text starts with %q; screen readers already announce it as an image", alt[:len(prefix)]))
+			break
+		}
+	}
+
+	return warnings
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 	
 	"github.com/pdxmph/imgupv2/pkg/config"
 )
@@ -72,6 +73,7 @@ func (api *FlickrAPI) GetPhotoInfo(ctx context.Context, photoID string) (*PhotoI
 			} `json:"owner"`
 		} `json:"photo"`
 		Stat string `json:"stat"`
+		Code int `json:"code,omitempty"`
 		Message string `json:"message,omitempty"`
 	}
 	
@@ -80,7 +82,7 @@ func (api *FlickrAPI) GetPhotoInfo(ctx context.Context, photoID string) (*PhotoI
 	}
 	
 	if result.Stat != "ok" {
-		return nil, fmt.Errorf("API error: %s", result.Message)
+		return nil, &FlickrError{Code: result.Code, Message: result.Message}
 	}
 	
 	// Build the photo URL
@@ -134,7 +136,7 @@ func (api *FlickrAPI) GetPhotoSizes(ctx context.Context, photoID string) ([]Phot
 	
 	if result.Stat != "ok" {
 		if result.Message != "" {
-			return nil, fmt.Errorf("API error %d: %s", result.Code, result.Message)
+			return nil, &FlickrError{Code: result.Code, Message: result.Message}
 		}
 		return nil, fmt.Errorf("API returned error status: %s", result.Stat)
 	}
@@ -250,6 +252,7 @@ func (api *FlickrAPI) PhotosSearch(ctx context.Context, params PhotoSearchParams
 			Photo   []PhotoSearchResult `json:"photo"`
 		} `json:"photos"`
 		Stat    string `json:"stat"`
+		Code    int    `json:"code,omitempty"`
 		Message string `json:"message,omitempty"`
 	}
 	
@@ -258,7 +261,7 @@ func (api *FlickrAPI) PhotosSearch(ctx context.Context, params PhotoSearchParams
 	}
 	
 	if result.Stat != "ok" {
-		return nil, fmt.Errorf("search failed: %s", result.Message)
+		return nil, fmt.Errorf("search failed: %w", &FlickrError{Code: result.Code, Message: result.Message})
 	}
 	
 	// Parse total - handle both string and number formats
@@ -319,6 +322,7 @@ func (api *FlickrAPI) GetUserID(ctx context.Context) (string, error) {
 			} `json:"username"`
 		} `json:"user"`
 		Stat    string `json:"stat"`
+		Code    int    `json:"code,omitempty"`
 		Message string `json:"message,omitempty"`
 	}
 	
@@ -327,7 +331,7 @@ func (api *FlickrAPI) GetUserID(ctx context.Context) (string, error) {
 	}
 	
 	if result.Stat != "ok" {
-		return "", fmt.Errorf("test.login failed: %s", result.Message)
+		return "", fmt.Errorf("test.login failed: %w", &FlickrError{Code: result.Code, Message: result.Message})
 	}
 	
 	if result.User.ID == "" {
@@ -336,3 +340,335 @@ func (api *FlickrAPI) GetUserID(ctx context.Context) (string, error) {
 	
 	return result.User.ID, nil
 }
+
+// flickrSafetyLevels maps safety level names to Flickr API values
+var flickrSafetyLevels = map[string]string{
+	"safe":       "1",
+	"moderate":   "2",
+	"restricted": "3",
+}
+
+// flickrContentTypes maps content type names to Flickr API values
+var flickrContentTypes = map[string]string{
+	"photo":      "1",
+	"screenshot": "2",
+	"other":      "3",
+}
+
+// ValidateFlickrSafetyLevel checks that a safety level name is supported
+func ValidateFlickrSafetyLevel(level string) error {
+	if _, ok := flickrSafetyLevels[strings.ToLower(level)]; !ok {
+		return fmt.Errorf("invalid safety level '%s'. Must be 'safe', 'moderate', or 'restricted'", level)
+	}
+	return nil
+}
+
+// ValidateFlickrContentType checks that a content type name is supported
+func ValidateFlickrContentType(contentType string) error {
+	if _, ok := flickrContentTypes[strings.ToLower(contentType)]; !ok {
+		return fmt.Errorf("invalid content type '%s'. Must be 'photo', 'screenshot', or 'other'", contentType)
+	}
+	return nil
+}
+
+// SetSafetyLevel sets a photo's safety level (safe, moderate, restricted)
+func (api *FlickrAPI) SetSafetyLevel(ctx context.Context, photoID, level string) error {
+	value, ok := flickrSafetyLevels[strings.ToLower(level)]
+	if !ok {
+		return ValidateFlickrSafetyLevel(level)
+	}
+	
+	params := url.Values{
+		"method":         {"flickr.photos.setSafetyLevel"},
+		"photo_id":       {photoID},
+		"safety_level":   {value},
+		"format":         {"json"},
+		"nojsoncallback": {"1"},
+	}
+	
+	resp, err := api.makeAPICall(ctx, "POST", params)
+	if err != nil {
+		return err
+	}
+	
+	return checkFlickrStat(resp)
+}
+
+// SetContentType sets a photo's content type (photo, screenshot, other)
+func (api *FlickrAPI) SetContentType(ctx context.Context, photoID, contentType string) error {
+	value, ok := flickrContentTypes[strings.ToLower(contentType)]
+	if !ok {
+		return ValidateFlickrContentType(contentType)
+	}
+	
+	params := url.Values{
+		"method":         {"flickr.photos.setContentType"},
+		"photo_id":       {photoID},
+		"content_type":   {value},
+		"format":         {"json"},
+		"nojsoncallback": {"1"},
+	}
+	
+	resp, err := api.makeAPICall(ctx, "POST", params)
+	if err != nil {
+		return err
+	}
+	
+	return checkFlickrStat(resp)
+}
+
+// SetHiddenFromSearch hides (or unhides) a photo from public searches
+func (api *FlickrAPI) SetHiddenFromSearch(ctx context.Context, photoID string, hidden bool) error {
+	params := url.Values{
+		"method":         {"flickr.photos.setSafetyLevel"},
+		"photo_id":       {photoID},
+		"hidden":         {boolToString(hidden)},
+		"format":         {"json"},
+		"nojsoncallback": {"1"},
+	}
+	
+	resp, err := api.makeAPICall(ctx, "POST", params)
+	if err != nil {
+		return err
+	}
+	
+	return checkFlickrStat(resp)
+}
+
+// SetMeta changes a photo's title and description. An empty value leaves
+// that field as it is.
+func (api *FlickrAPI) SetMeta(ctx context.Context, photoID, title, description string) error {
+	params := url.Values{
+		"method":         {"flickr.photos.setMeta"},
+		"photo_id":       {photoID},
+		"format":         {"json"},
+		"nojsoncallback": {"1"},
+	}
+	if title != "" {
+		params.Set("title", title)
+	}
+	if description != "" {
+		params.Set("description", description)
+	}
+	
+	resp, err := api.makeAPICall(ctx, "POST", params)
+	if err != nil {
+		return err
+	}
+	
+	return checkFlickrStat(resp)
+}
+
+// SetDates changes when Flickr says a photo was taken. Flickr stores the
+// time as given, without a time zone.
+func (api *FlickrAPI) SetDates(ctx context.Context, photoID string, taken time.Time) error {
+	params := url.Values{
+		"method":                 {"flickr.photos.setDates"},
+		"photo_id":               {photoID},
+		"date_taken":             {taken.Format("2006-01-02 15:04:05")},
+		"date_taken_granularity": {"0"},
+		"format":                 {"json"},
+		"nojsoncallback":         {"1"},
+	}
+	
+	resp, err := api.makeAPICall(ctx, "POST", params)
+	if err != nil {
+		return err
+	}
+	
+	return checkFlickrStat(resp)
+}
+
+// AddTags adds tags to a photo, keeping the ones it already has
+func (api *FlickrAPI) AddTags(ctx context.Context, photoID string, tags []string) error {
+	return api.addTags(ctx, photoID, tags)
+}
+
+// FindPhotoset returns the ID of the photoset with the given name, ignoring
+// case. An empty userID lists the authenticated user's photosets.
+func (api *FlickrAPI) FindPhotoset(ctx context.Context, userID, name string) (string, error) {
+	params := url.Values{}
+	params.Set("method", "flickr.photosets.getList")
+	if userID != "" {
+		params.Set("user_id", userID)
+	}
+	params.Set("format", "json")
+	params.Set("nojsoncallback", "1")
+	
+	resp, err := api.makeAPICall(ctx, "GET", params)
+	if err != nil {
+		return "", fmt.Errorf("failed to get photosets: %w", err)
+	}
+	
+	var result struct {
+		Photosets struct {
+			Photoset []struct {
+				ID    string `json:"id"`
+				Title struct {
+					Content string `json:"_content"`
+				} `json:"title"`
+				Photos int `json:"photos"`
+			} `json:"photoset"`
+		} `json:"photosets"`
+		Stat    string `json:"stat"`
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return "", fmt.Errorf("failed to parse photosets response: %w", err)
+	}
+	
+	if result.Stat != "ok" {
+		return "", &FlickrError{Code: result.Code, Message: result.Message}
+	}
+
+	// Debug: print available photosets
+	if os.Getenv("IMGUP_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "DEBUG: Available photosets:\n")
+		for _, ps := range result.Photosets.Photoset {
+			fmt.Fprintf(os.Stderr, "  - %s (ID: %s, %d photos)\n", ps.Title.Content, ps.ID, ps.Photos)
+		}
+	}
+
+	// Find photoset by name
+	for _, ps := range result.Photosets.Photoset {
+		if strings.EqualFold(ps.Title.Content, name) {
+			return ps.ID, nil
+		}
+	}
+
+	// If not found, suggest similar photosets
+	var suggestions []string
+	for _, ps := range result.Photosets.Photoset {
+		if strings.Contains(strings.ToLower(ps.Title.Content), strings.ToLower(name)) ||
+		   strings.Contains(strings.ToLower(name), strings.ToLower(ps.Title.Content)) {
+			suggestions = append(suggestions, ps.Title.Content)
+		}
+	}
+
+	if len(suggestions) > 0 {
+		return "", fmt.Errorf("photoset '%s' not found. Did you mean one of: %s", name, strings.Join(suggestions, ", "))
+	}
+
+	return "", fmt.Errorf("photoset '%s' not found", name)
+}
+
+// PhotosetPhotos lists every photo in a photoset, 500 at a time
+func (api *FlickrAPI) PhotosetPhotos(ctx context.Context, photosetID string) ([]PhotoSearchResult, error) {
+	var photos []PhotoSearchResult
+	for page := 1; ; page++ {
+		params := url.Values{
+			"method":         {"flickr.photosets.getPhotos"},
+			"photoset_id":    {photosetID},
+			"per_page":       {"500"},
+			"page":           {fmt.Sprintf("%d", page)},
+			"format":         {"json"},
+			"nojsoncallback": {"1"},
+		}
+
+		resp, err := api.makeAPICall(ctx, "GET", params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get photoset photos: %w", err)
+		}
+
+		var result struct {
+			Photoset struct {
+				Owner string              `json:"owner"`
+				Pages int                 `json:"pages"`
+				Photo []PhotoSearchResult `json:"photo"`
+			} `json:"photoset"`
+			Stat    string `json:"stat"`
+			Code    int    `json:"code,omitempty"`
+			Message string `json:"message,omitempty"`
+		}
+		if err := json.Unmarshal(resp, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse photoset photos response: %w", err)
+		}
+		if result.Stat != "ok" {
+			return nil, &FlickrError{Code: result.Code, Message: result.Message}
+		}
+
+		// The owner is given once for the whole photoset
+		for _, photo := range result.Photoset.Photo {
+			photo.Owner = result.Photoset.Owner
+			photos = append(photos, photo)
+		}
+		if page >= result.Photoset.Pages {
+			return photos, nil
+		}
+	}
+}
+
+// AddToPhotoset adds a photo to a photoset
+func (api *FlickrAPI) AddToPhotoset(ctx context.Context, photosetID, photoID string) error {
+	params := url.Values{
+		"method":         {"flickr.photosets.addPhoto"},
+		"photoset_id":    {photosetID},
+		"photo_id":       {photoID},
+		"format":         {"json"},
+		"nojsoncallback": {"1"},
+	}
+	
+	resp, err := api.makeAPICall(ctx, "POST", params)
+	if err != nil {
+		return err
+	}
+	
+	return checkFlickrStat(resp)
+}
+
+// checkFlickrStat parses a Flickr JSON response and returns an error unless stat is ok
+func checkFlickrStat(resp []byte) error {
+	var result struct {
+		Stat    string `json:"stat"`
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	
+	if result.Stat != "ok" {
+		return &FlickrError{Code: result.Code, Message: result.Message}
+	}
+	
+	return nil
+}
+
+// PhotoExists reports whether a photo is still on Flickr and visible to the
+// authenticated user
+func (api *FlickrAPI) PhotoExists(ctx context.Context, photoID string) (bool, error) {
+	params := url.Values{}
+	params.Set("method", "flickr.photos.getInfo")
+	params.Set("photo_id", photoID)
+	params.Set("format", "json")
+	params.Set("nojsoncallback", "1")
+	
+	resp, err := api.makeAPICall(ctx, "GET", params)
+	if err != nil {
+		return false, fmt.Errorf("failed to get photo info: %w", err)
+	}
+	
+	var result struct {
+		Stat    string `json:"stat"`
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+	
+	if result.Stat == "ok" {
+		return true, nil
+	}
+	
+	// Code 1 is "Photo not found"
+	if result.Code == 1 {
+		return false, nil
+	}
+	
+	return false, &FlickrError{Code: result.Code, Message: result.Message}
+}
@@ -0,0 +1,92 @@
+package backends
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+)
+
+// FlickrError is an error reported by the Flickr API, with its numeric code.
+// Codes are documented per method at https://www.flickr.com/services/api/
+type FlickrError struct {
+	Code    int
+	Message string
+}
+
+// Known Flickr errors, for use with errors.Is
+var (
+	ErrFlickrNotFound     = &FlickrError{Code: 1, Message: "Photo not found"}
+	ErrFlickrFileEmpty    = &FlickrError{Code: 4, Message: "Filesize was zero"}
+	ErrFlickrFiletype     = &FlickrError{Code: 5, Message: "Filetype was not recognised"}
+	ErrFlickrUploadLimit  = &FlickrError{Code: 6, Message: "User exceeded upload limit"}
+	ErrFlickrInvalidAuth  = &FlickrError{Code: 98, Message: "Invalid auth token"}
+	ErrFlickrNoPermission = &FlickrError{Code: 99, Message: "Insufficient permissions"}
+	ErrFlickrUnavailable  = &FlickrError{Code: 105, Message: "Service currently unavailable"}
+)
+
+// flickrErrorHints are the actionable messages shown for known error codes
+var flickrErrorHints = map[int]string{
+	4:   "the file is empty",
+	5:   "Flickr doesn't accept this file type; convert it first, e.g. with --transcode jpeg",
+	6:   "your Flickr account has reached its upload limit; free accounts are limited to 1,000 photos, so delete some or upgrade to Flickr Pro",
+	8:   "the file is larger than Flickr allows (200MB for photos)",
+	96:  "the request signature was rejected; run 'imgup auth flickr' again",
+	97:  "the request signature was rejected; run 'imgup auth flickr' again",
+	98:  "your Flickr authorization is no longer valid; run 'imgup auth flickr' again",
+	99:  "imgup doesn't have write permission on your Flickr account; run 'imgup auth flickr' again",
+	100: "the Flickr API key is invalid; check flickr.key and flickr.secret",
+	105: "Flickr is temporarily unavailable; try again later",
+}
+
+// Error returns Flickr's message with advice for known codes
+func (e *FlickrError) Error() string {
+	if hint, ok := flickrErrorHints[e.Code]; ok {
+		return fmt.Sprintf("Flickr error %d (%s): %s", e.Code, e.Message, hint)
+	}
+	return fmt.Sprintf("Flickr error %d: %s", e.Code, e.Message)
+}
+
+// Is matches Flickr errors by code, so errors.Is(err, ErrFlickrUploadLimit) works
+func (e *FlickrError) Is(target error) bool {
+	t, ok := target.(*FlickrError)
+	return ok && t.Code == e.Code
+}
+
+// flickrRetryableCodes are errors that may succeed if the request is repeated:
+// a general upload failure, service unavailable and write operation failed
+var flickrRetryableCodes = map[int]bool{
+	3:   true,
+	105: true,
+	106: true,
+}
+
+// Retryable reports whether the request may succeed if it is repeated
+func (e *FlickrError) Retryable() bool {
+	return flickrRetryableCodes[e.Code]
+}
+
+// IsRetryableFlickrError reports whether err wraps a retryable Flickr error
+func IsRetryableFlickrError(err error) bool {
+	var flickrErr *FlickrError
+	return errors.As(err, &flickrErr) && flickrErr.Retryable()
+}
+
+// parseFlickrUploadError returns the error in an upload API response, or nil
+// if the response isn't a failure. The upload API answers in XML:
+// <rsp stat="fail"><err code="6" msg="User exceeded upload limit" /></rsp>
+func parseFlickrUploadError(body []byte) *FlickrError {
+	var rsp struct {
+		Stat string `xml:"stat,attr"`
+		Err  *struct {
+			Code int    `xml:"code,attr"`
+			Msg  string `xml:"msg,attr"`
+		} `xml:"err"`
+	}
+	if err := xml.Unmarshal(body, &rsp); err != nil {
+		return nil
+	}
+	if rsp.Stat != "fail" || rsp.Err == nil {
+		return nil
+	}
+	return &FlickrError{Code: rsp.Err.Code, Message: rsp.Err.Msg}
+}
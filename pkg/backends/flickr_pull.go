@@ -26,37 +26,39 @@ func NewFlickrPullClient(cfg *config.FlickrConfig) *FlickrPullClient {
 	}
 }
 
-// PullImages fetches recent images from Flickr
-func (c *FlickrPullClient) PullImages(ctx context.Context, albumName string, count int, tags string) ([]types.PullImage, error) {
+// PullImages fetches recent images from Flickr, skipping the first offset
+// images. It also returns the total number of images available.
+func (c *FlickrPullClient) PullImages(ctx context.Context, albumName string, count, offset int, tags string) ([]types.PullImage, int, error) {
+	perPage, page, skip := flickrPage(offset, count)
+	if perPage > maxFlickrPerPage {
+		return nil, 0, fmt.Errorf("offset %d is too far from a multiple of count %d for Flickr paging; use an offset that's a multiple of the count", offset, count)
+	}
+
 	// Get user ID first
 	userID, err := c.api.GetUserID(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user ID: %w", err)
+		return nil, 0, fmt.Errorf("failed to get user ID: %w", err)
 	}
 
 	var photos []photosetPhoto
+	var total int
 	var isPhotostream bool
 	
 	// If tags are specified, use search instead of album/photostream
-	if tags != "" {
-		// Parse comma-separated tags
-		tagList := strings.Split(tags, ",")
-		for i := range tagList {
-			tagList[i] = strings.TrimSpace(tagList[i])
-		}
-
+	if tagList := parsePullTags(tags); len(tagList) > 0 {
 		// Use search API to find photos by tags
 		searchParams := PhotoSearchParams{
 			UserID:  userID,
 			Tags:    tagList,
-			PerPage: count,
-			Page:    1,
+			PerPage: perPage,
+			Page:    page,
 		}
 
 		searchResp, err := c.api.PhotosSearch(ctx, searchParams)
 		if err != nil {
-			return nil, fmt.Errorf("failed to search photos by tags: %w", err)
+			return nil, 0, fmt.Errorf("failed to search photos by tags: %w", err)
 		}
+		total = searchResp.Total
 
 		// Convert search results to photosetPhoto format for consistency
 		photos = make([]photosetPhoto, len(searchResp.Photos))
@@ -75,25 +77,37 @@ func (c *FlickrPullClient) PullImages(ctx context.Context, albumName string, cou
 		}
 	} else if albumName != "" && albumName != "photostream" {
 		// Find the photoset by name
-		photosetID, err := c.findPhotosetByName(ctx, userID, albumName)
+		photosetID, err := c.api.FindPhotoset(ctx, userID, albumName)
 		if err != nil {
-			return nil, fmt.Errorf("failed to find photoset '%s': %w", albumName, err)
+			return nil, 0, fmt.Errorf("failed to find photoset '%s': %w", albumName, err)
 		}
 		
 		// Get photos from the photoset
-		photos, err = c.getPhotosetPhotos(ctx, photosetID, count)
+		photos, total, err = c.getPhotosetPhotos(ctx, photosetID, perPage, page)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get photos from photoset: %w", err)
+			return nil, 0, fmt.Errorf("failed to get photos from photoset: %w", err)
 		}
 	} else {
 		// Get photos from user's photostream
 		isPhotostream = true
-		photos, err = c.getUserPhotos(ctx, userID, count)
+		photos, total, err = c.getUserPhotos(ctx, userID, perPage, page)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get photos from photostream: %w", err)
+			return nil, 0, fmt.Errorf("failed to get photos from photostream: %w", err)
 		}
 	}
 
+	// Drop the part of an oversized first page that falls before the offset
+	if skip > 0 {
+		if skip >= len(photos) {
+			photos = nil
+		} else {
+			photos = photos[skip:]
+		}
+	}
+	if len(photos) > count {
+		photos = photos[:count]
+	}
+
 	if os.Getenv("IMGUP_DEBUG") != "" {
 		if isPhotostream {
 			fmt.Fprintf(os.Stderr, "DEBUG: Found %d photos in photostream\n", len(photos))
@@ -102,50 +116,77 @@ func (c *FlickrPullClient) PullImages(ctx context.Context, albumName string, cou
 		}
 	}
 
-	// Convert to PullImage format
-	pullImages := make([]types.PullImage, 0, len(photos))
-	for i, photo := range photos {
-		// Get photo info for metadata
-		info, err := c.getPhotoInfo(ctx, photo.ID)
-		if err != nil {
-			if os.Getenv("IMGUP_DEBUG") != "" {
-				fmt.Fprintf(os.Stderr, "DEBUG: Failed to get info for photo %s: %v\n", photo.ID, err)
-			}
-			continue
-		}
+	// Look up each photo's metadata and sizes a few at a time, keeping the
+	// photos in order
+	found := make([]*types.PullImage, len(photos))
+	forEachParallel(len(photos), pullWorkers, func(i int) {
+		found[i] = c.pullImage(ctx, userID, i, photos[i])
+	})
 
-		// Get available sizes
-		sizes, err := c.getImageSizes(ctx, photo.ID)
-		if err != nil {
-			if os.Getenv("IMGUP_DEBUG") != "" {
-				fmt.Fprintf(os.Stderr, "DEBUG: Failed to get sizes for photo %s: %v\n", photo.ID, err)
-			}
-			continue
+	pullImages := make([]types.PullImage, 0, len(photos))
+	for _, image := range found {
+		if image != nil {
+			pullImages = append(pullImages, *image)
 		}
+	}
 
-		// Build photo page URL
-		photoURL := fmt.Sprintf("https://www.flickr.com/photos/%s/%s", userID, photo.ID)
+	return pullImages, total, nil
+}
 
-		pullImage := types.PullImage{
-			ID:          fmt.Sprintf("%d", i+1),
-			Title:       info.Title,
-			Description: info.Description,
-			SourceURL:   photoURL,
-			Sizes:       sizes,
-			Tags:        info.Tags,
+// pullImage converts the i-th photo of a pull to a PullImage, or returns nil
+// if its info or sizes can't be fetched
+func (c *FlickrPullClient) pullImage(ctx context.Context, userID string, i int, photo photosetPhoto) *types.PullImage {
+	// Get photo info for metadata
+	info, err := c.getPhotoInfo(ctx, photo.ID)
+	if err != nil {
+		if os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Failed to get info for photo %s: %v\n", photo.ID, err)
 		}
+		return nil
+	}
 
-		// Set alt text from description or title
-		if info.Description != "" {
-			pullImage.Alt = info.Description
-		} else if info.Title != "" {
-			pullImage.Alt = info.Title
+	// Get available sizes
+	sizes, err := c.getImageSizes(ctx, photo.ID)
+	if err != nil {
+		if os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Failed to get sizes for photo %s: %v\n", photo.ID, err)
 		}
+		return nil
+	}
+
+	// Build photo page URL
+	photoURL := fmt.Sprintf("https://www.flickr.com/photos/%s/%s", userID, photo.ID)
 
-		pullImages = append(pullImages, pullImage)
+	pullImage := types.PullImage{
+		ID:          fmt.Sprintf("%d", i+1),
+		Title:       info.Title,
+		Description: info.Description,
+		SourceURL:   photoURL,
+		Sizes:       sizes,
+		Tags:        info.Tags,
 	}
 
-	return pullImages, nil
+	// Set alt text from description or title
+	if info.Description != "" {
+		pullImage.Alt = info.Description
+	} else if info.Title != "" {
+		pullImage.Alt = info.Title
+	}
+
+	return &pullImage
+}
+
+// maxFlickrPerPage is the largest page Flickr returns
+const maxFlickrPerPage = 500
+
+// flickrPage maps an offset and count onto Flickr's page-based paging. When
+// the offset isn't a multiple of count, a larger first page is fetched and
+// the leading skip photos are dropped.
+func flickrPage(offset, count int) (perPage, page, skip int) {
+	if offset%count == 0 {
+		return count, offset/count + 1, 0
+	}
+	return offset + count, 1, offset
 }
 
 // photosetPhoto represents a photo in a photoset
@@ -157,136 +198,78 @@ type photosetPhoto struct {
 	Farm   int    `json:"farm"`
 }
 
-// findPhotosetByName finds a photoset by name
-func (c *FlickrPullClient) findPhotosetByName(ctx context.Context, userID, name string) (string, error) {
-	params := url.Values{}
-	params.Set("method", "flickr.photosets.getList")
-	params.Set("user_id", userID)
-	params.Set("format", "json")
-	params.Set("nojsoncallback", "1")
-	
-	resp, err := c.api.makeAPICall(ctx, "GET", params)
-	if err != nil {
-		return "", fmt.Errorf("failed to get photosets: %w", err)
-	}
-	
-	var result struct {
-		Photosets struct {
-			Photoset []struct {
-				ID    string `json:"id"`
-				Title struct {
-					Content string `json:"_content"`
-				} `json:"title"`
-				Photos int `json:"photos"`
-			} `json:"photoset"`
-		} `json:"photosets"`
-		Stat    string `json:"stat"`
-		Message string `json:"message,omitempty"`
-	}
-	
-	if err := json.Unmarshal(resp, &result); err != nil {
-		return "", fmt.Errorf("failed to parse photosets response: %w", err)
-	}
-	
-	if result.Stat != "ok" {
-		return "", fmt.Errorf("API error: %s", result.Message)
-	}
-
-	// Debug: print available photosets
-	if os.Getenv("IMGUP_DEBUG") != "" {
-		fmt.Fprintf(os.Stderr, "DEBUG: Available photosets:\n")
-		for _, ps := range result.Photosets.Photoset {
-			fmt.Fprintf(os.Stderr, "  - %s (ID: %s, %d photos)\n", ps.Title.Content, ps.ID, ps.Photos)
-		}
-	}
-
-	// Find photoset by name
-	for _, ps := range result.Photosets.Photoset {
-		if strings.EqualFold(ps.Title.Content, name) {
-			return ps.ID, nil
-		}
-	}
-
-	// If not found, suggest similar photosets
-	var suggestions []string
-	for _, ps := range result.Photosets.Photoset {
-		if strings.Contains(strings.ToLower(ps.Title.Content), strings.ToLower(name)) ||
-		   strings.Contains(strings.ToLower(name), strings.ToLower(ps.Title.Content)) {
-			suggestions = append(suggestions, ps.Title.Content)
-		}
-	}
-
-	if len(suggestions) > 0 {
-		return "", fmt.Errorf("photoset '%s' not found. Did you mean one of: %s", name, strings.Join(suggestions, ", "))
-	}
-
-	return "", fmt.Errorf("photoset '%s' not found", name)
-}
-
-// getPhotosetPhotos gets photos from a photoset
-func (c *FlickrPullClient) getPhotosetPhotos(ctx context.Context, photosetID string, count int) ([]photosetPhoto, error) {
+// getPhotosetPhotos gets a page of photos from a photoset and the photoset's total
+func (c *FlickrPullClient) getPhotosetPhotos(ctx context.Context, photosetID string, perPage, page int) ([]photosetPhoto, int, error) {
 	params := url.Values{}
 	params.Set("method", "flickr.photosets.getPhotos")
 	params.Set("photoset_id", photosetID)
-	params.Set("per_page", fmt.Sprintf("%d", count))
+	params.Set("per_page", fmt.Sprintf("%d", perPage))
+	params.Set("page", fmt.Sprintf("%d", page))
 	params.Set("format", "json")
 	params.Set("nojsoncallback", "1")
 	
 	resp, err := c.api.makeAPICall(ctx, "GET", params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get photoset photos: %w", err)
+		return nil, 0, fmt.Errorf("failed to get photoset photos: %w", err)
 	}
 	
 	var result struct {
 		Photoset struct {
 			Photo []photosetPhoto `json:"photo"`
+			Total json.Number     `json:"total"`
 		} `json:"photoset"`
 		Stat    string `json:"stat"`
+		Code    int    `json:"code,omitempty"`
 		Message string `json:"message,omitempty"`
 	}
 	
 	if err := json.Unmarshal(resp, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse photoset photos response: %w", err)
+		return nil, 0, fmt.Errorf("failed to parse photoset photos response: %w", err)
 	}
 	
 	if result.Stat != "ok" {
-		return nil, fmt.Errorf("API error: %s", result.Message)
+		return nil, 0, &FlickrError{Code: result.Code, Message: result.Message}
 	}
 	
-	return result.Photoset.Photo, nil
+	total, _ := result.Photoset.Total.Int64()
+	return result.Photoset.Photo, int(total), nil
 }
 
-// getUserPhotos gets photos from user's photostream
-func (c *FlickrPullClient) getUserPhotos(ctx context.Context, userID string, count int) ([]photosetPhoto, error) {
+// getUserPhotos gets a page of photos from user's photostream and the photostream's total
+func (c *FlickrPullClient) getUserPhotos(ctx context.Context, userID string, perPage, page int) ([]photosetPhoto, int, error) {
 	params := url.Values{}
 	params.Set("method", "flickr.people.getPhotos")
 	params.Set("user_id", userID)
-	params.Set("per_page", fmt.Sprintf("%d", count))
+	params.Set("per_page", fmt.Sprintf("%d", perPage))
+	params.Set("page", fmt.Sprintf("%d", page))
 	params.Set("format", "json")
 	params.Set("nojsoncallback", "1")
 	
 	resp, err := c.api.makeAPICall(ctx, "GET", params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user photos: %w", err)
+		return nil, 0, fmt.Errorf("failed to get user photos: %w", err)
 	}
 	
 	var result struct {
 		Photos struct {
 			Photo []photosetPhoto `json:"photo"`
+			Total json.Number     `json:"total"`
 		} `json:"photos"`
 		Stat    string `json:"stat"`
+		Code    int    `json:"code,omitempty"`
 		Message string `json:"message,omitempty"`
 	}
 	
 	if err := json.Unmarshal(resp, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse user photos response: %w", err)
+		return nil, 0, fmt.Errorf("failed to parse user photos response: %w", err)
 	}
 	
 	if result.Stat != "ok" {
-		return nil, fmt.Errorf("API error: %s", result.Message)
+		return nil, 0, &FlickrError{Code: result.Code, Message: result.Message}
 	}
 	
-	return result.Photos.Photo, nil
+	total, _ := result.Photos.Total.Int64()
+	return result.Photos.Photo, int(total), nil
 }
 
 // photoInfo contains detailed photo information
@@ -324,6 +307,7 @@ func (c *FlickrPullClient) getPhotoInfo(ctx context.Context, photoID string) (*p
 			} `json:"tags"`
 		} `json:"photo"`
 		Stat    string `json:"stat"`
+		Code    int    `json:"code,omitempty"`
 		Message string `json:"message,omitempty"`
 	}
 	
@@ -332,7 +316,7 @@ func (c *FlickrPullClient) getPhotoInfo(ctx context.Context, photoID string) (*p
 	}
 	
 	if result.Stat != "ok" {
-		return nil, fmt.Errorf("API error: %s", result.Message)
+		return nil, &FlickrError{Code: result.Code, Message: result.Message}
 	}
 	
 	info := &photoInfo{
@@ -0,0 +1,62 @@
+package backends
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// flickrBase58Alphabet is Flickr's base58 alphabet: digits and letters
+// without 0, O, I and l
+const flickrBase58Alphabet = "123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// FlickrShortURL returns the flic.kr short link for a numeric photo ID
+func FlickrShortURL(photoID string) (string, error) {
+	id, err := strconv.ParseUint(photoID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid Flickr photo ID %q: %w", photoID, err)
+	}
+	return "https://flic.kr/p/" + encodeFlickrBase58(id), nil
+}
+
+// encodeFlickrBase58 encodes n in Flickr's base58 alphabet
+func encodeFlickrBase58(n uint64) string {
+	if n == 0 {
+		return string(flickrBase58Alphabet[0])
+	}
+	var buf []byte
+	for n > 0 {
+		buf = append(buf, flickrBase58Alphabet[n%58])
+		n /= 58
+	}
+	// Digits were produced least significant first
+	for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	return string(buf)
+}
+
+// FlickrShortURLPhotoID returns the numeric photo ID a flic.kr short link
+// like https://flic.kr/p/2qLaQ9o stands for
+func FlickrShortURLPhotoID(shortURL string) (string, error) {
+	i := strings.Index(shortURL, "/p/")
+	if i < 0 {
+		return "", fmt.Errorf("not a flic.kr photo link: %s", shortURL)
+	}
+	code := strings.Trim(shortURL[i+len("/p/"):], "/")
+	if code == "" {
+		return "", fmt.Errorf("not a flic.kr photo link: %s", shortURL)
+	}
+	var n uint64
+	for _, r := range code {
+		digit := strings.IndexRune(flickrBase58Alphabet, r)
+		if digit < 0 {
+			return "", fmt.Errorf("invalid flic.kr link %s: %q isn't a base58 digit", shortURL, r)
+		}
+		if n > (^uint64(0)-uint64(digit))/58 {
+			return "", fmt.Errorf("invalid flic.kr link %s: photo ID out of range", shortURL)
+		}
+		n = n*58 + uint64(digit)
+	}
+	return strconv.FormatUint(n, 10), nil
+}
@@ -0,0 +1,93 @@
+package backends
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ChecksumTagPrefix starts the machine tag imgup adds to Flickr uploads so
+// duplicates can be found on Flickr itself
+const ChecksumTagPrefix = "imgupv2:checksum="
+
+// ChecksumTag returns the checksum machine tag for a file's MD5
+func ChecksumTag(md5Hash string) string {
+	return ChecksumTagPrefix + md5Hash
+}
+
+// WithChecksumTag returns tags with exactly one checksum machine tag, for
+// md5Hash, replacing any checksum tags already in the list
+func WithChecksumTag(tags []string, md5Hash string) []string {
+	result := make([]string, 0, len(tags)+1)
+	for _, tag := range tags {
+		if !strings.HasPrefix(strings.ToLower(tag), ChecksumTagPrefix) {
+			result = append(result, tag)
+		}
+	}
+	return append(result, ChecksumTag(md5Hash))
+}
+
+// RemoveTag removes a tag, matched by its raw text, from a photo. It reports
+// whether the photo had the tag.
+func (api *FlickrAPI) RemoveTag(ctx context.Context, photoID, tag string) (bool, error) {
+	params := url.Values{}
+	params.Set("method", "flickr.photos.getInfo")
+	params.Set("photo_id", photoID)
+	params.Set("format", "json")
+	params.Set("nojsoncallback", "1")
+
+	resp, err := api.makeAPICall(ctx, "GET", params)
+	if err != nil {
+		return false, fmt.Errorf("failed to get photo info: %w", err)
+	}
+
+	var result struct {
+		Photo struct {
+			Tags struct {
+				Tag []struct {
+					ID  string `json:"id"`
+					Raw string `json:"raw"`
+				} `json:"tag"`
+			} `json:"tags"`
+		} `json:"photo"`
+		Stat    string `json:"stat"`
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return false, fmt.Errorf("failed to parse photo info response: %w", err)
+	}
+	if result.Stat != "ok" {
+		return false, &FlickrError{Code: result.Code, Message: result.Message}
+	}
+
+	// Flickr removes tags by ID, which getInfo gives per photo
+	tagID := ""
+	for _, t := range result.Photo.Tags.Tag {
+		if strings.EqualFold(t.Raw, tag) {
+			tagID = t.ID
+			break
+		}
+	}
+	if tagID == "" {
+		return false, nil
+	}
+
+	params = url.Values{}
+	params.Set("method", "flickr.photos.removeTag")
+	params.Set("tag_id", tagID)
+	params.Set("format", "json")
+	params.Set("nojsoncallback", "1")
+
+	resp, err = api.makeAPICall(ctx, "POST", params)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove tag: %w", err)
+	}
+	if err := checkFlickrStat(resp); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -10,7 +10,6 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
 	"strings"
 	
 	"github.com/dghubble/oauth1"
@@ -27,6 +26,12 @@ type FlickrUploader struct {
 	ConsumerSecret string
 	AccessToken    string
 	AccessSecret   string
+	
+	// Optional settings applied after upload
+	SafetyLevel string // safe, moderate, restricted
+	ContentType string // photo, screenshot, other
+	HiddenFromSearch bool // hide from public searches
+	Filename    string // name Flickr stores the file under; defaults to the file's own name
 }
 
 // UploadResult contains the result of an upload
@@ -103,8 +108,39 @@ func (u *FlickrUploader) Upload(ctx context.Context, imagePath string, title, de
 		}
 	}
 	
-	// Get the photo info and URLs regardless of privacy setting
 	api := &FlickrAPI{FlickrUploader: u}
+	
+	// Step 5: Set safety level if requested
+	if u.SafetyLevel != "" {
+		if os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Setting safety level: %s\n", u.SafetyLevel)
+		}
+		if err := api.SetSafetyLevel(ctx, photoID, u.SafetyLevel); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to set safety level: %v", err))
+		}
+	}
+	
+	// Step 6: Set content type if requested
+	if u.ContentType != "" {
+		if os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Setting content type: %s\n", u.ContentType)
+		}
+		if err := api.SetContentType(ctx, photoID, u.ContentType); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to set content type: %v", err))
+		}
+	}
+	
+	// Step 7: Hide from public search if requested
+	if u.HiddenFromSearch {
+		if os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Hiding photo from public search\n")
+		}
+		if err := api.SetHiddenFromSearch(ctx, photoID, true); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to hide photo from search: %v", err))
+		}
+	}
+	
+	// Get the photo info and URLs regardless of privacy setting
 	photoInfo, err := api.GetPhotoInfo(ctx, photoID)
 	if err != nil {
 		// Fall back to basic URL if we can't get photo info
@@ -148,7 +184,7 @@ func (u *FlickrUploader) uploadPhoto(ctx context.Context, imagePath string) (str
 	writer := multipart.NewWriter(&buf)
 	
 	// Add image file
-	part, err := writer.CreateFormFile("photo", filepath.Base(imagePath))
+	part, err := writer.CreateFormFile("photo", uploadFilename(u.Filename, imagePath))
 	if err != nil {
 		return "", fmt.Errorf("failed to create form file: %w", err)
 	}
@@ -194,8 +230,13 @@ func (u *FlickrUploader) uploadPhoto(ctx context.Context, imagePath string) (str
 		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 	
+	// Flickr reports upload errors (quota, file type, auth) in the XML body
+	if flickrErr := parseFlickrUploadError(body); flickrErr != nil {
+		return "", flickrErr
+	}
+	
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, body)
+		return "", &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 	
 	// Parse response to get photo ID
@@ -204,11 +245,6 @@ func (u *FlickrUploader) uploadPhoto(ctx context.Context, imagePath string) (str
 		return "", fmt.Errorf("failed to parse photo ID from response: %s", body)
 	}
 	
-	// Check if response indicates an error
-	if strings.Contains(string(body), "stat=\"fail\"") || strings.Contains(string(body), "<err") {
-		return "", fmt.Errorf("upload failed - Flickr returned error: %s", body)
-	}
-	
 	if os.Getenv("IMGUP_DEBUG") != "" {
 		fmt.Fprintf(os.Stderr, "DEBUG: Photo uploaded successfully with ID: %s\n", photoID)
 		fmt.Fprintf(os.Stderr, "DEBUG: Full upload response: %s\n", string(body))
@@ -238,6 +274,7 @@ func (u *FlickrUploader) setPhotoMeta(ctx context.Context, photoID, title, descr
 	// Parse response
 	var result struct {
 		Stat    string `json:"stat"`
+		Code    int    `json:"code,omitempty"`
 		Message string `json:"message,omitempty"`
 	}
 	
@@ -246,7 +283,7 @@ func (u *FlickrUploader) setPhotoMeta(ctx context.Context, photoID, title, descr
 	}
 	
 	if result.Stat != "ok" {
-		return fmt.Errorf("API error: %s", result.Message)
+		return &FlickrError{Code: result.Code, Message: result.Message}
 	}
 	
 	return nil
@@ -276,6 +313,7 @@ func (u *FlickrUploader) addTags(ctx context.Context, photoID string, tags []str
 	// Parse response
 	var result struct {
 		Stat    string `json:"stat"`
+		Code    int    `json:"code,omitempty"`
 		Message string `json:"message,omitempty"`
 	}
 	
@@ -284,7 +322,7 @@ func (u *FlickrUploader) addTags(ctx context.Context, photoID string, tags []str
 	}
 	
 	if result.Stat != "ok" {
-		return fmt.Errorf("API error: %s", result.Message)
+		return &FlickrError{Code: result.Code, Message: result.Message}
 	}
 	
 	return nil
@@ -320,6 +358,7 @@ func (u *FlickrUploader) setPhotoPerms(ctx context.Context, photoID string, isPu
 	// Parse response
 	var result struct {
 		Stat    string `json:"stat"`
+		Code    int    `json:"code,omitempty"`
 		Message string `json:"message,omitempty"`
 	}
 	
@@ -328,7 +367,7 @@ func (u *FlickrUploader) setPhotoPerms(ctx context.Context, photoID string, isPu
 	}
 	
 	if result.Stat != "ok" {
-		return fmt.Errorf("API error: %s", result.Message)
+		return &FlickrError{Code: result.Code, Message: result.Message}
 	}
 	
 	return nil
@@ -0,0 +1,91 @@
+package backends
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// formatAliases maps file extensions to the format names used below
+var formatAliases = map[string]string{
+	"jpg":  "jpeg",
+	"jpe":  "jpeg",
+	"tif":  "tiff",
+	"heif": "heic",
+	"mpg":  "mpeg",
+	"mts":  "m2ts",
+}
+
+// knownFormats are the formats Supports has an opinion on. Anything else is
+// left for the service to accept or reject.
+var knownFormats = map[string]bool{
+	"jpeg": true, "png": true, "gif": true, "tiff": true, "webp": true,
+	"heic": true, "avif": true, "bmp": true, "psd": true,
+	"dng": true, "cr2": true, "cr3": true, "nef": true, "arw": true, "raf": true, "orf": true, "rw2": true,
+	"mp4": true, "mov": true, "m4v": true, "avi": true, "wmv": true, "mpeg": true, "3gp": true, "m2ts": true, "ogv": true, "webm": true,
+}
+
+// supportedFormats lists the formats each service accepts for upload. For
+// Mastodon and Bluesky that's the image fetched from Flickr or SmugMug for a
+// post.
+var supportedFormats = map[string]map[string]bool{
+	"flickr": {
+		"jpeg": true, "png": true, "gif": true, "tiff": true,
+		"mp4": true, "mov": true, "m4v": true, "avi": true, "wmv": true, "mpeg": true, "3gp": true, "m2ts": true, "ogv": true,
+	},
+	"smugmug": {
+		"jpeg": true, "png": true, "gif": true, "heic": true,
+		"mp4": true, "mov": true, "m4v": true, "avi": true, "wmv": true, "mpeg": true, "3gp": true, "m2ts": true,
+	},
+	"mastodon": {
+		"jpeg": true, "png": true, "gif": true, "webp": true, "heic": true, "avif": true,
+		"mp4": true, "mov": true, "m4v": true, "webm": true,
+	},
+	"bluesky": {
+		"jpeg": true, "png": true, "gif": true, "webp": true,
+		"mp4": true, "mov": true, "mpeg": true, "webm": true,
+	},
+}
+
+// FileFormat returns the format of the file at path from its extension,
+// e.g. "jpeg" for photo.JPG
+func FileFormat(path string) string {
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	if alias, ok := formatAliases[format]; ok {
+		return alias
+	}
+	return format
+}
+
+// URLFormat returns the format of the file at rawURL from the extension of
+// its path, ignoring any query string
+func URLFormat(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return FileFormat(u.Path)
+}
+
+// Supports reports whether service accepts uploads in format (see
+// FileFormat). Formats imgup doesn't know are assumed to be accepted.
+func Supports(service, format string) bool {
+	if !knownFormats[format] {
+		return true
+	}
+	return supportedFormats[service][format]
+}
+
+// uploadFilename returns the name to send with the file at path: name when
+// set, keeping path's extension if the two formats differ (a transcoded
+// copy, say), or else path's own name
+func uploadFilename(name, path string) string {
+	name = filepath.Base(name)
+	if name == "." || name == string(filepath.Separator) {
+		return filepath.Base(path)
+	}
+	if FileFormat(name) != FileFormat(path) {
+		name = strings.TrimSuffix(name, filepath.Ext(name)) + filepath.Ext(path)
+	}
+	return name
+}
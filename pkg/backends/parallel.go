@@ -0,0 +1,24 @@
+package backends
+
+import "sync"
+
+// pullWorkers is how many images a pull looks up at once. It's kept low so a
+// pull doesn't burst past the services' API rate limits.
+const pullWorkers = 4
+
+// forEachParallel calls fn for every index below n, running at most workers
+// calls at a time, and returns once they have all finished
+func forEachParallel(n, workers int, fn func(i int)) {
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
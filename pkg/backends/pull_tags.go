@@ -0,0 +1,55 @@
+package backends
+
+import (
+	"strings"
+	"unicode"
+)
+
+// parsePullTags splits a comma-separated tag filter, dropping blanks
+func parsePullTags(tags string) []string {
+	var tagList []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tagList = append(tagList, tag)
+		}
+	}
+	return tagList
+}
+
+// normalizeTag reduces a tag to the form Flickr matches on: lowercase
+// letters and digits only, so "New York" and "newyork" are the same tag
+func normalizeTag(tag string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(tag) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// splitKeywords splits a SmugMug keyword string, which may use ';' or ','
+func splitKeywords(keywords string) []string {
+	var result []string
+	for _, kw := range strings.FieldsFunc(keywords, func(r rune) bool { return r == ';' || r == ',' }) {
+		if kw = strings.TrimSpace(kw); kw != "" {
+			result = append(result, kw)
+		}
+	}
+	return result
+}
+
+// hasAllTags reports whether keywords contain every wanted tag, matching
+// Flickr's tag search (tag_mode=all)
+func hasAllTags(keywords []string, want []string) bool {
+	have := make(map[string]bool, len(keywords))
+	for _, kw := range keywords {
+		have[normalizeTag(kw)] = true
+	}
+	for _, tag := range want {
+		if !have[normalizeTag(tag)] {
+			return false
+		}
+	}
+	return true
+}
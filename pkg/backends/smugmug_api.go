@@ -1,6 +1,7 @@
 package backends
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -27,6 +28,7 @@ type Album struct {
 	WebURI      string `json:"WebUri"`
 	NodeID      string `json:"NodeId"`
 	ImageCount  int    `json:"ImageCount"`
+	Privacy     string `json:"Privacy,omitempty"` // Public, Unlisted or Private
 }
 
 // Image represents a SmugMug image
@@ -36,6 +38,8 @@ type Image struct {
 	WebURI   string `json:"WebUri"`
 	Title    string `json:"Title,omitempty"`
 	Caption  string `json:"Caption,omitempty"`
+	Keywords string `json:"Keywords,omitempty"` // semicolon-separated
+	ArchivedMD5 string `json:"ArchivedMd5,omitempty"` // MD5 of the original SmugMug stored
 }
 
 // AlbumImage represents an image within an album context
@@ -171,6 +175,125 @@ func (api *SmugMugAPI) ListAlbums(ctx context.Context) ([]Album, error) {
 	return allAlbums, nil
 }
 
+// FindAlbum returns the authenticated user's album with the given name,
+// ignoring case
+func (api *SmugMugAPI) FindAlbum(ctx context.Context, albumName string) (*Album, error) {
+	albums, err := api.ListAlbums(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Debug: print available albums if debug mode is on
+	if os.Getenv("IMGUP_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "DEBUG: Available albums:\n")
+		for _, album := range albums {
+			fmt.Fprintf(os.Stderr, "  - %s (Key: %s, %d images)\n", album.Name, album.AlbumKey, album.ImageCount)
+		}
+	}
+
+	for _, album := range albums {
+		if strings.EqualFold(album.Name, albumName) {
+			return &album, nil
+		}
+	}
+
+	// If not found, suggest similar albums
+	var suggestions []string
+	for _, album := range albums {
+		if strings.Contains(strings.ToLower(album.Name), strings.ToLower(albumName)) ||
+		   strings.Contains(strings.ToLower(albumName), strings.ToLower(album.Name)) {
+			suggestions = append(suggestions, album.Name)
+		}
+	}
+
+	return nil, &AlbumNotFoundError{Name: albumName, Suggestions: suggestions}
+}
+
+// AlbumNotFoundError is returned by FindAlbum when the user has no album
+// with the name
+type AlbumNotFoundError struct {
+	Name        string
+	Suggestions []string // albums with similar names
+}
+
+func (e *AlbumNotFoundError) Error() string {
+	if len(e.Suggestions) > 0 {
+		return fmt.Sprintf("album '%s' not found. Did you mean one of: %s", e.Name, strings.Join(e.Suggestions, ", "))
+	}
+	return fmt.Sprintf("album '%s' not found", e.Name)
+}
+
+// CreateAlbum creates an unlisted album with the given name at the top of
+// the authenticated user's site
+func (api *SmugMugAPI) CreateAlbum(ctx context.Context, name string) (*Album, error) {
+	userInfo, err := api.GetAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+	endpoint := fmt.Sprintf("%s/api/v2/folder/user/%s!albums", smugmugAPIURL, userInfo.Response.User.NickName)
+
+	body, err := json.Marshal(map[string]string{
+		"Name":    name,
+		"UrlName": albumURLName(name),
+		"Privacy": "Unlisted",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	// Create OAuth1 config and client
+	config := oauth1.Config{
+		ConsumerKey:    api.ConsumerKey,
+		ConsumerSecret: api.ConsumerSecret,
+	}
+
+	token := oauth1.NewToken(api.AccessToken, api.AccessSecret)
+	httpClient := config.Client(ctx, token)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create album: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Response struct {
+			Album Album `json:"Album"`
+		} `json:"Response"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result.Response.Album, nil
+}
+
+// albumURLName turns an album name into the URL name SmugMug requires:
+// letters, digits and dashes, starting with a capital letter or digit
+func albumURLName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	urlName := strings.Join(words, "-")
+	if urlName == "" {
+		return "Album"
+	}
+	return strings.ToUpper(urlName[:1]) + urlName[1:]
+}
+
 // fetchAlbumsPage fetches a single page of albums
 func (api *SmugMugAPI) fetchAlbumsPage(ctx context.Context, pageURL string) ([]Album, string, error) {
 	// Create OAuth1 config and client
@@ -394,6 +517,16 @@ func (api *SmugMugAPI) GetImage(ctx context.Context, imageURI string) (*Image, e
 	return &result.Response.Image, nil
 }
 
+// ArchivedMD5 returns the MD5 of the original SmugMug stored for an image.
+// It's empty while SmugMug is still processing a new upload.
+func (api *SmugMugAPI) ArchivedMD5(ctx context.Context, imageKey string) (string, error) {
+	image, err := api.GetImage(ctx, "/api/v2/image/"+imageKey)
+	if err != nil {
+		return "", err
+	}
+	return image.ArchivedMD5, nil
+}
+
 // GetAlbumImage gets details for an image in album context
 func (api *SmugMugAPI) GetAlbumImage(ctx context.Context, albumImageURI string) (map[string]interface{}, error) {
 	// Ensure albumImageURI starts with / for proper URL construction
@@ -486,7 +619,7 @@ func (api *SmugMugAPI) GetAlbumImages(ctx context.Context, albumKey string) ([]A
 		smugmugAPIURL, albumKey)
 	
 	for nextPage != "" {
-		images, next, err := api.fetchAlbumImagesPage(ctx, nextPage)
+		images, next, _, err := api.fetchAlbumImagesPage(ctx, nextPage)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch album images: %w", err)
 		}
@@ -505,8 +638,24 @@ func (api *SmugMugAPI) GetAlbumImages(ctx context.Context, albumKey string) ([]A
 	return allImages, nil
 }
 
-// fetchAlbumImagesPage fetches a single page of album images
-func (api *SmugMugAPI) fetchAlbumImagesPage(ctx context.Context, pageURL string) ([]AlbumImageDetail, string, error) {
+// GetAlbumImagesRange retrieves count images from an album starting at the
+// zero-based offset, along with the album's total image count
+func (api *SmugMugAPI) GetAlbumImagesRange(ctx context.Context, albumKey string, offset, count int) ([]AlbumImageDetail, int, error) {
+	// SmugMug's Start is one-based
+	pageURL := fmt.Sprintf("%s/api/v2/album/%s!images?start=%d&count=%d&_expand=ArchivedMd5,FileName,ImageKey,UploadKey,DateTimeOriginal,DateTimeUploaded,Keywords,OriginalSize,Caption,Title",
+		smugmugAPIURL, albumKey, offset+1, count)
+	
+	images, _, total, err := api.fetchAlbumImagesPage(ctx, pageURL)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch album images: %w", err)
+	}
+	
+	return images, total, nil
+}
+
+// fetchAlbumImagesPage fetches a single page of album images, returning the
+// next page URL and the album's total image count
+func (api *SmugMugAPI) fetchAlbumImagesPage(ctx context.Context, pageURL string) ([]AlbumImageDetail, string, int, error) {
 	// Create OAuth1 config and client
 	config := oauth1.Config{
 		ConsumerKey:    api.ConsumerKey,
@@ -518,24 +667,24 @@ func (api *SmugMugAPI) fetchAlbumImagesPage(ctx context.Context, pageURL string)
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create request: %w", err)
+		return nil, "", 0, fmt.Errorf("failed to create request: %w", err)
 	}
 	
 	req.Header.Set("Accept", "application/json")
 	
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get album images: %w", err)
+		return nil, "", 0, fmt.Errorf("failed to get album images: %w", err)
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, "", 0, fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
 	
 	var result AlbumImagesResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, "", fmt.Errorf("failed to parse response: %w", err)
+		return nil, "", 0, fmt.Errorf("failed to parse response: %w", err)
 	}
 	
 	if os.Getenv("IMGUP_DEBUG") != "" {
@@ -547,7 +696,7 @@ func (api *SmugMugAPI) fetchAlbumImagesPage(ctx context.Context, pageURL string)
 		}
 	}
 	
-	return result.Response.AlbumImage, result.Response.Pages.NextPage, nil
+	return result.Response.AlbumImage, result.Response.Pages.NextPage, result.Response.Pages.Total, nil
 }
 
 // SearchAlbumImages searches for images in an album by filename or other criteria
@@ -594,3 +743,142 @@ func (api *SmugMugAPI) SearchAlbumImages(ctx context.Context, albumKey string, q
 	
 	return result.Response.AlbumImage, nil
 }
+
+// smugmugPrivacyLevels lists the accepted --smugmug-privacy values
+var smugmugPrivacyLevels = []string{"public", "unlisted", "private"}
+
+// ValidateSmugMugPrivacy checks that a SmugMug privacy level is supported
+func ValidateSmugMugPrivacy(level string) error {
+	for _, l := range smugmugPrivacyLevels {
+		if l == strings.ToLower(level) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid SmugMug privacy '%s'. Must be 'public', 'unlisted', or 'private'", level)
+}
+
+// SmugMugPrivacyHidden reports whether an image with a privacy level is
+// hidden from its gallery. Only "unlisted" is: the image is left out of the
+// gallery but still viewable by its link. "public" and "private" images are
+// shown, and it's the album's own privacy that makes them one or the other.
+func SmugMugPrivacyHidden(level string) bool {
+	return strings.ToLower(level) == "unlisted"
+}
+
+// SmugMugAlbumPrivacy returns the album Privacy ("Public", "Unlisted" or
+// "Private") an image with a privacy level needs to be that private: a
+// private image needs a private album, and a public one a public album.
+// Unlisted images are hidden from the gallery, so any album will do and it
+// returns "".
+func SmugMugAlbumPrivacy(level string) string {
+	switch strings.ToLower(level) {
+	case "private":
+		return "Private"
+	case "public":
+		return "Public"
+	}
+	return ""
+}
+
+// SetImagePrivacy shows or hides an uploaded image as SmugMugPrivacyHidden
+// says for level. SmugMug keeps privacy on the album, so this can't make an
+// image private by itself; see SmugMugAlbumPrivacy.
+func (api *SmugMugAPI) SetImagePrivacy(ctx context.Context, imageKey, level string) error {
+	if err := ValidateSmugMugPrivacy(level); err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/api/v2/image/%s", smugmugAPIURL, imageKey)
+
+	body, err := json.Marshal(map[string]bool{"Hidden": SmugMugPrivacyHidden(level)})
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	// Create OAuth1 config and client
+	config := oauth1.Config{
+		ConsumerKey:    api.ConsumerKey,
+		ConsumerSecret: api.ConsumerSecret,
+	}
+
+	token := oauth1.NewToken(api.AccessToken, api.AccessSecret)
+	httpClient := config.Client(ctx, token)
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// UpdateImageMetadata changes the title, caption and keywords of an uploaded
+// image, the fields the upload sets from its X-Smug headers. Empty values
+// leave those fields as they are; keywords replace the image's current ones.
+func (api *SmugMugAPI) UpdateImageMetadata(ctx context.Context, imageURI, title, caption string, keywords []string) error {
+	// Ensure imageURI starts with / for proper URL construction
+	if !strings.HasPrefix(imageURI, "/") {
+		imageURI = "/" + imageURI
+	}
+	endpoint := smugmugAPIURL + imageURI
+
+	fields := map[string]string{}
+	if title != "" {
+		fields["Title"] = title
+	}
+	if caption != "" {
+		fields["Caption"] = caption
+	}
+	if len(keywords) > 0 {
+		fields["Keywords"] = strings.Join(keywords, "; ")
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	// Create OAuth1 config and client
+	config := oauth1.Config{
+		ConsumerKey:    api.ConsumerKey,
+		ConsumerSecret: api.ConsumerSecret,
+	}
+
+	token := oauth1.NewToken(api.AccessToken, api.AccessSecret)
+	httpClient := config.Client(ctx, token)
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
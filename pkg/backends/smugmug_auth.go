@@ -56,7 +56,7 @@ func (a *SmugMugAuth) Authenticate(ctx context.Context) (*oauth1.Token, string,
 	
 	// Start callback server
 	verifier := make(chan string)
-	server := &http.Server{Addr: ":8749"}
+	server := &http.Server{Addr: "127.0.0.1:8749"}
 	
 	http.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "<html><body><h1>SmugMug authorization successful!</h1><p>You can close this window.</p></body></html>")
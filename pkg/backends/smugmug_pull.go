@@ -27,8 +27,9 @@ func NewSmugMugPullClient(cfg *config.SmugMugConfig) *SmugMugPullClient {
 	}
 }
 
-// PullImages fetches recent images from SmugMug
-func (c *SmugMugPullClient) PullImages(ctx context.Context, albumName string, count int, tags string) ([]types.PullImage, error) {
+// PullImages fetches recent images from SmugMug, skipping the first offset
+// images. It also returns the total number of images available.
+func (c *SmugMugPullClient) PullImages(ctx context.Context, albumName string, count, offset int, tags string) ([]types.PullImage, int, error) {
 	// If no album name is provided, use the configured album
 	if albumName == "" {
 		if c.cfg.PullAlbum != "" {
@@ -38,55 +39,36 @@ func (c *SmugMugPullClient) PullImages(ctx context.Context, albumName string, co
 		}
 	}
 
-	// Get user info
-	userResp, err := c.api.GetAuthenticatedUser(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user info: %w", err)
-	}
-
 	// Find the album by name
-	album, err := c.findAlbumByName(ctx, userResp.Response.User.NickName, albumName)
+	album, err := c.api.FindAlbum(ctx, albumName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find album '%s': %w", albumName, err)
+		return nil, 0, fmt.Errorf("failed to find album '%s': %w", albumName, err)
 	}
 
-	// Get images from the album
-	images, err := c.api.GetAlbumImages(ctx, album.AlbumKey)
+	// Get images from the album. Without a tag filter only the requested
+	// range is fetched; tag filtering happens here, so it needs the whole album.
+	filtering := len(parsePullTags(tags)) > 0
+	var images []AlbumImageDetail
+	var total int
+	if !filtering {
+		images, total, err = c.api.GetAlbumImagesRange(ctx, album.AlbumKey, offset, count)
+	} else {
+		images, err = c.api.GetAlbumImages(ctx, album.AlbumKey)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get images from album: %w", err)
+		return nil, 0, fmt.Errorf("failed to get images from album: %w", err)
 	}
 
 	if os.Getenv("IMGUP_DEBUG") != "" {
 		fmt.Fprintf(os.Stderr, "DEBUG: Found %d images in album\n", len(images))
 	}
 
-	// Filter by tags if specified
-	if tags != "" {
-		// Parse comma-separated tags
-		tagList := strings.Split(tags, ",")
-		for i := range tagList {
-			tagList[i] = strings.TrimSpace(tagList[i])
-		}
-
-		// Create a map for faster lookup
-		tagMap := make(map[string]bool)
-		for _, tag := range tagList {
-			tagMap[strings.ToLower(tag)] = true
-		}
-
-		// Filter images that have any of the requested tags
+	// Filter by tags if specified, requiring every tag like Flickr's search does
+	if tagList := parsePullTags(tags); len(tagList) > 0 {
 		var filteredImages []AlbumImageDetail
 		for _, img := range images {
-			if img.Keywords != "" {
-				// Parse image keywords
-				imgTags := strings.Split(img.Keywords, ";")
-				for _, imgTag := range imgTags {
-					imgTag = strings.TrimSpace(imgTag)
-					if tagMap[strings.ToLower(imgTag)] {
-						filteredImages = append(filteredImages, img)
-						break // Found a match, no need to check other tags
-					}
-				}
+			if hasAllTags(splitKeywords(img.Keywords), tagList) {
+				filteredImages = append(filteredImages, img)
 			}
 		}
 
@@ -96,20 +78,49 @@ func (c *SmugMugPullClient) PullImages(ctx context.Context, albumName string, co
 		}
 	}
 
+	// Apply the range to the filtered list
+	if filtering {
+		total = len(images)
+		if offset >= len(images) {
+			images = nil
+		} else {
+			images = images[offset:]
+		}
+	}
+
 	// Limit to requested count
 	if len(images) > count {
 		images = images[:count]
 	}
 
-	// Convert to PullImage format
-	pullImages := make([]types.PullImage, 0, len(images))
-	for i, img := range images {
-		sizes, err := c.getImageSizes(ctx, img.ImageKey)
+	// Fetch each image's sizes a few at a time, keeping the images in order
+	sizes := make([]*types.ImageSizes, len(images))
+	errs := make([]error, len(images))
+	forEachParallel(len(images), pullWorkers, func(i int) {
+		imageSizes, err := c.getImageSizes(ctx, images[i].ImageKey)
 		if err != nil {
 			// Log error but continue with other images
 			if os.Getenv("IMGUP_DEBUG") != "" {
-				fmt.Fprintf(os.Stderr, "DEBUG: Failed to get sizes for image %s: %v\n", img.ImageKey, err)
+				fmt.Fprintf(os.Stderr, "DEBUG: Failed to get sizes for image %s: %v\n", images[i].ImageKey, err)
 			}
+			errs[i] = err
+			return
+		}
+		sizes[i] = &imageSizes
+	})
+
+	// An album whose images all fail would otherwise look empty
+	if len(images) > 0 && errs[0] != nil && allFailed(errs) {
+		return nil, 0, fmt.Errorf("failed to get image sizes from album '%s': %w", albumName, errs[0])
+	}
+
+	// SmugMug only shows a private album's pages and images to its owner
+	private := album.Privacy == "Private"
+
+	// Convert to PullImage format
+	pullImages := make([]types.PullImage, 0, len(images))
+	for i, img := range images {
+		if sizes[i] == nil {
 			continue
 		}
 
@@ -128,59 +139,27 @@ func (c *SmugMugPullClient) PullImages(ctx context.Context, albumName string, co
 			Title:       title,
 			Description: img.Caption,
 			SourceURL:   img.WebURI,
-			Sizes:       sizes,
+			Sizes:       *sizes[i],
+			Private:     private,
 		}
 
 		// Parse keywords into tags
-		if img.Keywords != "" {
-			pullImage.Tags = strings.Split(img.Keywords, ";")
-			// Trim whitespace from tags
-			for j := range pullImage.Tags {
-				pullImage.Tags[j] = strings.TrimSpace(pullImage.Tags[j])
-			}
-		}
+		pullImage.Tags = splitKeywords(img.Keywords)
 
 		pullImages = append(pullImages, pullImage)
 	}
 
-	return pullImages, nil
+	return pullImages, total, nil
 }
 
-// findAlbumByName finds an album by name
-func (c *SmugMugPullClient) findAlbumByName(ctx context.Context, nickname, albumName string) (*Album, error) {
-	albums, err := c.api.ListAlbums(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	// Debug: print available albums if debug mode is on
-	if os.Getenv("IMGUP_DEBUG") != "" {
-		fmt.Fprintf(os.Stderr, "DEBUG: Available albums:\n")
-		for _, album := range albums {
-			fmt.Fprintf(os.Stderr, "  - %s (Key: %s, %d images)\n", album.Name, album.AlbumKey, album.ImageCount)
-		}
-	}
-
-	for _, album := range albums {
-		if strings.EqualFold(album.Name, albumName) {
-			return &album, nil
-		}
-	}
-
-	// If not found, suggest similar albums
-	var suggestions []string
-	for _, album := range albums {
-		if strings.Contains(strings.ToLower(album.Name), strings.ToLower(albumName)) ||
-		   strings.Contains(strings.ToLower(albumName), strings.ToLower(album.Name)) {
-			suggestions = append(suggestions, album.Name)
+// allFailed reports whether every lookup returned an error
+func allFailed(errs []error) bool {
+	for _, err := range errs {
+		if err == nil {
+			return false
 		}
 	}
-
-	if len(suggestions) > 0 {
-		return nil, fmt.Errorf("album '%s' not found. Did you mean one of: %s", albumName, strings.Join(suggestions, ", "))
-	}
-
-	return nil, fmt.Errorf("album '%s' not found", albumName)
+	return true
 }
 
 // getImageSizes fetches all available sizes for an image
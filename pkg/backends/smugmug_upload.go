@@ -9,7 +9,6 @@ import (
 	"mime/multipart"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 	
 	"github.com/dghubble/oauth1"
@@ -27,6 +26,8 @@ type SmugMugUploader struct {
 	AccessToken    string
 	AccessSecret   string
 	AlbumID        string
+	Privacy        string // public, unlisted or private; overrides isPrivate when set
+	Filename       string // name SmugMug stores the file under; defaults to the file's own name
 }
 
 // SmugMugUploadResult contains the result of an upload
@@ -61,7 +62,8 @@ func (u *SmugMugUploader) Upload(ctx context.Context, imagePath string, title, d
 	writer := multipart.NewWriter(&buf)
 	
 	// Add the file
-	part, err := writer.CreateFormFile("file", filepath.Base(imagePath))
+	filename := uploadFilename(u.Filename, imagePath)
+	part, err := writer.CreateFormFile("file", filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create form file: %w", err)
 	}
@@ -96,7 +98,7 @@ func (u *SmugMugUploader) Upload(ctx context.Context, imagePath string, title, d
 	req.Header.Set("X-Smug-AlbumUri", fmt.Sprintf("/api/v2/album/%s", u.AlbumID))
 	req.Header.Set("X-Smug-ResponseType", "JSON")
 	req.Header.Set("X-Smug-Version", "v2")
-	req.Header.Set("X-Smug-Filename", filepath.Base(imagePath))
+	req.Header.Set("X-Smug-Filename", filename)
 	
 	if title != "" {
 		req.Header.Set("X-Smug-Title", title)
@@ -107,7 +109,11 @@ func (u *SmugMugUploader) Upload(ctx context.Context, imagePath string, title, d
 	if len(tags) > 0 {
 		req.Header.Set("X-Smug-Keywords", strings.Join(tags, ";"))
 	}
-	if isPrivate {
+	hidden := isPrivate
+	if u.Privacy != "" {
+		hidden = SmugMugPrivacyHidden(u.Privacy)
+	}
+	if hidden {
 		req.Header.Set("X-Smug-Hidden", "true")
 	}
 	
@@ -120,7 +126,7 @@ func (u *SmugMugUploader) Upload(ctx context.Context, imagePath string, title, d
 	
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 	
 	// Parse the response
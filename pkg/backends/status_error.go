@@ -0,0 +1,29 @@
+package backends
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// StatusError is an upload the service answered with an unexpected HTTP status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("upload failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the request may succeed if it is repeated: the
+// service had a server error or was rate limiting
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// IsRetryableStatusError reports whether err wraps a retryable StatusError
+func IsRetryableStatusError(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Retryable()
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+
 )
 
 // Config holds the application configuration
@@ -14,6 +17,8 @@ type Config struct {
 	Mastodon  MastodonConfig        `json:"mastodon"`
 	Bluesky   BlueskyConfig         `json:"bluesky"`
 	SmugMug   SmugMugConfig         `json:"smugmug"`
+	Describe  DescribeConfig        `json:"describe,omitempty"`
+	GUI       GUIConfig             `json:"gui,omitempty"`
 	Templates map[string]string     `json:"templates,omitempty"`
 }
 
@@ -22,9 +27,56 @@ type DefaultConfig struct {
 	Format          string `json:"format,omitempty"`
 	Service         string `json:"service,omitempty"`
 	DuplicateCheck  *bool  `json:"duplicate_check,omitempty"`  // nil means use default (true)
+	DuplicatePreference string `json:"duplicate_preference,omitempty"` // "cache" (default) or "remote"
+	PruneCacheOnMiss bool  `json:"prune_cache_on_miss,omitempty"` // remove cache entries a remote check finds gone
+	SkipExistingMatch string `json:"skip_existing_match,omitempty"` // title (default) or filename, compared by --skip-existing-in-album
 	PullService     string `json:"pull_service,omitempty"`     // default service for pull command
 	PullCount       int    `json:"pull_count,omitempty"`       // default number of images to pull
+	PullPageSize    int    `json:"pull_page_size,omitempty"`   // images listed at a time when choosing from a pull; 0 lists them all
 	KittyThumbnails bool   `json:"kitty_thumbnails,omitempty"` // enable Kitty terminal thumbnails
+	KittyInTmux     string `json:"kitty_in_tmux,omitempty"`    // text (default) or passthrough, for thumbnails inside tmux
+	LintAlt         bool   `json:"lint_alt,omitempty"`         // always check alt text quality
+	AltMinLength    int    `json:"alt_min_length,omitempty"`   // alt text shorter than this gets a lint warning
+	AltRequired     bool   `json:"alt_required,omitempty"`     // refuse uploads without alt text or a description
+	MinDimension    int    `json:"min_dimension,omitempty"`    // reject images whose longest edge is shorter, in pixels
+	Validate        *bool  `json:"validate,omitempty"`         // decode JPEG, PNG and GIF files before upload; nil means true
+	ConvertHEIC     *bool  `json:"convert_heic,omitempty"`     // convert HEIC to JPEG even for services that accept it; nil means yes for GUI Photos exports, no for the CLI
+	EmbedMetadata   *bool  `json:"embed_metadata,omitempty"`   // write title, description and tags into files with exiftool before the GUI uploads them; nil means true
+	JPEGQuality     int    `json:"jpeg_quality,omitempty"`     // 1-100, for transcodes and HEIC conversion; 0 uses the default
+	SocialJPEGQuality int  `json:"social_jpeg_quality,omitempty"` // 1-100, for images re-encoded to fit social size limits; 0 uses the default
+	SocialKeepEXIF  bool   `json:"social_keep_exif,omitempty"` // keep copyright and artist (never GPS) on images re-encoded for social posts
+	HashtagStyle    string `json:"hashtag_style,omitempty"`    // asis (default), camel or lower, for hashtags built from tags
+	AppendSeparator string `json:"append_separator,omitempty"`  // written before each --append-to-file snippet
+	SocialURLSeparator string `json:"social_url_separator,omitempty"` // between the post text and the photo URL in social posts
+	SocialFallbacks bool   `json:"social_fallbacks,omitempty"` // social posts reuse the title as text and the caption as alt
+	FlickrShortURLs bool   `json:"flickr_short_urls,omitempty"` // use flic.kr short links for Flickr photo URLs
+	TitleFromFilename bool `json:"title_from_filename,omitempty"` // untitled uploads get a title from the filename
+	TitleCleanup    string `json:"title_cleanup,omitempty"`    // full (default), spaces or none
+	SanitizeFilename bool  `json:"sanitize_filename,omitempty"` // drop "(1)" counters and the like from filenames in titles and snippets
+	UserAgent       string `json:"user_agent,omitempty"`       // sent with every request instead of imgupv2/<version>
+}
+
+// DefaultAppendSeparator is written before each snippet appended with --append-to-file
+const DefaultAppendSeparator = "\n%timestamp%\n"
+
+// AppendSeparator returns the configured --append-to-file separator, or the default
+func (c *Config) AppendSeparator() string {
+	if c.Default.AppendSeparator == "" {
+		return DefaultAppendSeparator
+	}
+	return c.Default.AppendSeparator
+}
+
+// DefaultSocialURLSeparator goes between the post text and the photo URL
+const DefaultSocialURLSeparator = "\n\n"
+
+// SocialURLSeparator returns the configured separator between post text and
+// the photo URL, or the default
+func (c *Config) SocialURLSeparator() string {
+	if c.Default.SocialURLSeparator == "" {
+		return DefaultSocialURLSeparator
+	}
+	return c.Default.SocialURLSeparator
 }
 
 // FlickrConfig holds Flickr-specific configuration
@@ -35,6 +87,9 @@ type FlickrConfig struct {
 	AccessSecret   string `json:"access_secret,omitempty"`
 	UserID         string `json:"user_id,omitempty"`
 	PullAlbum      string `json:"pull_album,omitempty"`      // default album for pull command
+	UploadAlbum    string `json:"upload_album,omitempty"`    // photoset uploads are added to, besides the photostream
+	SafetyLevel    string `json:"safety_level,omitempty"`    // default safety level: safe, moderate, restricted
+	ContentType    string `json:"content_type,omitempty"`    // default content type: photo, screenshot, other
 }
 
 // MastodonConfig holds Mastodon-specific configuration
@@ -43,6 +98,78 @@ type MastodonConfig struct {
 	ClientID     string `json:"client_id"`
 	ClientSecret string `json:"client_secret"`
 	AccessToken  string `json:"access_token,omitempty"`
+	Scopes       string `json:"scopes,omitempty"`     // OAuth scopes to request (space-separated)
+	AppScopes    string `json:"app_scopes,omitempty"` // scopes the app was registered with
+	Accounts     map[string]MastodonAccount `json:"accounts,omitempty"` // named extra accounts, chosen with --mastodon-account
+}
+
+// MastodonAccount is one Mastodon identity. Named accounts use an access
+// token created in the instance's Development settings.
+type MastodonAccount struct {
+	InstanceURL  string `json:"instance_url"`
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	AccessToken  string `json:"access_token,omitempty"`
+}
+
+// Account returns the named Mastodon account, or the main account when name is empty
+func (m *MastodonConfig) Account(name string) (MastodonAccount, error) {
+	if name == "" {
+		return MastodonAccount{InstanceURL: m.InstanceURL, ClientID: m.ClientID, ClientSecret: m.ClientSecret, AccessToken: m.AccessToken}, nil
+	}
+	account, ok := m.Accounts[name]
+	if !ok {
+		return MastodonAccount{}, fmt.Errorf("unknown Mastodon account '%s'. Set it up with 'imgup config set mastodon.accounts.%s.instance ...'", name, name)
+	}
+	return account, nil
+}
+
+// AccountNames returns the names of the extra Mastodon accounts, sorted
+func (m *MastodonConfig) AccountNames() []string {
+	names := make([]string, 0, len(m.Accounts))
+	for name := range m.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AnyConfigured reports whether the main account or any named account can post
+func (m *MastodonConfig) AnyConfigured() bool {
+	if main, _ := m.Account(""); main.Configured() {
+		return true
+	}
+	for _, account := range m.Accounts {
+		if account.Configured() {
+			return true
+		}
+	}
+	return false
+}
+
+// Configured reports whether the account has the credentials needed to post
+func (a MastodonAccount) Configured() bool {
+	return a.InstanceURL != "" && a.AccessToken != ""
+}
+
+// DefaultMastodonScopes are the OAuth scopes imgup needs to post with media
+const DefaultMastodonScopes = "read write:media write:statuses"
+
+// RequestedScopes returns the configured scopes, or the defaults
+func (m *MastodonConfig) RequestedScopes() string {
+	if m.Scopes == "" {
+		return DefaultMastodonScopes
+	}
+	return strings.Join(strings.Fields(m.Scopes), " ")
+}
+
+// RegisteredScopes returns the scopes the app was registered with.
+// Apps registered before scopes were configurable used the defaults.
+func (m *MastodonConfig) RegisteredScopes() string {
+	if m.AppScopes == "" {
+		return DefaultMastodonScopes
+	}
+	return m.AppScopes
 }
 
 // BlueskyConfig holds Bluesky-specific configuration
@@ -50,6 +177,98 @@ type BlueskyConfig struct {
 	Handle      string `json:"handle"`
 	AppPassword string `json:"app_password,omitempty"`
 	PDS         string `json:"pds,omitempty"`  // Personal Data Server URL, defaults to https://bsky.social
+	Accounts    map[string]BlueskyAccount `json:"accounts,omitempty"` // named extra accounts, chosen with --bluesky-account
+}
+
+// BlueskyAccount is one Bluesky identity
+type BlueskyAccount struct {
+	Handle      string `json:"handle"`
+	AppPassword string `json:"app_password,omitempty"`
+	PDS         string `json:"pds,omitempty"`
+}
+
+// Account returns the named Bluesky account, or the main account when name is empty
+func (b *BlueskyConfig) Account(name string) (BlueskyAccount, error) {
+	if name == "" {
+		return BlueskyAccount{Handle: b.Handle, AppPassword: b.AppPassword, PDS: b.PDS}, nil
+	}
+	account, ok := b.Accounts[name]
+	if !ok {
+		return BlueskyAccount{}, fmt.Errorf("unknown Bluesky account '%s'. Set it up with 'imgup config set bluesky.accounts.%s.handle ...'", name, name)
+	}
+	return account, nil
+}
+
+// AccountNames returns the names of the extra Bluesky accounts, sorted
+func (b *BlueskyConfig) AccountNames() []string {
+	names := make([]string, 0, len(b.Accounts))
+	for name := range b.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Configured reports whether the account has the credentials needed to post
+func (a BlueskyAccount) Configured() bool {
+	return a.Handle != "" && a.AppPassword != ""
+}
+
+// DescribeConfig sets up alt text generation for uploads without --alt
+type DescribeConfig struct {
+	Endpoint string `json:"endpoint,omitempty"` // URL the image is POSTed to; empty disables generation
+}
+
+// GUIConfig tells the CLI where to find the GUI for 'pull --gui', and the
+// GUI how to run uploads
+type GUIConfig struct {
+	Path   string   `json:"path,omitempty"`   // GUI app bundle or binary; searched for when empty
+	Search []string `json:"search,omitempty"` // places to search, in order; see GUISearchSources
+	UploadRetries *int `json:"upload_retries,omitempty"` // extra runs of imgup after an upload fails in transit
+	WindowResize string `json:"window_resize,omitempty"` // instant (default), smooth or off, when the form grows or shrinks
+}
+
+// DefaultUploadRetries is how many times the GUI runs imgup again after an
+// upload fails in transit, when gui.upload_retries isn't set
+const DefaultUploadRetries = 1
+
+// GUISearchSources are the places the GUI can be searched for:
+// dev is a development build under ~/code/imgupv2, applications is
+// /Applications and ~/Applications, spotlight asks mdfind, and path
+// looks for imgupv2-gui on $PATH
+var GUISearchSources = []string{"dev", "applications", "spotlight", "path"}
+
+// DefaultGUISearch is the search order used when gui.search isn't set
+var DefaultGUISearch = []string{"dev", "applications", "spotlight"}
+
+// WindowResizeModes are the ways the GUI window can follow the form's size:
+// instant jumps to the new size, smooth animates to it, and off keeps the
+// window the size it is
+var WindowResizeModes = []string{"instant", "smooth", "off"}
+
+// ResizeMode returns the configured window resize mode, or instant
+func (g *GUIConfig) ResizeMode() string {
+	if g.WindowResize == "" {
+		return "instant"
+	}
+	return g.WindowResize
+}
+
+// SearchOrder returns the configured GUI search order, or the default
+func (g *GUIConfig) SearchOrder() []string {
+	if len(g.Search) == 0 {
+		return DefaultGUISearch
+	}
+	return g.Search
+}
+
+// UploadRetryCount returns how many times the GUI retries an upload that
+// failed in transit
+func (g *GUIConfig) UploadRetryCount() int {
+	if g.UploadRetries == nil {
+		return DefaultUploadRetries
+	}
+	return *g.UploadRetries
 }
 
 // SmugMugConfig holds SmugMug-specific configuration
@@ -60,22 +279,30 @@ type SmugMugConfig struct {
 	AccessSecret   string `json:"access_secret,omitempty"`
 	AlbumID        string `json:"album_id,omitempty"`
 	PullAlbum      string `json:"pull_album,omitempty"`      // default album for pull command
+	CreatePullAlbum bool  `json:"create_pull_album,omitempty"` // create a missing configured or default pull album without asking
+	UploadAlbum    string `json:"upload_album,omitempty"`    // album uploads go to by name, instead of album_id
+	Privacy        string `json:"privacy,omitempty"`         // default --smugmug-privacy: public, unlisted, private
 }
 
 // DefaultTemplates returns the default output templates
 func DefaultTemplates() map[string]string {
 	return map[string]string{
-		"markdown": "![%alt|description|title|filename%](%image_url%)",
-		"html":     `<img src="%image_url%" alt="%alt|description|title|filename%">`,
-		"url":      "%url%",
-		"json":     `{"photo_id":"%photo_id%","url":"%url%","image_url":"%image_url%"}`,
-		"org":      "[[%image_url%][%alt|description|title|filename%]]",
+		"markdown":   "![%alt|description|title|filename%](%image_url%)",
+		"html":       `<img src="%image_url%" alt="%alt|description|title|filename%">`,
+		"url":        "%url%",
+		"json":       `{"photo_id":"%photo_id%","url":"%url%","image_url":"%image_url%"}`,
+		"org":        "[[%image_url%][%alt|description|title|filename%]]",
+		"org-link":   "[[%url%][%title|alt|description|filename%]]",
+		"org-image":  "#+ATTR_HTML: :alt %alt|description|title|filename%\n[[%image_url%]]",
+		"bbcode":     "[url=%url%][img]%image_url%[/img][/url]",
+		"bbcode-img": "[img]%image_url%[/img]",
+		"rst":        ".. image:: %image_url%\n   :alt: %alt|description|title|filename%\n   :target: %url%",
 	}
 }
 
 // Load loads configuration from the default location
 func Load() (*Config, error) {
-	path := configPath()
+	path := Path()
 	
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -118,9 +345,52 @@ func (c *Config) IsDuplicateCheckEnabled() bool {
 	return *c.Default.DuplicateCheck
 }
 
+// ValidateImages returns whether images are checked for corruption before
+// upload. Defaults to true if not explicitly set.
+func (c *Config) ValidateImages() bool {
+	if c.Default.Validate == nil {
+		return true
+	}
+	return *c.Default.Validate
+}
+
+// ConvertHEICImages returns whether the GUI converts Photos exports in HEIC
+// to JPEG for every service. When false, services that accept HEIC get the
+// original. Defaults to true if not explicitly set.
+func (c *Config) ConvertHEICImages() bool {
+	if c.Default.ConvertHEIC == nil {
+		return true
+	}
+	return *c.Default.ConvertHEIC
+}
+
+// ConvertHEICEverywhere returns whether uploads convert HEIC files to JPEG
+// even for services that accept HEIC. Only when default.convert_heic is
+// explicitly on, so HEIC otherwise uploads unchanged where it can.
+func (c *Config) ConvertHEICEverywhere() bool {
+	return c.Default.ConvertHEIC != nil && *c.Default.ConvertHEIC
+}
+
+// EmbedMetadataInFiles returns whether the GUI writes title, description and
+// tags into a photo with exiftool before uploading it. When false, the file
+// is uploaded unchanged and the service's API sets the metadata. Defaults
+// to true if not explicitly set.
+func (c *Config) EmbedMetadataInFiles() bool {
+	if c.Default.EmbedMetadata == nil {
+		return true
+	}
+	return *c.Default.EmbedMetadata
+}
+
+// PreferRemoteDuplicates returns whether duplicate checks should trust a
+// search on the service over the local cache
+func (c *Config) PreferRemoteDuplicates() bool {
+	return c.Default.DuplicatePreference == "remote"
+}
+
 // Save saves the configuration
 func (c *Config) Save() error {
-	path := configPath()
+	path := Path()
 	
 	// Create directory if needed
 	dir := filepath.Dir(path)
@@ -140,8 +410,14 @@ func (c *Config) Save() error {
 	return nil
 }
 
-// configPath returns the configuration file path
-func configPath() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".config", "imgupv2", "config.json")
+// Path returns the location of the active profile's configuration file,
+// whether or not it exists yet
+func Path() string {
+	return filepath.Join(Dir(), "config.json")
+}
+
+// Dir returns the active profile's directory, which holds its config,
+// state and upload cache
+func Dir() string {
+	return profileDir(ActiveProfile())
 }
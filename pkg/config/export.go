@@ -0,0 +1,153 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RedactedValue replaces secrets in exported configs. Imports skip it, so
+// a redacted export merges without clobbering the secrets already set.
+const RedactedValue = "REDACTED"
+
+// secretKeys are the config fields holding credentials
+var secretKeys = map[string]bool{
+	"consumer_secret": true,
+	"access_token":    true,
+	"access_secret":   true,
+	"client_secret":   true,
+	"app_password":    true,
+}
+
+// ExportFormats lists the formats configs can be exported and imported in
+var ExportFormats = []string{"json", "yaml"}
+
+// FormatForPath picks the import format from a file extension: yaml for
+// .yaml and .yml, json otherwise
+func FormatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	return "json"
+}
+
+// Export serializes the config as json or yaml, with secrets replaced by
+// RedactedValue unless includeSecrets is set
+func (c *Config) Export(format string, includeSecrets bool) ([]byte, error) {
+	fields, err := c.fields()
+	if err != nil {
+		return nil, err
+	}
+	if !includeSecrets {
+		redactSecrets(fields)
+	}
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(fields, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		return append(data, '\n'), nil
+	case "yaml":
+		data, err := yaml.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("invalid export format '%s'. Must be 'json' or 'yaml'", format)
+}
+
+// Import merges a json or yaml config, as written by Export, into c.
+// Settings in data replace the current ones; empty and redacted values
+// are skipped, and settings data doesn't mention are kept.
+func (c *Config) Import(data []byte, format string) error {
+	var imported map[string]interface{}
+	switch format {
+	case "json":
+		if err := json.Unmarshal(data, &imported); err != nil {
+			return fmt.Errorf("failed to parse config: %w", err)
+		}
+	case "yaml":
+		if err := yaml.Unmarshal(data, &imported); err != nil {
+			return fmt.Errorf("failed to parse config: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid import format '%s'. Must be 'json' or 'yaml'", format)
+	}
+
+	fields, err := c.fields()
+	if err != nil {
+		return err
+	}
+	mergeFields(fields, imported)
+
+	// Round-trip through JSON so the merged config is validated by the same
+	// struct tags Load uses
+	merged, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(merged, &cfg); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	*c = cfg
+	return nil
+}
+
+// fields returns the config as a generic map, using its JSON serialization
+func (c *Config) fields() (map[string]interface{}, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return fields, nil
+}
+
+// redactSecrets replaces every non-empty secret in fields, at any depth
+func redactSecrets(fields map[string]interface{}) {
+	for key, value := range fields {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			redactSecrets(v)
+		case string:
+			if secretKeys[key] && v != "" {
+				fields[key] = RedactedValue
+			}
+		}
+	}
+}
+
+// mergeFields copies src into dst, merging nested sections key by key
+func mergeFields(dst, src map[string]interface{}) {
+	for key, value := range src {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			existing, ok := dst[key].(map[string]interface{})
+			if !ok {
+				existing = map[string]interface{}{}
+				dst[key] = existing
+			}
+			mergeFields(existing, v)
+		case string:
+			if v == "" || v == RedactedValue {
+				continue
+			}
+			dst[key] = v
+		case nil:
+			continue
+		default:
+			dst[key] = v
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile using the original config.json
+const DefaultProfile = "default"
+
+// profileOverride is the profile chosen for this run with --config-profile
+var profileOverride string
+
+// profileNamePattern keeps profile names usable as directory names
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// baseDir returns imgup's config directory
+func baseDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "imgupv2")
+}
+
+// profileDir returns the directory holding a named profile's config and state
+func profileDir(name string) string {
+	if name == DefaultProfile {
+		return baseDir()
+	}
+	return filepath.Join(baseDir(), "profiles", name)
+}
+
+// activeProfilePath returns the file recording the profile in use
+func activeProfilePath() string {
+	return filepath.Join(baseDir(), "active_profile")
+}
+
+// ActiveProfile returns the profile this run uses: the --config-profile
+// override, else the one chosen with UseProfile, else DefaultProfile
+func ActiveProfile() string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+	data, err := os.ReadFile(activeProfilePath())
+	if err != nil {
+		return DefaultProfile
+	}
+	name := strings.TrimSpace(string(data))
+	if !profileNamePattern.MatchString(name) {
+		return DefaultProfile
+	}
+	return name
+}
+
+// SetProfile makes the rest of this run use a profile without switching to it
+func SetProfile(name string) error {
+	if err := checkProfile(name); err != nil {
+		return err
+	}
+	profileOverride = name
+	return nil
+}
+
+// UseProfile switches to a profile for future runs. With create, a new
+// profile starts out with an empty config.
+func UseProfile(name string, create bool) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name '%s'. Use letters, digits, '.', '-' and '_'", name)
+	}
+	if create {
+		if err := os.MkdirAll(profileDir(name), 0755); err != nil {
+			return fmt.Errorf("failed to create profile %s: %w", name, err)
+		}
+	} else if err := checkProfile(name); err != nil {
+		return err
+	}
+
+	if name == DefaultProfile {
+		if err := os.Remove(activeProfilePath()); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to switch profile: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(baseDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(activeProfilePath(), []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to switch profile: %w", err)
+	}
+	return nil
+}
+
+// Profiles lists the default profile and every profile created with UseProfile
+func Profiles() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(baseDir(), "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && profileNamePattern.MatchString(entry.Name()) && entry.Name() != DefaultProfile {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...), nil
+}
+
+// checkProfile reports an error unless the named profile exists
+func checkProfile(name string) error {
+	if name == DefaultProfile {
+		return nil
+	}
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name '%s'. Use letters, digits, '.', '-' and '_'", name)
+	}
+	if info, err := os.Stat(profileDir(name)); err != nil || !info.IsDir() {
+		return fmt.Errorf("no profile named '%s'. Create it with: imgup profile use --create %s", name, name)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// State remembers the most recently used options so they can act as
+// session defaults when neither a flag nor a config default is set
+type State struct {
+	Service    string            `json:"service,omitempty"`
+	Visibility string            `json:"visibility,omitempty"`
+	Albums     map[string]string `json:"albums,omitempty"` // last album per service
+}
+
+// LoadState loads the last-used state, returning an empty state if none exists
+func LoadState() (*State, error) {
+	data, err := os.ReadFile(statePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{Albums: map[string]string{}}, nil
+		}
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+	if state.Albums == nil {
+		state.Albums = map[string]string{}
+	}
+
+	return &state, nil
+}
+
+// Album returns the last album used with a service
+func (s *State) Album(service string) string {
+	return s.Albums[service]
+}
+
+// Remember records the options used in this invocation; empty values are ignored
+func (s *State) Remember(service, album, visibility string) {
+	if service != "" {
+		s.Service = service
+		if album != "" {
+			s.Albums[service] = album
+		}
+	}
+	if visibility != "" {
+		s.Visibility = visibility
+	}
+}
+
+// Save writes the state file
+func (s *State) Save() error {
+	path := statePath()
+
+	// Create directory if needed
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+
+	return nil
+}
+
+// statePath returns the last-used state file path
+func statePath() string {
+	return filepath.Join(filepath.Dir(Path()), "state.json")
+}
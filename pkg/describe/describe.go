@@ -0,0 +1,111 @@
+// Package describe generates alt text for images by asking an external
+// service, such as a locally hosted captioning model, to describe them.
+package describe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds how long a describer waits for a description
+const DefaultTimeout = 60 * time.Second
+
+// Describer produces alt text for an image
+type Describer interface {
+	Describe(ctx context.Context, imagePath string) (string, error)
+}
+
+// HTTPDescriber POSTs the image bytes to an endpoint and uses the response
+// as alt text. The response is either JSON with an "alt" (or "caption")
+// field, or a plain text description.
+type HTTPDescriber struct {
+	Endpoint string
+	Client   *http.Client
+}
+
+// NewHTTPDescriber creates a describer for the given endpoint URL
+func NewHTTPDescriber(endpoint string) *HTTPDescriber {
+	return &HTTPDescriber{
+		Endpoint: endpoint,
+		Client:   &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// Describe sends the image to the endpoint and returns its description
+func (d *HTTPDescriber) Describe(ctx context.Context, imagePath string) (string, error) {
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", d.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType(imagePath))
+	req.Header.Set("Accept", "application/json, text/plain")
+	req.Header.Set("X-Imgup-Filename", filepath.Base(imagePath))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("describe request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read description: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("describe endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	alt := parseDescription(resp.Header.Get("Content-Type"), body)
+	if alt == "" {
+		return "", fmt.Errorf("describe endpoint returned no description")
+	}
+	return alt, nil
+}
+
+// parseDescription pulls the alt text out of a JSON or plain text response
+func parseDescription(contentType string, body []byte) string {
+	if strings.Contains(contentType, "json") {
+		var result struct {
+			Alt     string `json:"alt"`
+			Caption string `json:"caption"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return ""
+		}
+		if result.Alt != "" {
+			return strings.TrimSpace(result.Alt)
+		}
+		return strings.TrimSpace(result.Caption)
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// contentType guesses the image MIME type from its extension
+func contentType(imagePath string) string {
+	switch strings.ToLower(filepath.Ext(imagePath)) {
+	case ".png":
+		return "image/png"
+	case ".gif":
+		return "image/gif"
+	case ".webp":
+		return "image/webp"
+	case ".heic", ".heif":
+		return "image/heic"
+	case ".tif", ".tiff":
+		return "image/tiff"
+	}
+	return "image/jpeg"
+}
@@ -0,0 +1,84 @@
+package duplicate
+
+import (
+	"context"
+	"crypto/md5"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// BatchItem is an image a batch upload has already finished
+type BatchItem struct {
+	BatchID     string
+	FileMD5     string
+	Service     string
+	RemoteID    string
+	RemoteURL   string
+	ImageURL    string
+	CompletedAt time.Time
+}
+
+// BatchID derives a batch id from the batch's JSON input, so running the
+// same input again resumes the same batch
+func BatchID(input []byte) string {
+	return fmt.Sprintf("%x", md5.Sum(input))
+}
+
+// RecordBatchItem marks an image in a batch as uploaded
+func (c *SQLiteCache) RecordBatchItem(item *BatchItem) error {
+	query := `
+		INSERT OR REPLACE INTO batch_progress
+		(batch_id, file_md5, service, remote_id, remote_url, image_url, completed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := c.db.Exec(
+		query,
+		item.BatchID,
+		item.FileMD5,
+		item.Service,
+		item.RemoteID,
+		item.RemoteURL,
+		item.ImageURL,
+		time.Now().Unix(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("record batch item: %w", err)
+	}
+
+	return nil
+}
+
+// BatchItem returns the recorded upload of a file in a batch, or nil if the
+// batch hasn't finished it
+func (c *SQLiteCache) BatchItem(ctx context.Context, batchID, md5Hash, service string) (*BatchItem, error) {
+	query := `
+		SELECT batch_id, file_md5, service, remote_id, remote_url, image_url, completed_at
+		FROM batch_progress
+		WHERE batch_id = ? AND file_md5 = ? AND service = ?
+	`
+
+	var item BatchItem
+	var completedAt int64
+	err := c.db.QueryRowContext(ctx, query, batchID, md5Hash, service).Scan(
+		&item.BatchID,
+		&item.FileMD5,
+		&item.Service,
+		&item.RemoteID,
+		&item.RemoteURL,
+		&item.ImageURL,
+		&completedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query batch item: %w", err)
+	}
+
+	item.CompletedAt = time.Unix(completedAt, 0)
+	return &item, nil
+}
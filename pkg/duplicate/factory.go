@@ -9,7 +9,7 @@ import (
 // SetupFlickrDuplicateChecker creates a duplicate checker for Flickr (local cache only)
 func SetupFlickrDuplicateChecker(cfg *config.FlickrConfig) (*RemoteChecker, error) {
 	// Create cache
-	cache, err := NewSQLiteCache(DefaultCachePath())
+	cache, err := OpenDefaultCache()
 	if err != nil {
 		return nil, fmt.Errorf("create cache: %w", err)
 	}
@@ -22,7 +22,7 @@ func SetupFlickrDuplicateChecker(cfg *config.FlickrConfig) (*RemoteChecker, erro
 // SetupSmugMugDuplicateChecker creates a duplicate checker for SmugMug (local cache only)
 func SetupSmugMugDuplicateChecker(cfg *config.SmugMugConfig) (*RemoteChecker, error) {
 	// Create cache
-	cache, err := NewSQLiteCache(DefaultCachePath())
+	cache, err := OpenDefaultCache()
 	if err != nil {
 		return nil, fmt.Errorf("create cache: %w", err)
 	}
@@ -3,12 +3,17 @@ package duplicate
 import (
 	"context"
 	"fmt"
+	"os"
 )
 
-// RemoteChecker implements duplicate checking with local cache only
+// RemoteChecker implements duplicate checking against the local cache,
+// optionally confirmed by a search on the service
 type RemoteChecker struct {
-	cache   *SQLiteCache
-	service string // current service name for cache entries
+	cache    *SQLiteCache
+	service  string         // current service name for cache entries
+	searcher RemoteSearcher // set when the remote search is preferred over the cache
+	prune    bool           // remove cache entries the service no longer has
+	pruned   []*Upload
 }
 
 // NewRemoteChecker creates a new checker with cache
@@ -19,7 +24,26 @@ func NewRemoteChecker(cache *SQLiteCache, service string) *RemoteChecker {
 	}
 }
 
-// Check looks for an existing upload in the local cache only
+// PreferRemote makes Check search the service and trust its answer over the
+// cache. Slower, but catches photos deleted from the service.
+func (r *RemoteChecker) PreferRemote(searcher RemoteSearcher) {
+	r.searcher = searcher
+}
+
+// PruneOnMiss makes Check remove a cache entry when the remote search finds
+// the photo is gone. Without it the entry is kept and the check reports no
+// duplicate.
+func (r *RemoteChecker) PruneOnMiss() {
+	r.prune = true
+}
+
+// Pruned returns the cache entries Check has removed
+func (r *RemoteChecker) Pruned() []*Upload {
+	return r.pruned
+}
+
+// Check looks for an existing upload of the file, to any service in the
+// cache, or to the checker's service when the remote search is preferred
 func (r *RemoteChecker) Check(ctx context.Context, filePath string) (*Upload, error) {
 	// Get file info including MD5
 	info, err := GetFileInfo(filePath)
@@ -27,14 +51,127 @@ func (r *RemoteChecker) Check(ctx context.Context, filePath string) (*Upload, er
 		return nil, fmt.Errorf("get file info: %w", err)
 	}
 
-	// Check local cache only (fast path)
+	// Check local cache (fast path)
 	upload, err := r.cache.Check(ctx, info.MD5)
 	if err != nil {
 		return nil, fmt.Errorf("cache check: %w", err)
 	}
 	
-	// Return result - nil means not found
-	return upload, nil
+	// Trust the cache unless the remote search is preferred. The search
+	// only covers this service, so an upload elsewhere stands.
+	if r.searcher == nil || (upload != nil && upload.Service != r.service) {
+		// Return result - nil means not found
+		return upload, nil
+	}
+	
+	remote, err := r.searcher.Search(ctx, info, upload)
+	if err != nil {
+		// Fall back to the cache when the service can't be searched
+		if os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Remote duplicate search failed, using cache: %v\n", err)
+		}
+		return upload, nil
+	}
+	
+	if remote == nil {
+		// The service doesn't have it; drop the stale cache entry if asked to
+		if upload != nil && r.prune {
+			if err := r.cache.Delete(info.MD5, r.service); err != nil {
+				return nil, fmt.Errorf("remove stale cache entry: %w", err)
+			}
+			r.pruned = append(r.pruned, upload)
+		}
+		return nil, nil
+	}
+	
+	// Keep the cache in step with what the service reported
+	if remote != upload {
+		remote.FileMD5 = info.MD5
+		remote.Service = r.service
+		remote.Filename = info.Filename
+		remote.FileSize = info.Size
+		if err := r.cache.Record(remote); err != nil {
+			return nil, fmt.Errorf("cache remote result: %w", err)
+		}
+	}
+	
+	return remote, nil
+}
+
+// CheckService looks the file up on the checker's service alone. Unlike
+// Check, a cached upload to another service doesn't stop the search, and the
+// cache isn't updated, since it keeps one upload per file.
+func (r *RemoteChecker) CheckService(ctx context.Context, filePath string) (*Upload, error) {
+	info, err := GetFileInfo(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("get file info: %w", err)
+	}
+
+	cached, err := r.cache.CheckService(ctx, info.MD5, r.service)
+	if err != nil {
+		return nil, fmt.Errorf("cache check: %w", err)
+	}
+	if r.searcher == nil {
+		return cached, nil
+	}
+
+	remote, err := r.searcher.Search(ctx, info, cached)
+	if err != nil {
+		if os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Remote duplicate search failed, using cache: %v\n", err)
+		}
+		return cached, nil
+	}
+	if remote != nil && remote != cached {
+		remote.FileMD5 = info.MD5
+		remote.Service = r.service
+		remote.Filename = info.Filename
+		remote.FileSize = info.Size
+	}
+	return remote, nil
+}
+
+// CheckAll returns every known upload of the file to the checker's service:
+// the cache entry first, then, when a remote search is set, every other copy
+// the service has. Like Check, it falls back to the cache if the search fails.
+func (r *RemoteChecker) CheckAll(ctx context.Context, filePath string) ([]*Upload, error) {
+	info, err := GetFileInfo(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("get file info: %w", err)
+	}
+
+	cached, err := r.cache.CheckService(ctx, info.MD5, r.service)
+	if err != nil {
+		return nil, fmt.Errorf("cache check: %w", err)
+	}
+
+	var matches []*Upload
+	if cached != nil {
+		matches = append(matches, cached)
+	}
+	if r.searcher == nil {
+		return matches, nil
+	}
+
+	remote, err := r.searcher.SearchAll(ctx, info)
+	if err != nil {
+		if os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Remote duplicate search failed, using cache: %v\n", err)
+		}
+		return matches, nil
+	}
+
+	for _, upload := range remote {
+		if cached != nil && upload.RemoteID == cached.RemoteID {
+			continue
+		}
+		upload.FileMD5 = info.MD5
+		upload.Service = r.service
+		upload.Filename = info.Filename
+		upload.FileSize = info.Size
+		matches = append(matches, upload)
+	}
+	return matches, nil
 }
 
 // Record saves an upload to the cache
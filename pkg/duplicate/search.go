@@ -0,0 +1,185 @@
+package duplicate
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/imgupv2/pkg/backends"
+	"github.com/pdxmph/imgupv2/pkg/config"
+)
+
+// RemoteSearcher looks a file up on the upload service itself
+type RemoteSearcher interface {
+	// Search returns the service's copy of the file, or nil if it isn't there.
+	// cached is the local cache entry for the file, if any.
+	Search(ctx context.Context, info *FileInfo, cached *Upload) (*Upload, error)
+
+	// SearchAll returns every copy of the file the service has
+	SearchAll(ctx context.Context, info *FileInfo) ([]*Upload, error)
+}
+
+// FlickrSearcher finds uploads on Flickr
+type FlickrSearcher struct {
+	api *backends.FlickrAPI
+}
+
+// NewFlickrSearcher creates a remote searcher for Flickr
+func NewFlickrSearcher(cfg *config.FlickrConfig) *FlickrSearcher {
+	return &FlickrSearcher{api: backends.NewFlickrAPI(cfg)}
+}
+
+// Search verifies a cached photo still exists, or looks for the file's
+// checksum machine tag when nothing is cached. Photos uploaded before the
+// tag existed are found by their filename title instead.
+func (s *FlickrSearcher) Search(ctx context.Context, info *FileInfo, cached *Upload) (*Upload, error) {
+	if cached != nil {
+		exists, err := s.api.PhotoExists(ctx, cached.RemoteID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return cached, nil
+		}
+		return nil, nil
+	}
+
+	resp, err := s.api.PhotosSearch(ctx, checksumSearch(info.MD5))
+	if err != nil {
+		return nil, err
+	}
+	photos := resp.Photos
+	if len(photos) == 0 {
+		if photos, err = s.titleSearch(ctx, info); err != nil {
+			return nil, err
+		}
+	}
+	if len(photos) == 0 {
+		return nil, nil
+	}
+
+	photo := photos[0]
+	return &Upload{
+		RemoteID:   photo.ID,
+		RemoteURL:  s.api.BuildPhotoURL(photo),
+		ImageURL:   s.api.BuildImageURL(photo, "b"),
+		UploadTime: time.Now(),
+	}, nil
+}
+
+// SearchAll finds every photo tagged with the file's checksum, or every
+// photo titled with its filename when none are tagged
+func (s *FlickrSearcher) SearchAll(ctx context.Context, info *FileInfo) ([]*Upload, error) {
+	params := checksumSearch(info.MD5)
+	params.PerPage = 100
+	resp, err := s.api.PhotosSearch(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	photos := resp.Photos
+	if len(photos) == 0 {
+		if photos, err = s.titleSearch(ctx, info); err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []*Upload
+	for _, photo := range photos {
+		matches = append(matches, &Upload{
+			RemoteID:   photo.ID,
+			RemoteURL:  s.api.BuildPhotoURL(photo),
+			ImageURL:   s.api.BuildImageURL(photo, "b"),
+			UploadTime: time.Now(),
+		})
+	}
+	return matches, nil
+}
+
+// titleSearch finds the user's photos titled with the file's name minus its
+// extension, which is how Flickr titles uploads without a title. It's a
+// weaker match than the checksum tag: an edited photo with the same name
+// matches too, and a retitled one doesn't.
+func (s *FlickrSearcher) titleSearch(ctx context.Context, info *FileInfo) ([]backends.PhotoSearchResult, error) {
+	title := strings.TrimSuffix(info.Filename, filepath.Ext(info.Filename))
+	resp, err := s.api.PhotosSearch(ctx, backends.PhotoSearchParams{UserID: "me", Text: title, PerPage: 100})
+	if err != nil {
+		return nil, err
+	}
+
+	var photos []backends.PhotoSearchResult
+	for _, photo := range resp.Photos {
+		if strings.EqualFold(photo.Title, title) {
+			photos = append(photos, photo)
+		}
+	}
+	return photos, nil
+}
+
+// checksumSearch builds a search for the user's photos tagged
+// with a file checksum
+func checksumSearch(md5Hash string) backends.PhotoSearchParams {
+	return backends.PhotoSearchParams{
+		UserID:      "me",
+		MachineTags: []string{backends.ChecksumTag(md5Hash)},
+		PerPage:     1,
+	}
+}
+
+// SmugMugSearcher finds uploads in the configured SmugMug album
+type SmugMugSearcher struct {
+	api      *backends.SmugMugAPI
+	albumKey string
+}
+
+// NewSmugMugSearcher creates a remote searcher for SmugMug
+func NewSmugMugSearcher(cfg *config.SmugMugConfig) *SmugMugSearcher {
+	return &SmugMugSearcher{api: backends.NewSmugMugAPI(cfg), albumKey: cfg.AlbumID}
+}
+
+// Search scans the album for an image with the same MD5 as the file
+func (s *SmugMugSearcher) Search(ctx context.Context, info *FileInfo, cached *Upload) (*Upload, error) {
+	images, err := s.api.GetAlbumImages(ctx, s.albumKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, img := range images {
+		if img.ArchivedMD5 != info.MD5 {
+			continue
+		}
+
+		// Keep the cached image URL if the cache points at this image
+		if cached != nil && cached.RemoteID == img.ImageKey {
+			return cached, nil
+		}
+		return &Upload{
+			RemoteID:   img.ImageKey,
+			RemoteURL:  img.WebURI,
+			UploadTime: time.Now(),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// SearchAll returns every image in the album with the same MD5 as the file
+func (s *SmugMugSearcher) SearchAll(ctx context.Context, info *FileInfo) ([]*Upload, error) {
+	images, err := s.api.GetAlbumImages(ctx, s.albumKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*Upload
+	for _, img := range images {
+		if img.ArchivedMD5 != info.MD5 {
+			continue
+		}
+		matches = append(matches, &Upload{
+			RemoteID:   img.ImageKey,
+			RemoteURL:  img.WebURI,
+			UploadTime: time.Now(),
+		})
+	}
+	return matches, nil
+}
@@ -0,0 +1,71 @@
+package duplicate
+
+import (
+	"database/sql"
+	"path/filepath"
+	"sync"
+)
+
+// sharedCache is a cache database opened once per process
+type sharedCache struct {
+	db   *sql.DB
+	refs int
+}
+
+var (
+	sharedMu     sync.Mutex
+	sharedCaches = map[string]*sharedCache{}
+)
+
+// OpenCache returns a handle on the process-wide cache at dbPath, opening
+// the database on first use. Handles share one connection, so uploads,
+// batch progress, the social queue and thumbnails in the same process queue
+// up instead of locking each other out. Each handle must be closed; the
+// database is closed with the last one.
+func OpenCache(dbPath string) (*SQLiteCache, error) {
+	key := dbPath
+	if abs, err := filepath.Abs(dbPath); err == nil {
+		key = abs
+	}
+
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	shared, ok := sharedCaches[key]
+	if !ok {
+		cache, err := NewSQLiteCache(dbPath)
+		if err != nil {
+			return nil, err
+		}
+		shared = &sharedCache{db: cache.db}
+		sharedCaches[key] = shared
+	}
+	shared.refs++
+
+	return &SQLiteCache{db: shared.db, release: func() error {
+		return releaseCache(key)
+	}}, nil
+}
+
+// OpenDefaultCache returns a handle on the shared cache at DefaultCachePath
+func OpenDefaultCache() (*SQLiteCache, error) {
+	return OpenCache(DefaultCachePath())
+}
+
+// releaseCache drops a reference to a shared cache, closing the database
+// when none are left
+func releaseCache(key string) error {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	shared, ok := sharedCaches[key]
+	if !ok {
+		return nil
+	}
+	shared.refs--
+	if shared.refs > 0 {
+		return nil
+	}
+	delete(sharedCaches, key)
+	return shared.db.Close()
+}
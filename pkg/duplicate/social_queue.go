@@ -0,0 +1,158 @@
+package duplicate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxSocialAttempts is how many times a queued social post is tried before it's given up on
+const MaxSocialAttempts = 5
+
+// SocialPost is a queued social post that failed after a successful upload
+type SocialPost struct {
+	ID            int64
+	Target        string // mastodon or bluesky
+	Service       string // flickr or smugmug
+	PhotoID       string
+	PhotoURL      string
+	Text          string // post text, without the photo URL
+	Alt           string
+	Tags          []string
+	Visibility    string
+	TagPrefix     string
+	Account       string        // named Mastodon or Bluesky account; empty for the main account
+	NoText        bool          // post only the image, as with --no-text
+	NoSocialURL   bool          // leave the photo URL out of the text, as with --no-social-url
+	PollOptions   []string      // Mastodon poll options; empty when the post has no poll
+	PollExpiresIn time.Duration // how long the poll stays open
+	Attempts      int
+	LastError     string
+	CreatedAt     time.Time
+}
+
+// QueueSocialPost saves a failed social post so it can be retried later
+func (c *SQLiteCache) QueueSocialPost(post *SocialPost) error {
+	query := `
+		INSERT INTO social_queue
+		(target, service, photo_id, photo_url, text, alt, tags, visibility, tag_prefix, account, no_text, no_social_url, poll_options, poll_expires_in, attempts, last_error, created_at, done)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0)
+	`
+
+	_, err := c.db.Exec(
+		query,
+		post.Target,
+		post.Service,
+		post.PhotoID,
+		post.PhotoURL,
+		post.Text,
+		post.Alt,
+		strings.Join(post.Tags, ","),
+		post.Visibility,
+		post.TagPrefix,
+		post.Account,
+		post.NoText,
+		post.NoSocialURL,
+		strings.Join(post.PollOptions, "|"),
+		int64(post.PollExpiresIn.Seconds()),
+		post.Attempts,
+		post.LastError,
+		time.Now().Unix(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("queue social post: %w", err)
+	}
+
+	return nil
+}
+
+// PendingSocialPosts returns queued social posts that aren't done or given
+// up on, oldest first
+func (c *SQLiteCache) PendingSocialPosts(ctx context.Context) ([]*SocialPost, error) {
+	query := `
+		SELECT id, target, service, photo_id, photo_url, text, alt, tags,
+		       visibility, tag_prefix, account, no_text, no_social_url, poll_options, poll_expires_in,
+		       attempts, last_error, created_at
+		FROM social_queue
+		WHERE done = 0 AND given_up = 0
+		ORDER BY created_at, id
+	`
+
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query social queue: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*SocialPost
+	for rows.Next() {
+		var post SocialPost
+		var tags, pollOptions string
+		var pollExpiresIn, createdAt int64
+
+		err := rows.Scan(
+			&post.ID,
+			&post.Target,
+			&post.Service,
+			&post.PhotoID,
+			&post.PhotoURL,
+			&post.Text,
+			&post.Alt,
+			&tags,
+			&post.Visibility,
+			&post.TagPrefix,
+			&post.Account,
+			&post.NoText,
+			&post.NoSocialURL,
+			&pollOptions,
+			&pollExpiresIn,
+			&post.Attempts,
+			&post.LastError,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+
+		if tags != "" {
+			post.Tags = strings.Split(tags, ",")
+		}
+		// Poll options can't contain |, since --poll splits on it
+		if pollOptions != "" {
+			post.PollOptions = strings.Split(pollOptions, "|")
+			post.PollExpiresIn = time.Duration(pollExpiresIn) * time.Second
+		}
+		post.CreatedAt = time.Unix(createdAt, 0)
+		posts = append(posts, &post)
+	}
+
+	return posts, rows.Err()
+}
+
+// MarkSocialPostDone marks a queued social post as posted
+func (c *SQLiteCache) MarkSocialPostDone(id int64) error {
+	if _, err := c.db.Exec(`UPDATE social_queue SET done = 1, attempts = attempts + 1 WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("mark social post done: %w", err)
+	}
+	return nil
+}
+
+// RecordSocialPostFailure counts a failed retry of a queued social post,
+// giving up on it after MaxSocialAttempts
+func (c *SQLiteCache) RecordSocialPostFailure(id int64, postErr error) error {
+	query := `UPDATE social_queue SET attempts = attempts + 1, last_error = ?, given_up = (attempts + 1 >= ?) WHERE id = ?`
+	if _, err := c.db.Exec(query, postErr.Error(), MaxSocialAttempts, id); err != nil {
+		return fmt.Errorf("record social post failure: %w", err)
+	}
+	return nil
+}
+
+// GiveUpSocialPost stops retrying a queued social post
+func (c *SQLiteCache) GiveUpSocialPost(id int64) error {
+	if _, err := c.db.Exec(`UPDATE social_queue SET given_up = 1 WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("give up on social post: %w", err)
+	}
+	return nil
+}
@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
 )
 
 // Upload represents a cached upload record
@@ -36,8 +39,16 @@ type Thumbnail struct {
 // SQLiteCache implements local duplicate checking via SQLite
 type SQLiteCache struct {
 	db *sql.DB
+
+	release   func() error // set on handles from OpenCache
+	closeOnce sync.Once
+	closeErr  error
 }
 
+// BusyTimeout is how long a cache write waits for another process, such as
+// the GUI saving thumbnails, to finish before failing with "database is locked"
+const BusyTimeout = 5 * time.Second
+
 // NewSQLiteCache creates a new SQLite-based cache
 func NewSQLiteCache(dbPath string) (*SQLiteCache, error) {
 	// Ensure directory exists
@@ -46,10 +57,15 @@ func NewSQLiteCache(dbPath string) (*SQLiteCache, error) {
 		return nil, fmt.Errorf("create cache directory: %w", err)
 	}
 
-	db, err := sql.Open("sqlite3", dbPath)
+	// WAL lets readers carry on while another process writes
+	dsn := fmt.Sprintf("%s?_journal_mode=WAL&_busy_timeout=%d", dbPath, BusyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
+	// SQLite allows one writer at a time; a single connection queues
+	// statements in the pool instead of failing with "database is locked"
+	db.SetMaxOpenConns(1)
 
 	cache := &SQLiteCache{db: db}
 	if err := cache.init(); err != nil {
@@ -85,25 +101,132 @@ func (c *SQLiteCache) init() error {
 		file_size INTEGER,
 		created_at INTEGER
 	);
+
+	CREATE TABLE IF NOT EXISTS social_queue (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		target TEXT NOT NULL,
+		service TEXT NOT NULL,
+		photo_id TEXT NOT NULL,
+		photo_url TEXT NOT NULL,
+		text TEXT,
+		alt TEXT,
+		tags TEXT,
+		visibility TEXT,
+		tag_prefix TEXT,
+		account TEXT NOT NULL DEFAULT '',
+		no_text INTEGER NOT NULL DEFAULT 0,
+		no_social_url INTEGER NOT NULL DEFAULT 0,
+		poll_options TEXT NOT NULL DEFAULT '',
+		poll_expires_in INTEGER NOT NULL DEFAULT 0,
+		attempts INTEGER DEFAULT 0,
+		last_error TEXT,
+		created_at INTEGER,
+		done INTEGER DEFAULT 0,
+		given_up INTEGER NOT NULL DEFAULT 0
+	);
+
+	CREATE TABLE IF NOT EXISTS batch_progress (
+		batch_id TEXT NOT NULL,
+		file_md5 TEXT NOT NULL,
+		service TEXT NOT NULL,
+		remote_id TEXT NOT NULL,
+		remote_url TEXT NOT NULL,
+		image_url TEXT,
+		completed_at INTEGER,
+		PRIMARY KEY (batch_id, file_md5, service)
+	);
 	`
 
-	_, err := c.db.Exec(schema)
-	return err
+	if _, err := c.db.Exec(schema); err != nil {
+		return err
+	}
+
+	if err := c.migrateSocialQueueColumns(); err != nil {
+		return fmt.Errorf("migrate social queue: %w", err)
+	}
+
+	return nil
+}
+
+// socialQueueColumns are the social_queue columns added after it was
+// created, in the order they were added
+var socialQueueColumns = []struct{ name, definition string }{
+	{"account", "TEXT NOT NULL DEFAULT ''"},
+	{"no_text", "INTEGER NOT NULL DEFAULT 0"},
+	{"no_social_url", "INTEGER NOT NULL DEFAULT 0"},
+	{"poll_options", "TEXT NOT NULL DEFAULT ''"},
+	{"poll_expires_in", "INTEGER NOT NULL DEFAULT 0"},
+	{"given_up", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+// migrateSocialQueueColumns adds the socialQueueColumns that social queues
+// from older versions are missing
+func (c *SQLiteCache) migrateSocialQueueColumns() error {
+	rows, err := c.db.Query(`PRAGMA table_info(social_queue)`)
+	if err != nil {
+		return err
+	}
+
+	existing := map[string]bool{}
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, column := range socialQueueColumns {
+		if existing[column.name] {
+			continue
+		}
+		if _, err := c.db.Exec(fmt.Sprintf(`ALTER TABLE social_queue ADD COLUMN %s %s`, column.name, column.definition)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// Check looks up a file by MD5 hash
+// Check looks up a file by MD5 hash. The cache keeps one upload per file,
+// to whichever service it went to last.
 func (c *SQLiteCache) Check(ctx context.Context, md5Hash string) (*Upload, error) {
 	query := `
 		SELECT file_md5, service, remote_id, remote_url, image_url, 
 		       upload_time, filename, file_size
 		FROM uploads
 		WHERE file_md5 = ?
+		ORDER BY upload_time DESC
+		LIMIT 1
 	`
 
+	return c.queryUpload(ctx, query, md5Hash)
+}
+
+// CheckService looks up a file by MD5 hash, if its cached upload went to service
+func (c *SQLiteCache) CheckService(ctx context.Context, md5Hash, service string) (*Upload, error) {
+	query := `
+		SELECT file_md5, service, remote_id, remote_url, image_url, 
+		       upload_time, filename, file_size
+		FROM uploads
+		WHERE file_md5 = ? AND service = ?
+	`
+
+	return c.queryUpload(ctx, query, md5Hash, service)
+}
+
+// queryUpload runs a query returning at most one upload
+func (c *SQLiteCache) queryUpload(ctx context.Context, query string, args ...interface{}) (*Upload, error) {
 	var upload Upload
 	var uploadTime int64
 
-	err := c.db.QueryRowContext(ctx, query, md5Hash).Scan(
+	err := c.db.QueryRowContext(ctx, query, args...).Scan(
 		&upload.FileMD5,
 		&upload.Service,
 		&upload.RemoteID,
@@ -152,6 +275,14 @@ func (c *SQLiteCache) Record(upload *Upload) error {
 	return nil
 }
 
+// Delete removes a file's cached upload to a service
+func (c *SQLiteCache) Delete(md5Hash, service string) error {
+	if _, err := c.db.Exec(`DELETE FROM uploads WHERE file_md5 = ? AND service = ?`, md5Hash, service); err != nil {
+		return fmt.Errorf("delete upload: %w", err)
+	}
+	return nil
+}
+
 // FindByRemoteID looks up an upload by service and remote ID
 func (c *SQLiteCache) FindByRemoteID(ctx context.Context, service, remoteID string) (*Upload, error) {
 	query := `
@@ -284,12 +415,22 @@ func (c *SQLiteCache) SaveThumbnail(thumb *Thumbnail) error {
 	return nil
 }
 
-// Close closes the database connection
+// Close closes the database connection. For handles from OpenCache it
+// releases the handle, and the database is closed with the last one.
+// Closing a handle more than once is harmless.
 func (c *SQLiteCache) Close() error {
-	return c.db.Close()
+	c.closeOnce.Do(func() {
+		if c.release != nil {
+			c.closeErr = c.release()
+		} else {
+			c.closeErr = c.db.Close()
+		}
+	})
+	return c.closeErr
 }
 
-// DefaultCachePath returns the default cache database path
+// DefaultCachePath returns the active profile's cache database path, so
+// each profile keeps its own uploads, social queue and batch progress
 func DefaultCachePath() string {
-	return filepath.Join(os.Getenv("HOME"), ".config", "imgupv2", "uploads.db")
+	return filepath.Join(config.Dir(), "uploads.db")
 }
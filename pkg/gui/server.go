@@ -195,7 +195,7 @@ func (s *Server) performUpload(ctx context.Context, session *Session, req *Uploa
 			})
 
 			// Embed metadata if needed
-			if hasMetadata := req.Metadata.Title != "" || req.Metadata.Description != "" || len(req.Metadata.Tags) > 0; hasMetadata && metadata.HasExiftool() {
+			if hasMetadata := req.Metadata.Title != "" || req.Metadata.Description != "" || len(req.Metadata.Tags) > 0; hasMetadata && s.config.EmbedMetadataInFiles() && metadata.HasExiftool() {
 				fmt.Fprintf(os.Stderr, "DEBUG: Embedding metadata - Title: %q, Desc: %q, Tags: %v\n", req.Metadata.Title, req.Metadata.Description, req.Metadata.Tags)
 				writer, err := metadata.NewWriter()
 				if err == nil {
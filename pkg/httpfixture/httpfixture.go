@@ -0,0 +1,283 @@
+// Package httpfixture records and replays HTTP interactions so upload flows
+// can be exercised against saved Flickr/SmugMug/Mastodon/Bluesky responses
+// instead of the live services.
+package httpfixture
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	// ModeRecord passes requests through and saves each interaction
+	ModeRecord = "record"
+	// ModeReplay answers requests from a saved cassette without touching the network
+	ModeReplay = "replay"
+)
+
+// Cassette is a saved sequence of HTTP interactions
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single request/response pair
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request identifies a recorded request
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// Response is a recorded response
+type Response struct {
+	Status     int               `json:"status"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	BodyBase64 string            `json:"body_base64,omitempty"` // used for non-UTF-8 bodies such as images
+}
+
+// volatileParams are query parameters that change per request or carry secrets;
+// they are dropped when recording and ignored when matching. "exp" is the
+// expiry time of a Bluesky service auth token.
+var volatileParams = []string{"api_key", "oauth_", "exp"}
+
+// Transport is an http.RoundTripper that records to or replays from a cassette
+type Transport struct {
+	mode string
+	path string
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New creates a transport for the cassette at path. In replay mode the
+// cassette must exist; in record mode it is (re)written by Save.
+func New(mode, path string, base http.RoundTripper) (*Transport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	t := &Transport{mode: mode, path: path, base: base}
+
+	switch mode {
+	case ModeRecord:
+		// Start with an empty cassette
+		t.cassette.Interactions = []Interaction{}
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &t.cassette); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette: %w", err)
+		}
+		t.used = make([]bool, len(t.cassette.Interactions))
+	default:
+		return nil, fmt.Errorf("unknown fixture mode '%s'. Must be '%s' or '%s'", mode, ModeRecord, ModeReplay)
+	}
+
+	return t, nil
+}
+
+// InstallFromEnv swaps http.DefaultTransport for a fixture transport when
+// IMGUP_HTTP_FIXTURE (cassette path) and IMGUP_HTTP_MODE (record/replay) are set.
+// Every client in imgup that doesn't set its own transport, including the
+// OAuth1 clients, goes through http.DefaultTransport. The returned function
+// saves the cassette in record mode and should be deferred by the caller.
+func InstallFromEnv() (func() error, error) {
+	path := os.Getenv("IMGUP_HTTP_FIXTURE")
+	if path == "" {
+		return func() error { return nil }, nil
+	}
+
+	mode := os.Getenv("IMGUP_HTTP_MODE")
+	if mode == "" {
+		mode = ModeReplay
+	}
+
+	t, err := New(mode, path, http.DefaultTransport)
+	if err != nil {
+		return nil, err
+	}
+	http.DefaultTransport = t
+
+	return t.Save, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == ModeReplay {
+		return t.replay(req)
+	}
+	return t.record(req)
+}
+
+// replay answers with the first unused interaction matching the request
+func (t *Transport) replay(req *http.Request) (*http.Response, error) {
+	key := matchKey(req.Method, req.URL)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, interaction := range t.cassette.Interactions {
+		if t.used[i] {
+			continue
+		}
+		recorded, err := url.Parse(interaction.Request.URL)
+		if err != nil {
+			continue
+		}
+		if matchKey(interaction.Request.Method, recorded) != key {
+			continue
+		}
+
+		t.used[i] = true
+		return interaction.Response.toHTTP(req)
+	}
+
+	return nil, fmt.Errorf("httpfixture: no recorded interaction for %s %s", req.Method, redactURL(req.URL))
+}
+
+// record performs the request and saves the interaction
+func (t *Transport) record(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	recorded := Response{
+		Status:  resp.StatusCode,
+		Headers: map[string]string{},
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		recorded.Headers["Content-Type"] = ct
+	}
+	if utf8.Valid(body) {
+		recorded.Body = string(body)
+	} else {
+		recorded.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+
+	t.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Request:  Request{Method: req.Method, URL: redactURL(req.URL)},
+		Response: recorded,
+	})
+	t.mu.Unlock()
+
+	return resp, nil
+}
+
+// Save writes the recorded cassette to disk (a no-op when replaying)
+func (t *Transport) Save() error {
+	if t.mode != ModeRecord {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.WriteFile(t.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// toHTTP builds an http.Response for a recorded response
+func (r Response) toHTTP(req *http.Request) (*http.Response, error) {
+	body := []byte(r.Body)
+	if r.BodyBase64 != "" {
+		decoded, err := base64.StdEncoding.DecodeString(r.BodyBase64)
+		if err != nil {
+			return nil, fmt.Errorf("httpfixture: invalid body_base64: %w", err)
+		}
+		body = decoded
+	}
+
+	header := http.Header{}
+	for k, v := range r.Headers {
+		header.Set(k, v)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// isVolatile reports whether a query parameter should be ignored
+func isVolatile(name string) bool {
+	for _, prefix := range volatileParams {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL returns the URL with volatile and secret query parameters removed
+func redactURL(u *url.URL) string {
+	clean := *u
+	query := u.Query()
+	for name := range query {
+		if isVolatile(name) {
+			query.Del(name)
+		}
+	}
+	clean.RawQuery = query.Encode()
+	return clean.String()
+}
+
+// matchKey normalizes a request into a comparable key: method, host, path
+// and the sorted non-volatile query parameters
+func matchKey(method string, u *url.URL) string {
+	query := u.Query()
+	var names []string
+	for name := range query {
+		if !isVolatile(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(strings.ToUpper(method) + " " + u.Host + u.Path)
+	for _, name := range names {
+		b.WriteString(" " + name + "=" + strings.Join(query[name], ","))
+	}
+	return b.String()
+}
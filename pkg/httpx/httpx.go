@@ -0,0 +1,35 @@
+// Package httpx sets up the HTTP transport shared by every imgup client.
+package httpx
+
+import "net/http"
+
+// UserAgent returns the User-Agent imgup sends by default
+func UserAgent(version string) string {
+	return "imgupv2/" + version
+}
+
+// Transport sets a User-Agent on requests that don't carry their own
+type Transport struct {
+	UserAgent string
+	Base      http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		// A RoundTripper mustn't change the caller's request
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.UserAgent)
+	}
+	return t.Base.RoundTrip(req)
+}
+
+// Install wraps http.DefaultTransport so every request made through it
+// identifies itself as userAgent. The Flickr, SmugMug, Mastodon and Bluesky
+// clients, OAuth included, all use http.DefaultTransport. The returned
+// Transport's UserAgent can be changed before the first request.
+func Install(userAgent string) *Transport {
+	transport := &Transport{UserAgent: userAgent, Base: http.DefaultTransport}
+	http.DefaultTransport = transport
+	return transport
+}
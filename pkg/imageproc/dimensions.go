@@ -0,0 +1,35 @@
+package imageproc
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"os"
+)
+
+// ErrUnknownDimensions is returned by LongestEdge for formats it can't read
+var ErrUnknownDimensions = errors.New("can't read image dimensions for this format")
+
+// LongestEdge returns the longer of an image's width and height, reading
+// only the image header. JPEG, PNG and GIF are supported; other formats
+// return ErrUnknownDimensions.
+func LongestEdge(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+	if errors.Is(err, image.ErrFormat) {
+		return 0, ErrUnknownDimensions
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read image dimensions: %w", err)
+	}
+
+	if cfg.Width > cfg.Height {
+		return cfg.Width, nil
+	}
+	return cfg.Height, nil
+}
@@ -0,0 +1,38 @@
+package imageproc
+
+import "fmt"
+
+// JPEG quality ranges from MinJPEGQuality (smallest files) to MaxJPEGQuality
+const (
+	MinJPEGQuality = 1
+	MaxJPEGQuality = 100
+)
+
+// DefaultJPEGQuality is used for images sent to the upload service:
+// transcodes and the GUI's HEIC conversion
+const DefaultJPEGQuality = 92
+
+// DefaultSocialJPEGQuality is used when an image is re-encoded to fit a
+// social network's size limit
+const DefaultSocialJPEGQuality = 85
+
+// ThumbnailJPEGQuality is used for terminal thumbnail previews
+const ThumbnailJPEGQuality = 80
+
+// ValidateJPEGQuality checks a quality is within range. Zero is allowed and
+// means "use the default".
+func ValidateJPEGQuality(quality int) error {
+	if quality == 0 || (quality >= MinJPEGQuality && quality <= MaxJPEGQuality) {
+		return nil
+	}
+	return fmt.Errorf("invalid JPEG quality %d. Must be between %d and %d", quality, MinJPEGQuality, MaxJPEGQuality)
+}
+
+// ResolveJPEGQuality returns quality, or fallback when quality is unset or
+// out of range
+func ResolveJPEGQuality(quality, fallback int) int {
+	if quality < MinJPEGQuality || quality > MaxJPEGQuality {
+		return fallback
+	}
+	return quality
+}
@@ -0,0 +1,147 @@
+// Package imageproc converts images before upload.
+package imageproc
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	// Import image format handlers
+	_ "image/gif"
+)
+
+// Formats lists the formats images can be transcoded to
+var Formats = []string{"jpeg", "png", "webp"}
+
+// ParseFormat normalizes a target format name, accepting "jpg" for jpeg
+func ParseFormat(format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "jpg" {
+		format = "jpeg"
+	}
+	for _, f := range Formats {
+		if f == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("invalid transcode format '%s'. Must be one of: %s", format, strings.Join(Formats, ", "))
+}
+
+// extension returns the file extension for a target format
+func extension(format string) string {
+	if format == "jpeg" {
+		return ".jpg"
+	}
+	return "." + format
+}
+
+// Transcode converts the image at path to format and returns the path of the
+// converted copy, which keeps the original base name. The caller must call
+// cleanup when done with it. JPEG, PNG and GIF sources are converted to JPEG
+// or PNG in Go; other sources (TIFF, HEIC, ...) and WebP output need
+// ImageMagick, or sips on macOS, or cwebp for WebP. quality applies to JPEG
+// and WebP output; zero means DefaultJPEGQuality.
+func Transcode(path, format string, quality int) (string, func(), error) {
+	format, err := ParseFormat(format)
+	if err != nil {
+		return "", nil, err
+	}
+	quality = ResolveJPEGQuality(quality, DefaultJPEGQuality)
+
+	dir, err := os.MkdirTemp("", "imgup-transcode-")
+	if err != nil {
+		return "", nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	out := filepath.Join(dir, base+extension(format))
+
+	if err := encodeNative(path, out, format, quality); err != nil {
+		if err := convertExternal(path, out, format, quality); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+
+	return out, cleanup, nil
+}
+
+// encodeNative converts with the standard library image packages
+func encodeNative(path, out, format string, quality int) error {
+	if format == "webp" {
+		return fmt.Errorf("webp encoding not supported natively")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return fmt.Errorf("decode image: %w", err)
+	}
+
+	dst, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	if format == "png" {
+		err = png.Encode(dst, img)
+	} else {
+		err = jpeg.Encode(dst, img, &jpeg.Options{Quality: quality})
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// convertExternal converts with the first available command line converter
+func convertExternal(path, out, format string, quality int) error {
+	type converter struct {
+		name string
+		args []string
+	}
+
+	q := fmt.Sprint(quality)
+	converters := []converter{
+		{"magick", []string{path, "-quality", q, out}},
+		{"convert", []string{path, "-quality", q, out}},
+	}
+	if runtime.GOOS == "darwin" && format != "webp" {
+		args := []string{"-s", "format", format}
+		if format == "jpeg" {
+			args = append(args, "-s", "formatOptions", q)
+		}
+		converters = append(converters, converter{"sips", append(args, path, "--out", out)})
+	}
+	if format == "webp" {
+		converters = append(converters, converter{"cwebp", []string{"-quiet", "-q", q, path, "-o", out}})
+	}
+
+	var tried []string
+	for _, conv := range converters {
+		bin, err := exec.LookPath(conv.name)
+		if err != nil {
+			tried = append(tried, conv.name)
+			continue
+		}
+		output, err := exec.Command(bin, conv.args...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%s failed to convert %s to %s: %v: %s", conv.name, filepath.Base(path), format, err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
+	return fmt.Errorf("can't convert %s to %s: install ImageMagick (tried %s)", filepath.Base(path), format, strings.Join(tried, ", "))
+}
@@ -0,0 +1,49 @@
+package imageproc
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrCorruptImage is wrapped by Validate's errors for files that can't be decoded
+var ErrCorruptImage = errors.New("image is corrupt or truncated")
+
+// validatedExtensions are the formats Validate decodes. RAW files, video and
+// formats without a Go decoder (HEIC, TIFF, WebP) are skipped.
+var validatedExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+}
+
+// CanValidate reports whether Validate checks files like path
+func CanValidate(path string) bool {
+	return validatedExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// Validate decodes the whole image to catch truncated or corrupt files
+// before they're uploaded. Formats CanValidate doesn't cover always pass.
+func Validate(path string) error {
+	if !CanValidate(path) {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, format, err := image.Decode(f); err != nil {
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+		}
+		return fmt.Errorf("%s: %w (%s decoder: %v)", filepath.Base(path), ErrCorruptImage, format, err)
+	}
+	return nil
+}
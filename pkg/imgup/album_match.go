@@ -0,0 +1,118 @@
+package imgup
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/imgupv2/pkg/backends"
+	"github.com/pdxmph/imgupv2/pkg/duplicate"
+)
+
+// AlbumMatchFields lists the accepted default.skip_existing_match values
+var AlbumMatchFields = []string{"title", "filename"}
+
+// ValidateAlbumMatchField checks a default.skip_existing_match value
+func ValidateAlbumMatchField(field string) error {
+	for _, f := range AlbumMatchFields {
+		if f == field {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid skip_existing_match '%s'. Must be one of: %s", field, strings.Join(AlbumMatchFields, ", "))
+}
+
+// findInAlbum looks in the upload's destination, the SmugMug album or Flickr
+// photoset albumID, or the Flickr photostream when no photoset is set, for a
+// photo with the same title or filename as the one being uploaded
+// (default.skip_existing_match). It
+// returns the match and a description of what matched, or nil when nothing
+// does or the upload has no title to compare.
+func (c *Client) findInAlbum(ctx context.Context, service, albumID string, req *UploadRequest) (*duplicate.Upload, string, error) {
+	field := c.cfg.Default.SkipExistingMatch
+	if field == "" {
+		field = "title"
+	}
+	if err := ValidateAlbumMatchField(field); err != nil {
+		return nil, "", err
+	}
+
+	value := req.Title
+	if field == "filename" {
+		value = filepath.Base(req.Path)
+		if req.RemoteFilename != "" {
+			value = filepath.Base(req.RemoteFilename)
+		}
+	}
+	if value == "" {
+		return nil, "", nil
+	}
+	note := fmt.Sprintf("a photo with the %s %q", field, value)
+
+	switch service {
+	case "flickr":
+		// Flickr doesn't keep filenames, but titles untitled uploads with
+		// the filename minus its extension
+		if field == "filename" {
+			value = strings.TrimSuffix(value, filepath.Ext(value))
+		}
+		api := backends.NewFlickrAPI(&c.cfg.Flickr)
+		photos, err := flickrAlbumPhotos(ctx, api, albumID, value)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, photo := range photos {
+			if strings.EqualFold(photo.Title, value) {
+				return &duplicate.Upload{
+					RemoteID:   photo.ID,
+					RemoteURL:  api.BuildPhotoURL(photo),
+					ImageURL:   api.BuildImageURL(photo, "b"),
+					UploadTime: time.Now(),
+				}, note, nil
+			}
+		}
+
+	case "smugmug":
+		images, err := backends.NewSmugMugAPI(&c.cfg.SmugMug).GetAlbumImages(ctx, albumID)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, img := range images {
+			existing := img.Title
+			if field == "filename" {
+				existing = img.FileName
+			}
+			if strings.EqualFold(existing, value) {
+				return &duplicate.Upload{
+					RemoteID:   img.ImageKey,
+					RemoteURL:  img.WebURI,
+					UploadTime: time.Now(),
+				}, note, nil
+			}
+		}
+	}
+
+	return nil, "", nil
+}
+
+// flickrAlbumPhotos returns the photos to compare with an upload: every
+// photo in photoset albumID, or else every photo in the photostream that
+// Flickr's text search finds for title
+func flickrAlbumPhotos(ctx context.Context, api *backends.FlickrAPI, albumID, title string) ([]backends.PhotoSearchResult, error) {
+	if albumID != "" {
+		return api.PhotosetPhotos(ctx, albumID)
+	}
+	var photos []backends.PhotoSearchResult
+	for page := 1; ; page++ {
+		resp, err := api.PhotosSearch(ctx, backends.PhotoSearchParams{UserID: "me", Text: title, PerPage: 500, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, resp.Photos...)
+		if page >= resp.Pages {
+			return photos, nil
+		}
+	}
+}
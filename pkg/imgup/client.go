@@ -0,0 +1,721 @@
+// Package imgup exposes imgup's upload orchestration (service selection,
+// duplicate detection, upload, cache recording and social posting) so it can
+// be embedded in other Go programs without shelling out to the CLI.
+package imgup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/imgupv2/pkg/backends"
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/describe"
+	"github.com/pdxmph/imgupv2/pkg/duplicate"
+	"github.com/pdxmph/imgupv2/pkg/imageproc"
+	"github.com/pdxmph/imgupv2/pkg/metadata"
+	"github.com/pdxmph/imgupv2/pkg/services/mastodon"
+)
+
+var (
+	// ErrNotAuthenticated is returned when no upload service is configured
+	ErrNotAuthenticated = errors.New("not authenticated. Run 'imgup auth flickr' or 'imgup auth smugmug' first")
+
+	// ErrAmbiguousService is returned when both services are configured and none was chosen
+	ErrAmbiguousService = errors.New("both Flickr and SmugMug are configured. Please specify a service or set a default")
+
+	// ErrAltRequired is returned when alt text is required and the image has none
+	ErrAltRequired = errors.New("alt text is required")
+)
+
+// Client runs uploads using a loaded imgup configuration
+type Client struct {
+	cfg       *config.Config
+	describer describe.Describer
+}
+
+// UploadRequest describes a single image upload
+type UploadRequest struct {
+	Path        string
+	Title       string
+	Description string
+	Alt         string   // generated by the configured describer when empty
+	Tags        []string
+	Private     bool
+	Service     string // flickr or smugmug; resolved from config when empty
+	Force       bool   // upload even if a duplicate is found
+	Replace     bool   // upload even if a duplicate is found, and untag the copies it supersedes
+	Transcode   string // convert to jpeg, png or webp before upload; duplicates still match the original
+	MinDimension int   // reject images whose longest edge is shorter than this many pixels
+	SkipValidation bool // upload without decoding the image first to catch corrupt files
+	AltRequired bool    // refuse to upload without alt text or a description to stand in for it
+	SkipExistingInAlbum bool // skip the upload when the destination has a photo with the same title or filename
+	Album       string // album (Flickr: photoset) name to upload to; defaults to the service's upload_album
+	DateTaken   string // Flickr date taken to set after upload (see ParseDateTaken), or DateTakenAuto for the EXIF date
+	RemoteFilename string // name the service stores the file under, e.g. the original name of a temporary export; defaults to Path's
+	VerifyUpload bool   // SmugMug: compare the stored original's MD5 with the uploaded file's, warning on a mismatch
+	CopyEXIFFrom string // image to copy copyright, artist and credit tags from into the uploaded file (see metadata.AttributionTags)
+
+	// Flickr-only options
+	SafetyLevel      string // safe, moderate, restricted
+	ContentType      string // photo, screenshot, other
+	HiddenFromSearch bool
+
+	// SmugMug-only options
+	SmugMugPrivacy string // public, unlisted, private
+
+	// Social posting, performed after a successful upload
+	Mastodon   bool
+	Bluesky    bool
+	Post       string // social post body; falls back to the title only with default.social_fallbacks
+	Visibility string // Mastodon visibility, defaults to public
+	TagPrefix  string // prefix for hashtags built from tags
+	NoSocialURL bool  // leave the photo page URL out of the post text; the image is still attached
+	NoText     bool   // post only the image: no text, photo URL or hashtags
+	BlueskyAccount string // named Bluesky account; empty for the main account
+	MastodonAccounts []string // named Mastodon accounts to post to, each in turn; empty for the main account
+	Poll       *mastodon.Poll // Mastodon poll, posted as a reply to the photo
+}
+
+// UploadResult is the outcome of an upload
+type UploadResult struct {
+	Service   string
+	PhotoID   string
+	URL       string // Photo page URL
+	ImageURL  string // Direct image URL for embedding
+	Duplicate bool   // true if an existing upload was reused
+	Warnings  []string
+	Social    []SocialResult
+}
+
+// New creates a client from an already loaded configuration
+func New(cfg *config.Config) *Client {
+	c := &Client{cfg: cfg}
+	if cfg.Describe.Endpoint != "" {
+		c.describer = describe.NewHTTPDescriber(cfg.Describe.Endpoint)
+	}
+	return c
+}
+
+// NewFromDefaultConfig loads the user's configuration and creates a client
+func NewFromDefaultConfig() (*Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return New(cfg), nil
+}
+
+// SetDescriber replaces the describer used to generate alt text for uploads
+// without any. A nil describer turns generation off.
+func (c *Client) SetDescriber(d describe.Describer) {
+	c.describer = d
+}
+
+// Config returns the configuration the client was created with
+func (c *Client) Config() *config.Config {
+	return c.cfg
+}
+
+// ResolveService picks the upload service: the requested one, the configured
+// default, or whichever single service is authenticated
+func (c *Client) ResolveService(requested string) (string, error) {
+	service := requested
+	if service == "" {
+		service = c.cfg.Default.Service
+	}
+
+	if service == "" {
+		hasFlickr := c.cfg.Flickr.AccessToken != "" && c.cfg.Flickr.AccessSecret != ""
+		hasSmugMug := c.cfg.SmugMug.AccessToken != "" && c.cfg.SmugMug.AccessSecret != ""
+
+		switch {
+		case hasFlickr && hasSmugMug:
+			return "", ErrAmbiguousService
+		case hasFlickr:
+			service = "flickr"
+		case hasSmugMug:
+			service = "smugmug"
+		default:
+			return "", ErrNotAuthenticated
+		}
+	}
+
+	if service != "flickr" && service != "smugmug" {
+		return "", fmt.Errorf("invalid service '%s'. Must be 'flickr' or 'smugmug'", service)
+	}
+
+	return service, nil
+}
+
+// CheckAuth verifies the service has the credentials needed to upload
+func (c *Client) CheckAuth(service string) error {
+	switch service {
+	case "flickr":
+		if c.cfg.Flickr.AccessToken == "" || c.cfg.Flickr.AccessSecret == "" {
+			return fmt.Errorf("not authenticated with Flickr. Run 'imgup auth flickr' first")
+		}
+	case "smugmug":
+		if c.cfg.SmugMug.AccessToken == "" || c.cfg.SmugMug.AccessSecret == "" {
+			return fmt.Errorf("not authenticated with SmugMug. Run 'imgup auth smugmug' first")
+		}
+		if c.cfg.SmugMug.AlbumID == "" && c.cfg.SmugMug.UploadAlbum == "" {
+			return fmt.Errorf("no SmugMug album selected. Run 'imgup auth smugmug' again")
+		}
+	default:
+		return fmt.Errorf("unsupported service: %s", service)
+	}
+	return nil
+}
+
+// CheckDuplicate looks up a previous upload of the same file to the service.
+// It returns nil when no duplicate is known. Stale cache entries removed by
+// default.prune_cache_on_miss are logged to stderr.
+func (c *Client) CheckDuplicate(ctx context.Context, service, imagePath string) (*duplicate.Upload, error) {
+	upload, pruned, err := c.checkDuplicate(ctx, service, imagePath)
+	for _, stale := range pruned {
+		fmt.Fprintf(os.Stderr, "%s\n", prunedMessage(stale))
+	}
+	return upload, err
+}
+
+// duplicateChecker sets up the cache checker and remote searcher for a service
+func (c *Client) duplicateChecker(service string) (*duplicate.RemoteChecker, duplicate.RemoteSearcher, error) {
+	var checker *duplicate.RemoteChecker
+	var searcher duplicate.RemoteSearcher
+	var err error
+
+	switch service {
+	case "flickr":
+		checker, err = duplicate.SetupFlickrDuplicateChecker(&c.cfg.Flickr)
+		searcher = duplicate.NewFlickrSearcher(&c.cfg.Flickr)
+	case "smugmug":
+		checker, err = duplicate.SetupSmugMugDuplicateChecker(&c.cfg.SmugMug)
+		searcher = duplicate.NewSmugMugSearcher(&c.cfg.SmugMug)
+	default:
+		return nil, nil, fmt.Errorf("unsupported service: %s", service)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to set up duplicate checker: %w", err)
+	}
+	return checker, searcher, nil
+}
+
+// CheckAllMatches returns every known upload of the same file to the
+// service: the cached one and any others a search on the service finds.
+// Several matches usually mean the file was uploaded more than once.
+func (c *Client) CheckAllMatches(ctx context.Context, service, imagePath string) ([]*duplicate.Upload, error) {
+	checker, searcher, err := c.duplicateChecker(service)
+	if err != nil {
+		return nil, err
+	}
+	defer checker.Close()
+
+	checker.PreferRemote(searcher)
+	return checker.CheckAll(ctx, imagePath)
+}
+
+// CheckService looks for the file on one service, searching it even when the
+// cache has the file's upload to another service
+func (c *Client) CheckService(ctx context.Context, service, imagePath string) (*duplicate.Upload, error) {
+	checker, searcher, err := c.duplicateChecker(service)
+	if err != nil {
+		return nil, err
+	}
+	defer checker.Close()
+
+	checker.PreferRemote(searcher)
+	return checker.CheckService(ctx, imagePath)
+}
+
+// prunedMessage describes a cache entry removed because its photo is gone
+func prunedMessage(stale *duplicate.Upload) string {
+	return fmt.Sprintf("Pruned stale cache entry: %s photo %s (%s) is no longer on the service", stale.Service, stale.RemoteID, stale.RemoteURL)
+}
+
+// checkDuplicate looks up a previous upload and returns any cache entries
+// pruned along the way
+func (c *Client) checkDuplicate(ctx context.Context, service, imagePath string) (*duplicate.Upload, []*duplicate.Upload, error) {
+	checker, searcher, err := c.duplicateChecker(service)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer checker.Close()
+
+	if c.cfg.PreferRemoteDuplicates() {
+		checker.PreferRemote(searcher)
+		if c.cfg.Default.PruneCacheOnMiss {
+			checker.PruneOnMiss()
+		}
+	}
+
+	upload, err := checker.Check(ctx, imagePath)
+	return upload, checker.Pruned(), err
+}
+
+// Upload uploads an image, reusing an existing upload when duplicate checking
+// is enabled, records it in the cache and optionally posts it to social media.
+// Social posting failures don't fail the upload; see UploadResult.Social.
+func (c *Client) Upload(ctx context.Context, req *UploadRequest) (*UploadResult, error) {
+	if _, err := os.Stat(req.Path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("file not found: %s", req.Path)
+	}
+
+	service, err := c.ResolveService(req.Service)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.CheckAuth(service); err != nil {
+		return nil, err
+	}
+	if req.Transcode != "" {
+		if _, err := imageproc.ParseFormat(req.Transcode); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.checkFormat(service, req); err != nil {
+		return nil, err
+	}
+	if req.DateTaken != "" {
+		if _, err := ParseDateTaken(req.DateTaken); err != nil {
+			return nil, err
+		}
+	}
+	if req.CopyEXIFFrom != "" {
+		if _, err := os.Stat(req.CopyEXIFFrom); err != nil {
+			return nil, fmt.Errorf("can't copy EXIF from %s: %w", req.CopyEXIFFrom, err)
+		}
+		if !metadata.HasExiftool() {
+			return nil, fmt.Errorf("copying EXIF from %s needs exiftool; install it or leave out --copy-exif-from", filepath.Base(req.CopyEXIFFrom))
+		}
+	}
+
+	if !req.SkipValidation {
+		if err := imageproc.Validate(req.Path); err != nil {
+			return nil, fmt.Errorf("%w. Re-export the file, or pass --validate=false to upload it anyway", err)
+		}
+	}
+
+	result := &UploadResult{
+		Service:  service,
+		Warnings: []string{},
+	}
+
+	if req.MinDimension > 0 {
+		warning, err := checkMinDimension(req.Path, req.MinDimension)
+		if err != nil {
+			return nil, err
+		}
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+
+	// Generate alt text when none was given; failures only leave it empty
+	if req.Alt == "" && c.describer != nil {
+		alt, err := c.describer.Describe(ctx, req.Path)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Couldn't generate alt text: %v", err))
+		} else {
+			req.Alt = alt
+		}
+	}
+	if req.AltRequired && req.Alt == "" && req.Description == "" {
+		return nil, fmt.Errorf("%w: %s has no alt text or description. Add --alt (or \"alt\" in batch JSON), or configure describe.endpoint to generate it", ErrAltRequired, filepath.Base(req.Path))
+	}
+
+	// Find the copies a replacement supersedes before the new one exists
+	var superseded []*duplicate.Upload
+	if req.Replace {
+		superseded, err = c.CheckAllMatches(ctx, service, req.Path)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to find the copies to replace: %v", err))
+		}
+	}
+
+	// Check for duplicates unless forced or disabled in config
+	if !req.Force && !req.Replace && c.cfg.IsDuplicateCheckEnabled() {
+		existing, pruned, err := c.checkDuplicate(ctx, service, req.Path)
+		for _, stale := range pruned {
+			result.Warnings = append(result.Warnings, prunedMessage(stale))
+		}
+		if err != nil {
+			// Continue with upload if duplicate check fails
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Duplicate check failed: %v", err))
+		} else if existing != nil {
+			result.Duplicate = true
+			result.PhotoID = existing.RemoteID
+			result.URL = existing.RemoteURL
+			result.ImageURL = existing.ImageURL
+		}
+	}
+
+	// Find the destination album before anything is uploaded, so a wrong
+	// name fails early
+	var albumID string
+	if !result.Duplicate {
+		albumID, err = c.destinationAlbum(ctx, service, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Skip a photo the destination already has under the same title or
+	// filename, even when the file itself changed
+	if !result.Duplicate && req.SkipExistingInAlbum && !req.Force && !req.Replace {
+		existing, note, err := c.findInAlbum(ctx, service, albumID, req)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Album check failed: %v", err))
+		} else if existing != nil {
+			result.Duplicate = true
+			result.PhotoID = existing.RemoteID
+			result.URL = existing.RemoteURL
+			result.ImageURL = existing.ImageURL
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Skipped upload: %s is already on %s", note, service))
+		}
+	}
+
+	if !result.Duplicate {
+		if err := c.upload(ctx, service, albumID, req, result); err != nil {
+			return nil, err
+		}
+	}
+	if req.Replace {
+		result.Warnings = append(result.Warnings, c.untagSuperseded(ctx, service, req.Path, superseded, result.PhotoID)...)
+	}
+
+	// The cache keeps the full URL; short links are only for output
+	result.URL = c.DisplayURL(service, result.PhotoID, result.URL)
+
+	if req.Mastodon || req.Bluesky {
+		result.Social = c.PostSocial(ctx, req, result)
+	}
+
+	return result, nil
+}
+
+// checkMinDimension returns an error if the image's longest edge is shorter
+// than min pixels. Formats whose size can't be read pass with a warning.
+func checkMinDimension(path string, min int) (string, error) {
+	edge, err := imageproc.LongestEdge(path)
+	if err == imageproc.ErrUnknownDimensions {
+		return fmt.Sprintf("Skipped minimum dimension check for %s: %v", filepath.Base(path), err), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if edge < min {
+		return "", fmt.Errorf("%s is too small: its longest edge is %dpx, below the minimum of %dpx (--min-dimension, default.min_dimension)", filepath.Base(path), edge, min)
+	}
+	return "", nil
+}
+
+// DisplayURL returns the photo page URL to show for a photo: a flic.kr
+// short link for Flickr when default.flickr_short_urls is on, else url
+func (c *Client) DisplayURL(service, photoID, url string) string {
+	if service != "flickr" || !c.cfg.Default.FlickrShortURLs {
+		return url
+	}
+	shortURL, err := backends.FlickrShortURL(photoID)
+	if err != nil {
+		return url
+	}
+	return shortURL
+}
+
+// heicServices lists the services that accept HEIC uploads as-is
+var heicServices = map[string]bool{
+	"smugmug": true,
+}
+
+// convertHEIC reports whether path is a HEIC file that should go to service
+// as JPEG: for services that don't accept HEIC, and for every service when
+// default.convert_heic is on
+func (c *Client) convertHEIC(service, path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".heic" && ext != ".heif" {
+		return false
+	}
+	return !heicServices[service] || c.cfg.ConvertHEICEverywhere()
+}
+
+// checkFormat rejects files the service won't accept in the format they'd
+// be uploaded in, after --transcode and HEIC conversion
+func (c *Client) checkFormat(service string, req *UploadRequest) error {
+	format := backends.FileFormat(req.Path)
+	if req.Transcode != "" {
+		format, _ = imageproc.ParseFormat(req.Transcode)
+	} else if c.convertHEIC(service, req.Path) {
+		format = "jpeg"
+	}
+	if backends.Supports(service, format) {
+		return nil
+	}
+	if req.Transcode != "" {
+		return fmt.Errorf("%s doesn't support %s files; use --transcode jpeg instead", service, strings.ToUpper(format))
+	}
+	return fmt.Errorf("%s doesn't support %s files; use --transcode jpeg to convert %s before upload", service, strings.ToUpper(format), filepath.Base(req.Path))
+}
+
+// upload sends the file to the service, adds it to albumID (see
+// destinationAlbum) and records it in the cache
+func (c *Client) upload(ctx context.Context, service, albumID string, req *UploadRequest, result *UploadResult) error {
+	// Calculate MD5 for the file (used for caching)
+	fileInfo, err := duplicate.GetFileInfo(req.Path)
+	if err != nil {
+		// Upload can still work without MD5
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to calculate file hash: %v", err))
+	}
+
+	// Upload a converted copy; the hash above stays that of the original
+	uploadPath := req.Path
+	transcode := req.Transcode
+	if transcode == "" && c.convertHEIC(service, req.Path) {
+		transcode = "jpeg"
+	}
+	if transcode != "" {
+		transcoded, cleanup, err := imageproc.Transcode(req.Path, transcode, c.cfg.Default.JPEGQuality)
+		if err != nil {
+			return fmt.Errorf("transcode failed: %w", err)
+		}
+		defer cleanup()
+		uploadPath = transcoded
+	}
+	// Copy attribution after transcoding, which may not keep the tags
+	if req.CopyEXIFFrom != "" {
+		tagged, cleanup, err := metadata.CopyWithTagsFrom(uploadPath, req.CopyEXIFFrom)
+		if err != nil {
+			return fmt.Errorf("copying EXIF from %s failed: %w", filepath.Base(req.CopyEXIFFrom), err)
+		}
+		defer cleanup()
+		uploadPath = tagged
+	}
+
+	switch service {
+	case "flickr":
+		if req.SafetyLevel != "" {
+			if err := backends.ValidateFlickrSafetyLevel(req.SafetyLevel); err != nil {
+				return err
+			}
+		}
+		if req.ContentType != "" {
+			if err := backends.ValidateFlickrContentType(req.ContentType); err != nil {
+				return err
+			}
+		}
+
+		uploader := backends.NewFlickrUploader(
+			c.cfg.Flickr.ConsumerKey,
+			c.cfg.Flickr.ConsumerSecret,
+			c.cfg.Flickr.AccessToken,
+			c.cfg.Flickr.AccessSecret,
+		)
+		uploader.SafetyLevel = req.SafetyLevel
+		uploader.ContentType = req.ContentType
+		uploader.HiddenFromSearch = req.HiddenFromSearch
+		uploader.Filename = req.RemoteFilename
+
+		// Tag the photo with the original's checksum, once, so it can be
+		// found again on Flickr
+		tags := req.Tags
+		if fileInfo != nil {
+			tags = backends.WithChecksumTag(req.Tags, fileInfo.MD5)
+		}
+
+		uploadResult, err := uploader.Upload(ctx, uploadPath, req.Title, req.Description, tags, req.Private)
+		if err != nil {
+			return uploadFailure(err)
+		}
+		result.PhotoID = uploadResult.PhotoID
+		result.URL = uploadResult.URL
+		result.ImageURL = uploadResult.ImageURL
+		result.Warnings = append(result.Warnings, uploadResult.Warnings...)
+
+		if albumID != "" {
+			if err := backends.NewFlickrAPI(&c.cfg.Flickr).AddToPhotoset(ctx, albumID, result.PhotoID); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to add the photo to album %q: %v", c.UploadAlbumName(service, req), err))
+			}
+		}
+		if req.DateTaken != "" {
+			if warning := c.setDateTaken(ctx, req, result.PhotoID); warning != "" {
+				result.Warnings = append(result.Warnings, warning)
+			}
+		}
+		if req.VerifyUpload {
+			result.Warnings = append(result.Warnings, "--verify-upload only applies to SmugMug; Flickr doesn't report a checksum of the stored file")
+		}
+
+	case "smugmug":
+		var privacyWarning string
+		if req.SmugMugPrivacy != "" {
+			if err := backends.ValidateSmugMugPrivacy(req.SmugMugPrivacy); err != nil {
+				return err
+			}
+			// The album decides who can see the image, so check it before
+			// uploading anything
+			var err error
+			if privacyWarning, err = c.checkSmugMugAlbumPrivacy(ctx, albumID, req.SmugMugPrivacy); err != nil {
+				return err
+			}
+		}
+
+		uploader := backends.NewSmugMugUploader(
+			c.cfg.SmugMug.ConsumerKey,
+			c.cfg.SmugMug.ConsumerSecret,
+			c.cfg.SmugMug.AccessToken,
+			c.cfg.SmugMug.AccessSecret,
+			albumID,
+		)
+		uploader.Privacy = req.SmugMugPrivacy
+		uploader.Filename = req.RemoteFilename
+
+		uploadResult, err := uploader.Upload(ctx, uploadPath, req.Title, req.Description, req.Tags, req.Private)
+		if err != nil {
+			return uploadFailure(err)
+		}
+		result.PhotoID = uploadResult.ImageKey
+		result.URL = uploadResult.URL
+		result.ImageURL = uploadResult.ImageURL
+
+		if req.DateTaken != "" {
+			result.Warnings = append(result.Warnings, "--date-taken only applies to Flickr; SmugMug keeps the date from the file's EXIF")
+		}
+		if req.SmugMugPrivacy != "" {
+			if err := backends.NewSmugMugAPI(&c.cfg.SmugMug).SetImagePrivacy(ctx, result.PhotoID, req.SmugMugPrivacy); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to set SmugMug privacy: %v", err))
+			}
+		}
+		if privacyWarning != "" {
+			result.Warnings = append(result.Warnings, privacyWarning)
+		}
+		if req.VerifyUpload {
+			if warning := c.verifySmugMugUpload(ctx, result.PhotoID, uploadPath); warning != "" {
+				result.Warnings = append(result.Warnings, warning)
+			}
+		}
+
+	default:
+		return fmt.Errorf("unsupported service: %s", service)
+	}
+
+	// Record successful upload in cache for future duplicate detection
+	if fileInfo != nil {
+		if err := c.recordUpload(service, req.Path, result, fileInfo); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to cache upload: %v", err))
+		}
+	}
+
+	return nil
+}
+
+// untagSuperseded removes the checksum machine tag from earlier Flickr
+// copies of a replaced upload, so duplicate searches only find the new
+// photo. The old photos themselves are left alone. It returns a message for
+// each copy.
+func (c *Client) untagSuperseded(ctx context.Context, service, path string, superseded []*duplicate.Upload, photoID string) []string {
+	if service != "flickr" {
+		if len(superseded) > 0 {
+			return []string{fmt.Sprintf("--replace only untags earlier copies on Flickr; %d earlier %s copies are unchanged", len(superseded), service)}
+		}
+		return nil
+	}
+
+	fileInfo, err := duplicate.GetFileInfo(path)
+	if err != nil {
+		return []string{fmt.Sprintf("Failed to untag replaced photos: %v", err)}
+	}
+	tag := backends.ChecksumTag(fileInfo.MD5)
+
+	api := backends.NewFlickrAPI(&c.cfg.Flickr)
+	var messages []string
+	for _, old := range superseded {
+		if old.RemoteID == photoID {
+			continue
+		}
+		removed, err := api.RemoveTag(ctx, old.RemoteID, tag)
+		switch {
+		case errors.Is(err, backends.ErrFlickrNotFound):
+			// Already deleted
+		case err != nil:
+			messages = append(messages, fmt.Sprintf("Failed to untag replaced photo %s: %v", old.RemoteID, err))
+		case removed:
+			messages = append(messages, fmt.Sprintf("Removed the checksum tag from replaced photo %s (%s)", old.RemoteID, old.RemoteURL))
+		}
+	}
+	return messages
+}
+
+// checkSmugMugAlbumPrivacy checks that the upload album can give an image
+// the privacy asked for, since SmugMug keeps privacy on the album (see
+// backends.SmugMugAlbumPrivacy). A private image in an album that isn't
+// private is an error, so it's never uploaded where others can see it; a
+// public image in an album that isn't public is only a warning.
+func (c *Client) checkSmugMugAlbumPrivacy(ctx context.Context, albumKey, privacy string) (string, error) {
+	want := backends.SmugMugAlbumPrivacy(privacy)
+	if want == "" {
+		return "", nil
+	}
+
+	album, err := backends.NewSmugMugAPI(&c.cfg.SmugMug).GetAlbum(ctx, albumKey)
+	if err != nil {
+		if want == "Private" {
+			return "", fmt.Errorf("couldn't check that the SmugMug album is private: %w", err)
+		}
+		return fmt.Sprintf("Couldn't check the SmugMug album's privacy: %v", err), nil
+	}
+	if album.Privacy == "" || album.Privacy == want {
+		return "", nil
+	}
+	if want == "Private" {
+		return "", fmt.Errorf("album %q is %s, so a private image in it could be seen by anyone with the link. Make the album private on SmugMug or upload to a private album with --album", album.Name, strings.ToLower(album.Privacy))
+	}
+	return fmt.Sprintf("The image is shown, but album %q is %s, so it's only as visible as the album", album.Name, strings.ToLower(album.Privacy)), nil
+}
+
+// verifySmugMugUpload compares the MD5 SmugMug stored for an image with the
+// file that was sent, and returns a warning when they differ or can't be
+// compared. A mismatch means SmugMug transcoded the file or it was corrupted.
+func (c *Client) verifySmugMugUpload(ctx context.Context, imageKey, path string) string {
+	localMD5, err := duplicate.CalculateFileMD5(path)
+	if err != nil {
+		return fmt.Sprintf("Couldn't verify the upload: %v", err)
+	}
+	remoteMD5, err := backends.NewSmugMugAPI(&c.cfg.SmugMug).ArchivedMD5(ctx, imageKey)
+	if err != nil {
+		return fmt.Sprintf("Couldn't verify the upload: %v", err)
+	}
+	if remoteMD5 == "" {
+		return "Couldn't verify the upload: SmugMug hasn't reported a checksum for it yet"
+	}
+	if !strings.EqualFold(remoteMD5, localMD5) {
+		return fmt.Sprintf("SmugMug's copy doesn't match the uploaded file (MD5 %s, expected %s); it may have been transcoded or corrupted", remoteMD5, localMD5)
+	}
+	if os.Getenv("IMGUP_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "DEBUG: SmugMug's copy of %s matches (MD5 %s)\n", imageKey, localMD5)
+	}
+	return ""
+}
+
+// recordUpload stores a successful upload in the duplicate cache
+func (c *Client) recordUpload(service, imagePath string, result *UploadResult, fileInfo *duplicate.FileInfo) error {
+	cache, err := duplicate.OpenDefaultCache()
+	if err != nil {
+		return err
+	}
+	defer cache.Close()
+
+	return cache.Record(&duplicate.Upload{
+		FileMD5:    fileInfo.MD5,
+		Service:    service,
+		RemoteID:   result.PhotoID,
+		RemoteURL:  result.URL,
+		ImageURL:   result.ImageURL,
+		UploadTime: time.Now(),
+		Filename:   filepath.Base(imagePath),
+		FileSize:   fileInfo.Size,
+	})
+}
@@ -0,0 +1,55 @@
+package imgup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/imgupv2/pkg/backends"
+	"github.com/pdxmph/imgupv2/pkg/metadata"
+)
+
+// DateTakenAuto as a date taken uses the image's EXIF DateTimeOriginal
+const DateTakenAuto = "auto"
+
+// dateTakenLayouts are the accepted --date-taken formats
+var dateTakenLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// ParseDateTaken parses a date taken such as "2019-07-04" or
+// "2019-07-04 18:30". DateTakenAuto parses to the zero time.
+func ParseDateTaken(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == DateTakenAuto {
+		return time.Time{}, nil
+	}
+	for _, layout := range dateTakenLayouts {
+		if taken, err := time.Parse(layout, value); err == nil {
+			return taken, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date taken '%s'. Use YYYY-MM-DD, YYYY-MM-DD HH:MM[:SS] or %s", value, DateTakenAuto)
+}
+
+// setDateTaken applies req.DateTaken to an uploaded Flickr photo and returns
+// a warning if it couldn't
+func (c *Client) setDateTaken(ctx context.Context, req *UploadRequest, photoID string) string {
+	taken, err := ParseDateTaken(req.DateTaken)
+	if err == nil && taken.IsZero() {
+		// Read from the original, since a transcoded copy may have lost its EXIF
+		taken, err = metadata.DateTaken(req.Path)
+	}
+	if err == nil {
+		err = backends.NewFlickrAPI(&c.cfg.Flickr).SetDates(ctx, photoID, taken)
+	}
+	if err != nil {
+		return fmt.Sprintf("Failed to set the date taken: %v", err)
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package imgup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pdxmph/imgupv2/pkg/backends"
+)
+
+// MetadataUpdate describes changes to the metadata of an existing upload.
+// Empty fields are left as they are; tags are added to the photo's own.
+type MetadataUpdate struct {
+	Title       string
+	Description string
+	Tags        []string
+}
+
+// Empty reports whether the update has nothing to change
+func (u MetadataUpdate) Empty() bool {
+	return u.Title == "" && u.Description == "" && len(u.Tags) == 0
+}
+
+// UpdateMetadata changes the title, description and tags of a photo resolved
+// with ResolvePhoto
+func (c *Client) UpdateMetadata(ctx context.Context, photo *UploadResult, update MetadataUpdate) error {
+	if err := c.CheckAuth(photo.Service); err != nil {
+		return err
+	}
+	if update.Empty() {
+		return nil
+	}
+
+	switch photo.Service {
+	case "flickr":
+		api := backends.NewFlickrAPI(&c.cfg.Flickr)
+		if update.Title != "" || update.Description != "" {
+			if err := api.SetMeta(ctx, photo.PhotoID, update.Title, update.Description); err != nil {
+				return fmt.Errorf("failed to update Flickr photo %s: %w", photo.PhotoID, err)
+			}
+		}
+		if err := api.AddTags(ctx, photo.PhotoID, update.Tags); err != nil {
+			return fmt.Errorf("failed to tag Flickr photo %s: %w", photo.PhotoID, err)
+		}
+		return nil
+
+	case "smugmug":
+		api := backends.NewSmugMugAPI(&c.cfg.SmugMug)
+		imageURI := "/api/v2/image/" + photo.PhotoID
+		var keywords []string
+		if len(update.Tags) > 0 {
+			// SmugMug replaces keywords, so merge with the image's current ones
+			image, err := api.GetImage(ctx, imageURI)
+			if err != nil {
+				return fmt.Errorf("failed to look up SmugMug image %s: %w", photo.PhotoID, err)
+			}
+			keywords = mergeKeywords(image.Keywords, update.Tags)
+		}
+		if err := api.UpdateImageMetadata(ctx, imageURI, update.Title, update.Description, keywords); err != nil {
+			return fmt.Errorf("failed to update SmugMug image %s: %w", photo.PhotoID, err)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported service: %s", photo.Service)
+	}
+}
+
+// mergeKeywords appends tags missing from a SmugMug semicolon-separated
+// keyword list to it
+func mergeKeywords(current string, tags []string) []string {
+	var keywords []string
+	seen := map[string]bool{}
+	add := func(keyword string) {
+		keyword = strings.TrimSpace(keyword)
+		if keyword == "" || seen[strings.ToLower(keyword)] {
+			return
+		}
+		seen[strings.ToLower(keyword)] = true
+		keywords = append(keywords, keyword)
+	}
+	for _, keyword := range strings.Split(current, ";") {
+		add(keyword)
+	}
+	for _, tag := range tags {
+		add(tag)
+	}
+	return keywords
+}
@@ -0,0 +1,94 @@
+package imgup
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pdxmph/imgupv2/pkg/backends"
+)
+
+var (
+	// flickrPhotoPath matches /photos/<user>/<id> in a Flickr photo page URL
+	flickrPhotoPath = regexp.MustCompile(`^/photos/[^/]+/(\d+)`)
+
+	// smugmugImageSegment matches the i-<key> segment of a SmugMug image URL
+	smugmugImageSegment = regexp.MustCompile(`^i-([A-Za-z0-9]+)$`)
+)
+
+// ResolvePhoto turns a photo page URL or photo ID of an existing upload into
+// an UploadResult that can be posted with PostSocial. The service is taken
+// from the URL when possible, otherwise from service (or the configured default).
+// flic.kr short links are decoded to their photo ID.
+func (c *Client) ResolvePhoto(ctx context.Context, service, ref string) (*UploadResult, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("no photo URL or ID given")
+	}
+
+	// A URL identifies both the service and the photo
+	if u, err := url.Parse(ref); err == nil && u.Host != "" {
+		host := strings.ToLower(u.Host)
+		switch {
+		case strings.HasSuffix(host, "flickr.com"):
+			m := flickrPhotoPath.FindStringSubmatch(u.Path)
+			if m == nil {
+				return nil, fmt.Errorf("not a Flickr photo URL: %s", ref)
+			}
+			return &UploadResult{Service: "flickr", PhotoID: m[1], URL: ref}, nil
+
+		case host == "flic.kr":
+			photoID, err := backends.FlickrShortURLPhotoID(u.Path)
+			if err != nil {
+				return nil, fmt.Errorf("not a Flickr photo URL: %s", ref)
+			}
+			return &UploadResult{Service: "flickr", PhotoID: photoID, URL: ref}, nil
+
+		case strings.HasSuffix(host, "smugmug.com"):
+			for _, segment := range strings.Split(u.Path, "/") {
+				if m := smugmugImageSegment.FindStringSubmatch(segment); m != nil {
+					return &UploadResult{Service: "smugmug", PhotoID: m[1], URL: ref}, nil
+				}
+			}
+			return nil, fmt.Errorf("not a SmugMug image URL: %s", ref)
+
+		default:
+			return nil, fmt.Errorf("unrecognized photo URL: %s", ref)
+		}
+	}
+
+	// A bare ID needs the service to look up its page URL
+	service, err := c.ResolveService(service)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.CheckAuth(service); err != nil {
+		return nil, err
+	}
+
+	result := &UploadResult{Service: service, PhotoID: ref}
+
+	switch service {
+	case "flickr":
+		info, err := backends.NewFlickrAPI(&c.cfg.Flickr).GetPhotoInfo(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up Flickr photo %s: %w", ref, err)
+		}
+		result.URL = info.URL
+
+	case "smugmug":
+		image, err := backends.NewSmugMugAPI(&c.cfg.SmugMug).GetImage(ctx, "/api/v2/image/"+ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up SmugMug image %s: %w", ref, err)
+		}
+		result.URL = image.WebURI
+	}
+
+	if result.URL == "" {
+		return nil, fmt.Errorf("no page URL found for photo %s", ref)
+	}
+
+	return result, nil
+}
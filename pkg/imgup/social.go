@@ -0,0 +1,497 @@
+package imgup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pdxmph/imgupv2/pkg/backends"
+	"github.com/pdxmph/imgupv2/pkg/duplicate"
+	"github.com/pdxmph/imgupv2/pkg/services/bluesky"
+	"github.com/pdxmph/imgupv2/pkg/services/mastodon"
+)
+
+// SocialResult is the outcome of posting an upload to one social service
+type SocialResult struct {
+	Target   string // mastodon or bluesky
+	Account  string // named account posted to; empty for the main account
+	Error    error
+	Warnings []string
+}
+
+// PostSocial posts an uploaded image to the social services selected in the request
+func (c *Client) PostSocial(ctx context.Context, req *UploadRequest, result *UploadResult) []SocialResult {
+	var results []SocialResult
+
+	if req.Mastodon {
+		results = append(results, c.PostToMastodon(ctx, req, result)...)
+	}
+	if req.Bluesky {
+		results = append(results, c.PostToBluesky(ctx, req, result))
+	}
+
+	// Keep failed posts so they can be retried with 'imgup retry-social'
+	for i := range results {
+		if results[i].Error != nil {
+			if err := c.QueueSocialRetry(req, result, results[i]); err != nil {
+				results[i].Warnings = append(results[i].Warnings, fmt.Sprintf("Failed to queue post for retry: %v", err))
+			}
+		}
+	}
+
+	return results
+}
+
+// QueueSocialRetry saves a failed social post to the retry queue in the cache
+func (c *Client) QueueSocialRetry(req *UploadRequest, result *UploadResult, social SocialResult) error {
+	// Without a photo there's nothing to retry
+	if result.PhotoID == "" || result.URL == "" {
+		return fmt.Errorf("no photo to post")
+	}
+
+	cache, err := duplicate.OpenDefaultCache()
+	if err != nil {
+		return err
+	}
+	defer cache.Close()
+
+	text := req.Post
+	if text == "" && c.cfg.Default.SocialFallbacks && !req.NoText {
+		text = req.Title
+	}
+
+	post := &duplicate.SocialPost{
+		Target:     social.Target,
+		Service:    result.Service,
+		PhotoID:    result.PhotoID,
+		PhotoURL:   result.URL,
+		Text:       text,
+		Alt:        c.SocialAltText(req),
+		Tags:       req.SocialTags(),
+		Visibility: req.Visibility,
+		TagPrefix:  req.TagPrefix,
+		Account:    social.Account,
+		NoText:     req.NoText,
+		NoSocialURL: req.NoSocialURL,
+	}
+	// Only Mastodon posts the poll
+	if req.Poll != nil && social.Target == "mastodon" {
+		post.PollOptions = req.Poll.Options
+		post.PollExpiresIn = req.Poll.ExpiresIn
+	}
+	if social.Error != nil {
+		post.LastError = social.Error.Error()
+	}
+
+	return cache.QueueSocialPost(post)
+}
+
+// RetrySocialPost posts a queued social post again and updates the queue with the outcome
+func (c *Client) RetrySocialPost(ctx context.Context, post *duplicate.SocialPost) SocialResult {
+	req := &UploadRequest{
+		Post:       post.Text,
+		Alt:        post.Alt,
+		Tags:       post.Tags,
+		Visibility: post.Visibility,
+		TagPrefix:  post.TagPrefix,
+		BlueskyAccount: post.Account,
+		NoText:     post.NoText,
+		NoSocialURL: post.NoSocialURL,
+	}
+	if len(post.PollOptions) > 0 {
+		req.Poll = &mastodon.Poll{Options: post.PollOptions, ExpiresIn: post.PollExpiresIn}
+	}
+	result := &UploadResult{
+		Service: post.Service,
+		PhotoID: post.PhotoID,
+		URL:     post.PhotoURL,
+	}
+	if os.Getenv("IMGUP_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "DEBUG: Retrying %s post with text %q\n", post.Target, c.StatusText(req, result.URL))
+		if req.Poll != nil {
+			fmt.Fprintf(os.Stderr, "DEBUG: Retrying poll %q open for %s\n", req.Poll.Options, req.Poll.ExpiresIn)
+		}
+	}
+
+	var social SocialResult
+	switch post.Target {
+	case "mastodon":
+		social = c.postToMastodonAccount(ctx, req, result, post.Account)
+	case "bluesky":
+		social = c.PostToBluesky(ctx, req, result)
+	default:
+		social = SocialResult{Target: post.Target, Error: fmt.Errorf("unknown social target: %s", post.Target)}
+	}
+
+	cache, err := duplicate.OpenDefaultCache()
+	if err != nil {
+		social.Warnings = append(social.Warnings, fmt.Sprintf("Failed to update retry queue: %v", err))
+		return social
+	}
+	defer cache.Close()
+
+	if social.Error != nil {
+		err = cache.RecordSocialPostFailure(post.ID, social.Error)
+	} else {
+		err = cache.MarkSocialPostDone(post.ID)
+	}
+	if err != nil {
+		social.Warnings = append(social.Warnings, fmt.Sprintf("Failed to update retry queue: %v", err))
+	}
+
+	return social
+}
+
+// CheckSocialFormat rejects an image the social service won't take, before
+// it's downloaded from the photo service
+func CheckSocialFormat(target, imageURL string) error {
+	format := backends.URLFormat(imageURL)
+	if backends.Supports(target, format) {
+		return nil
+	}
+	return fmt.Errorf("%s doesn't support %s images; upload a JPEG copy with --transcode jpeg to post it", target, strings.ToUpper(format))
+}
+
+// StatusText builds the post text: the post body, default.social_url_separator
+// and the photo URL. It only falls back to the title when
+// default.social_fallbacks is on.
+func (c *Client) StatusText(req *UploadRequest, photoURL string) string {
+	if req.NoText {
+		return ""
+	}
+	text := req.Post
+	if text == "" && c.cfg.Default.SocialFallbacks {
+		text = req.Title
+	}
+	if req.NoSocialURL {
+		return text
+	}
+	if text == "" {
+		return photoURL
+	}
+	return text + c.cfg.SocialURLSeparator() + photoURL
+}
+
+// SocialTags returns the tags to post as hashtags, none when the post has
+// no text
+func (req *UploadRequest) SocialTags() []string {
+	if req.NoText {
+		return nil
+	}
+	return req.Tags
+}
+
+// CheckNoText reports options that would put text in a NoText post
+func (req *UploadRequest) CheckNoText() error {
+	if !req.NoText {
+		return nil
+	}
+	if req.Post != "" {
+		return fmt.Errorf("--no-text posts only the image, so it can't be combined with post text")
+	}
+	if req.Poll != nil {
+		return fmt.Errorf("a Mastodon poll needs post text, so --poll can't be used with --no-text")
+	}
+	return nil
+}
+
+// SocialAltText returns the alt text for social media. It only falls back to
+// the photo caption when default.social_fallbacks is on.
+func (c *Client) SocialAltText(req *UploadRequest) string {
+	if req.Alt == "" && c.cfg.Default.SocialFallbacks {
+		return req.Description
+	}
+	return req.Alt
+}
+
+// fallbackWarnings points out social values left empty that older versions
+// filled in from the title or caption
+func (c *Client) fallbackWarnings(req *UploadRequest) []string {
+	if c.cfg.Default.SocialFallbacks {
+		return nil
+	}
+	var warnings []string
+	if req.Post == "" && req.Title != "" && !req.NoText {
+		if req.NoSocialURL {
+			warnings = append(warnings, "No post text given, so the post is just the image. Use --embed-text, or 'imgup config set default.social_fallbacks true' to use the title")
+		} else {
+			warnings = append(warnings, "No post text given, so the post is just the link. Use --embed-text, or 'imgup config set default.social_fallbacks true' to use the title")
+		}
+	}
+	if req.Alt == "" && req.Description != "" {
+		warnings = append(warnings, "No alt text given for the social post. Use --alt, or 'imgup config set default.social_fallbacks true' to use the caption")
+	}
+	return warnings
+}
+
+// PostToMastodon posts an uploaded image to each Mastodon account in the
+// request, or to the main account when none are named
+func (c *Client) PostToMastodon(ctx context.Context, req *UploadRequest, result *UploadResult) []SocialResult {
+	if len(req.MastodonAccounts) == 0 {
+		return []SocialResult{c.postToMastodonAccount(ctx, req, result, "")}
+	}
+	results := make([]SocialResult, 0, len(req.MastodonAccounts))
+	for _, name := range req.MastodonAccounts {
+		results = append(results, c.postToMastodonAccount(ctx, req, result, name))
+	}
+	return results
+}
+
+// postToMastodonAccount posts an uploaded image to one Mastodon account
+func (c *Client) postToMastodonAccount(ctx context.Context, req *UploadRequest, result *UploadResult, name string) SocialResult {
+	social := SocialResult{Target: "mastodon", Account: name}
+
+	// Check if Mastodon is configured
+	account, err := c.cfg.Mastodon.Account(name)
+	if err != nil {
+		social.Error = err
+		return social
+	}
+	if !account.Configured() {
+		if name != "" {
+			social.Error = fmt.Errorf("Mastodon account %s has no instance or access token. Set them with 'imgup config set mastodon.accounts.%s.instance ...' and '.access_token ...'", name, name)
+		} else {
+			social.Error = fmt.Errorf("not authenticated with Mastodon. Run 'imgup auth mastodon' first")
+		}
+		return social
+	}
+
+	// Validate we have required photo data
+	if result.PhotoID == "" {
+		social.Error = fmt.Errorf("cannot post to Mastodon: no photo ID available")
+		return social
+	}
+	if result.URL == "" {
+		social.Error = fmt.Errorf("cannot post to Mastodon: no photo URL available")
+		return social
+	}
+
+	client := mastodon.NewClient(
+		account.InstanceURL,
+		account.ClientID,
+		account.ClientSecret,
+		account.AccessToken,
+	)
+	client.TagPrefix = req.TagPrefix
+	client.HashtagStyle = c.cfg.Default.HashtagStyle
+	client.Poll = req.Poll
+
+	// Get a suitable image URL for Mastodon based on the service
+	imageURL, err := c.SocialImageURL(ctx, result.Service, result.PhotoID)
+	if err != nil {
+		social.Error = fmt.Errorf("failed to get image for social posting: %w", err)
+		return social
+	}
+
+	if err := CheckSocialFormat("mastodon", imageURL); err != nil {
+		social.Error = err
+		return social
+	}
+
+	// Upload the resized image from photo service to Mastodon
+	social.Warnings = append(social.Warnings, c.fallbackWarnings(req)...)
+	mediaID, err := client.UploadMediaFromURL(imageURL, c.SocialAltText(req))
+	if err != nil {
+		social.Error = fmt.Errorf("failed to upload media: %w", err)
+		return social
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = "public"
+	}
+
+	if err := client.PostStatus(c.StatusText(req, result.URL), []string{mediaID}, visibility, req.SocialTags()); err != nil {
+		social.Error = fmt.Errorf("failed to post status: %w", err)
+	}
+
+	return social
+}
+
+// PostToBluesky posts an uploaded image to Bluesky
+func (c *Client) PostToBluesky(ctx context.Context, req *UploadRequest, result *UploadResult) SocialResult {
+	social := SocialResult{Target: "bluesky", Account: req.BlueskyAccount}
+
+	// Check if Bluesky is configured
+	account, err := c.cfg.Bluesky.Account(req.BlueskyAccount)
+	if err != nil {
+		social.Error = err
+		return social
+	}
+	if !account.Configured() {
+		social.Error = fmt.Errorf("not authenticated with Bluesky. Run 'imgup auth bluesky' first")
+		return social
+	}
+
+	// Validate we have required photo data
+	if result.PhotoID == "" {
+		social.Error = fmt.Errorf("cannot post to Bluesky: no photo ID available")
+		return social
+	}
+	if result.URL == "" {
+		social.Error = fmt.Errorf("cannot post to Bluesky: no photo URL available")
+		return social
+	}
+
+	client := bluesky.NewClient(account.PDS, account.Handle, account.AppPassword)
+	client.TagPrefix = req.TagPrefix
+	client.HashtagStyle = c.cfg.Default.HashtagStyle
+	client.JPEGQuality = c.cfg.Default.SocialJPEGQuality
+	client.KeepEXIF = c.cfg.Default.SocialKeepEXIF
+
+	text := c.StatusText(req, result.URL)
+	social.Warnings = append(social.Warnings, c.fallbackWarnings(req)...)
+
+	// Check character limit (300 for Bluesky)
+	if len(text) > 300 {
+		social.Warnings = append(social.Warnings, fmt.Sprintf("Post text exceeds Bluesky's 300 character limit (%d chars). Truncating...", len(text)))
+		// Leave room for "..."
+		text = text[:297] + "..."
+	}
+
+	imageURL, err := c.SocialImageURL(ctx, result.Service, result.PhotoID)
+	if err != nil {
+		social.Error = fmt.Errorf("failed to get image for social posting: %w", err)
+		return social
+	}
+	if os.Getenv("IMGUP_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "DEBUG: Got image URL: %s\n", imageURL)
+	}
+
+	if err := CheckSocialFormat("bluesky", imageURL); err != nil {
+		social.Error = err
+		return social
+	}
+
+	altText := c.SocialAltText(req)
+
+	// Upload the image from the photo service to Bluesky
+	blob, _, err := client.UploadMediaFromURL(imageURL, altText)
+	if err != nil {
+		social.Error = fmt.Errorf("failed to upload media: %w", err)
+		return social
+	}
+	social.Warnings = append(social.Warnings, blob.Warnings...)
+
+	if err := client.PostStatus(text, []bluesky.BlobResponse{*blob}, []string{altText}, req.SocialTags()); err != nil {
+		social.Error = fmt.Errorf("failed to post status: %w", err)
+	}
+
+	return social
+}
+
+// SocialImageURL fetches an appropriate image URL for social media posting
+// from either Flickr or SmugMug using the photo ID
+func (c *Client) SocialImageURL(ctx context.Context, service string, photoID string) (string, error) {
+	if os.Getenv("IMGUP_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "DEBUG: SocialImageURL called with service=%s, photoID=%s\n", service, photoID)
+	}
+
+	switch service {
+	case "flickr":
+		// Get photo sizes from Flickr to find a good size for social media
+		api := backends.NewFlickrAPI(&c.cfg.Flickr)
+		sizes, err := api.GetPhotoSizes(ctx, photoID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get photo sizes from Flickr: %w", err)
+		}
+
+		// Find a good size for social media (prefer Large or Medium)
+		var imageURL string
+		for _, size := range sizes {
+			// Prioritize these sizes for social media
+			if size.Label == "Large" || size.Label == "Large 1024" {
+				imageURL = size.Source
+				break
+			} else if size.Label == "Medium" || size.Label == "Medium 800" {
+				imageURL = size.Source
+				// Keep looking for Large
+			}
+		}
+
+		// Fallback to whatever we have
+		if imageURL == "" && len(sizes) > 0 {
+			// Use a middle size if available
+			if len(sizes) > 2 {
+				imageURL = sizes[len(sizes)/2].Source
+			} else {
+				imageURL = sizes[0].Source
+			}
+		}
+
+		if imageURL == "" {
+			return "", fmt.Errorf("no suitable image size found from Flickr")
+		}
+
+		return imageURL, nil
+
+	case "smugmug":
+		// The photo ID from SmugMug is the image key; bare keys are looked up as images
+		api := backends.NewSmugMugAPI(&c.cfg.SmugMug)
+		imageURI := photoID
+		if !strings.Contains(imageURI, "/") {
+			imageURI = "/api/v2/image/" + imageURI
+		}
+
+		sizes, err := api.GetImageSizes(ctx, imageURI)
+		if err != nil {
+			return "", fmt.Errorf("failed to get image sizes from SmugMug (photo ID: %s): %w", photoID, err)
+		}
+
+		// SmugMug's response structure is complex, so we need to navigate it
+		if respData, ok := sizes["Response"].(map[string]interface{}); ok {
+			var imageURL string
+
+			if os.Getenv("IMGUP_DEBUG") != "" {
+				fmt.Fprintf(os.Stderr, "DEBUG: SmugMug response keys: %v\n", mapKeys(respData))
+			}
+
+			// Check for AlbumImage.Image.ArchivedUri (for large size)
+			if albumImage, ok := respData["AlbumImage"].(map[string]interface{}); ok {
+				if img, ok := albumImage["Image"].(map[string]interface{}); ok {
+					if archivedUri, ok := img["ArchivedUri"].(string); ok && archivedUri != "" {
+						imageURL = archivedUri
+					}
+
+					// If no ArchivedUri, try ImageDownloadUrl
+					if imageURL == "" {
+						if downloadUrl, ok := img["ImageDownloadUrl"].(string); ok && downloadUrl != "" {
+							imageURL = downloadUrl
+						}
+					}
+				}
+			}
+
+			// If still no URL, try the Image object directly
+			if imageURL == "" {
+				if img, ok := respData["Image"].(map[string]interface{}); ok {
+					if archivedUri, ok := img["ArchivedUri"].(string); ok && archivedUri != "" {
+						imageURL = archivedUri
+					}
+				}
+			}
+
+			if imageURL != "" {
+				if os.Getenv("IMGUP_DEBUG") != "" {
+					fmt.Fprintf(os.Stderr, "DEBUG: Found SmugMug image URL: %s\n", imageURL)
+				}
+				return imageURL, nil
+			}
+		}
+
+		return "", fmt.Errorf("could not extract image URL from SmugMug response - photo ID may be invalid or API response structure changed")
+
+	default:
+		return "", fmt.Errorf("unsupported service: %s", service)
+	}
+}
+
+// mapKeys returns the keys of a map (for debugging)
+func mapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,40 @@
+package imgup
+
+import (
+	"errors"
+	"net"
+
+	"github.com/pdxmph/imgupv2/pkg/backends"
+)
+
+// transferError marks an upload that failed in transit, e.g. on a network
+// error or a temporary outage, so trying again may succeed
+type transferError struct {
+	err error
+}
+
+func (e *transferError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transferError) Unwrap() error {
+	return e.err
+}
+
+// IsTransient reports whether err is an upload that failed in transit and
+// may succeed if tried again. Problems with the file, the request or the
+// credentials aren't transient.
+func IsTransient(err error) bool {
+	var transfer *transferError
+	return errors.As(err, &transfer)
+}
+
+// uploadFailure marks an upload error as transient when it's a network
+// error, a server error or rate limit, or a Flickr error that may go away
+func uploadFailure(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) || backends.IsRetryableStatusError(err) || backends.IsRetryableFlickrError(err) {
+		return &transferError{err: err}
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package imgup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pdxmph/imgupv2/pkg/backends"
+)
+
+// UploadAlbumName returns the album an upload goes to by name: req.Album,
+// else the service's upload_album setting
+func (c *Client) UploadAlbumName(service string, req *UploadRequest) string {
+	if req.Album != "" {
+		return req.Album
+	}
+	switch service {
+	case "flickr":
+		return c.cfg.Flickr.UploadAlbum
+	case "smugmug":
+		return c.cfg.SmugMug.UploadAlbum
+	}
+	return ""
+}
+
+// destinationAlbum resolves the album an upload goes to. For SmugMug it
+// returns an album key, falling back to the album chosen with 'imgup auth
+// smugmug'; for Flickr a photoset ID, or "" for the photostream only.
+func (c *Client) destinationAlbum(ctx context.Context, service string, req *UploadRequest) (string, error) {
+	name := c.UploadAlbumName(service, req)
+
+	switch service {
+	case "flickr":
+		if name == "" {
+			return "", nil
+		}
+		photosetID, err := backends.NewFlickrAPI(&c.cfg.Flickr).FindPhotoset(ctx, "", name)
+		if err != nil {
+			return "", fmt.Errorf("failed to find Flickr album: %w", err)
+		}
+		return photosetID, nil
+
+	case "smugmug":
+		if name == "" {
+			return c.cfg.SmugMug.AlbumID, nil
+		}
+		album, err := backends.NewSmugMugAPI(&c.cfg.SmugMug).FindAlbum(ctx, name)
+		if err != nil {
+			return "", fmt.Errorf("failed to find SmugMug album: %w", err)
+		}
+		return album.AlbumKey, nil
+	}
+	return "", fmt.Errorf("unsupported service: %s", service)
+}
@@ -29,8 +29,30 @@ func IsKittyTerminal() bool {
 	return false
 }
 
+// Multiplexer names the terminal multiplexer imgup is running in: "tmux",
+// "screen", or "" for none. Kitty graphics don't reach the terminal through
+// either unless tmux passes them through.
+func Multiplexer() string {
+	if os.Getenv("TMUX") != "" {
+		return "tmux"
+	}
+	if os.Getenv("STY") != "" {
+		return "screen"
+	}
+	return ""
+}
+
+// resetSequence closes an escape sequence a failed icat may have left open,
+// resets text attributes and clears the current line, so the failure doesn't
+// garble the text and images that follow
+const resetSequence = "\x1b\\\x1b[0m\r\x1b[2K"
+
 // ImageDisplay handles displaying images in Kitty terminal
 type ImageDisplay struct {
+	// Passthrough is passed to icat's --passthrough, e.g. "tmux"; empty
+	// leaves it to icat
+	Passthrough string
+	
 	// tempFiles tracks temporary files for cleanup
 	tempFiles []string
 }
@@ -68,6 +90,9 @@ func (d *ImageDisplay) DisplayImage(reader io.Reader, width, height int) error {
 	// Use kitten icat to display the image inline
 	// --align left ensures left alignment
 	args := []string{"icat", "--align", "left"}
+	if d.Passthrough != "" {
+		args = append(args, "--passthrough", d.Passthrough)
+	}
 	args = append(args, tmpFile.Name())
 	
 	cmd := exec.Command("kitten", args...)
@@ -76,6 +101,7 @@ func (d *ImageDisplay) DisplayImage(reader io.Reader, width, height int) error {
 	cmd.Stdin = os.Stdin
 	
 	if err := cmd.Run(); err != nil {
+		os.Stdout.WriteString(resetSequence)
 		return fmt.Errorf("kitten icat failed: %w", err)
 	}
 	
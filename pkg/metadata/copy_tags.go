@@ -0,0 +1,59 @@
+package metadata
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// AttributionTags are the tags CopyWithTagsFrom copies: copyright, artist
+// and credit, from each of the EXIF, IPTC and XMP groups that hold them
+var AttributionTags = []string{
+	"EXIF:Copyright",
+	"EXIF:Artist",
+	"IPTC:CopyrightNotice",
+	"IPTC:By-line",
+	"IPTC:Credit",
+	"XMP-dc:Rights",
+	"XMP-dc:Creator",
+	"XMP-photoshop:Credit",
+}
+
+// CopyWithTagsFrom returns the path of a copy of the image at imagePath,
+// keeping its base name, with AttributionTags copied from source. Tags
+// source doesn't have are left as they are. The caller must call cleanup
+// when done with the copy.
+func CopyWithTagsFrom(imagePath, source string) (string, func(), error) {
+	w, err := NewWriter()
+	if err != nil {
+		return "", nil, err
+	}
+
+	input, err := os.ReadFile(imagePath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read original: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "imgup-exif-")
+	if err != nil {
+		return "", nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	out := filepath.Join(dir, filepath.Base(imagePath))
+	if err := os.WriteFile(out, input, 0644); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	args := []string{"-overwrite_original", "-TagsFromFile", source}
+	for _, tag := range AttributionTags {
+		args = append(args, "-"+tag)
+	}
+	args = append(args, out)
+	if output, err := exec.Command(w.exiftoolPath, args...).CombinedOutput(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("exiftool failed: %w\nOutput: %s", err, output)
+	}
+	return out, cleanup, nil
+}
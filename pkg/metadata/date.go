@@ -0,0 +1,42 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// exifDateLayout is how DateTaken asks exiftool to print dates
+const exifDateLayout = "2006-01-02 15:04:05"
+
+// DateTaken returns the EXIF DateTimeOriginal of the image at path, in the
+// camera's local time
+func DateTaken(path string) (time.Time, error) {
+	w, err := NewWriter()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	output, err := exec.Command(w.exiftoolPath, "-json", "-d", "%Y-%m-%d %H:%M:%S", "-DateTimeOriginal", path).Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("exiftool failed: %w", err)
+	}
+
+	var results []struct {
+		DateTimeOriginal string `json:"DateTimeOriginal"`
+	}
+	if err := json.Unmarshal(output, &results); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse exiftool output: %w", err)
+	}
+	if len(results) == 0 || results[0].DateTimeOriginal == "" {
+		return time.Time{}, fmt.Errorf("%s has no EXIF date taken", filepath.Base(path))
+	}
+
+	taken, err := time.Parse(exifDateLayout, results[0].DateTimeOriginal)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unreadable EXIF date taken %q in %s", results[0].DateTimeOriginal, filepath.Base(path))
+	}
+	return taken, nil
+}
@@ -0,0 +1,41 @@
+package metadata
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// RightsTags are the copyright and artist tags kept on resized social copies.
+// Location tags are deliberately not among them.
+var RightsTags = []string{
+	"EXIF:Artist",
+	"EXIF:Copyright",
+	"XMP-dc:Creator",
+	"XMP-dc:Rights",
+	"IPTC:By-line",
+	"IPTC:CopyrightNotice",
+}
+
+// KeepTags strips all metadata from dst, then copies the given tags from src.
+// With no tags dst is left with no metadata at all.
+func KeepTags(src, dst string, tags []string) error {
+	w, err := NewWriter()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"-overwrite_original", "-all="}
+	if len(tags) > 0 {
+		args = append(args, "-tagsFromFile", src)
+		for _, tag := range tags {
+			args = append(args, "-"+tag)
+		}
+	}
+	args = append(args, dst)
+
+	output, err := exec.Command(w.exiftoolPath, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("exiftool failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
@@ -175,7 +175,9 @@ func ExtractMetadata(imagePath string) (title, description string, keywords []st
 	}
 	
 	if exiftoolPath == "" {
-		fmt.Fprintf(os.Stderr, "DEBUG ExtractMetadata: exiftool not found\n")
+		if os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG ExtractMetadata: exiftool not found\n")
+		}
 		return "", "", nil, nil
 	}
 	
@@ -186,7 +188,9 @@ func ExtractMetadata(imagePath string) (title, description string, keywords []st
 		return "", "", nil, fmt.Errorf("failed to extract metadata: %w", err)
 	}
 	
-	fmt.Fprintf(os.Stderr, "DEBUG ExtractMetadata: Using %s, output length: %d\n", exiftoolPath, len(output))
+	if os.Getenv("IMGUP_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "DEBUG ExtractMetadata: Using %s, output length: %d\n", exiftoolPath, len(output))
+	}
 	
 	// Parse JSON output
 	var results []map[string]interface{}
@@ -204,8 +208,9 @@ func ExtractMetadata(imagePath string) (title, description string, keywords []st
 }
 
 func extractFromResult(result map[string]interface{}) (title, description string, keywords []string, err error) {
-	// Debug: print what we got
-	fmt.Fprintf(os.Stderr, "DEBUG ExtractMetadata: Got %d fields\n", len(result))
+	if os.Getenv("IMGUP_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "DEBUG ExtractMetadata: Got %d fields\n", len(result))
+	}
 	
 	// Extract title (try multiple fields)
 	if val, ok := result["Title"]; ok && val != nil {
@@ -251,7 +256,9 @@ func extractFromResult(result map[string]interface{}) (title, description string
 		keywords = append(keywords, k)
 	}
 	
-	fmt.Fprintf(os.Stderr, "DEBUG ExtractMetadata: Final - Title: %q, Desc: %q, Tags: %v\n", title, description, keywords)
+	if os.Getenv("IMGUP_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "DEBUG ExtractMetadata: Final - Title: %q, Desc: %q, Tags: %v\n", title, description, keywords)
+	}
 	
 	return title, description, keywords, nil
 }
@@ -36,7 +36,7 @@ func NewCallbackServer(port int, path string) *CallbackServer {
 // Start starts the callback server
 func (s *CallbackServer) Start(ctx context.Context) error {
 	var err error
-	s.listener, err = net.Listen("tcp", fmt.Sprintf(":%d", s.Port))
+	s.listener, err = net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", s.Port))
 	if err != nil {
 		return fmt.Errorf("failed to start callback server: %w", err)
 	}
@@ -0,0 +1,142 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// CodeServer receives an OAuth 2.0 authorization code. It listens on the
+// loopback interface only and rejects callbacks whose state doesn't match
+// the one sent with the authorize URL.
+type CodeServer struct {
+	Port     int
+	Path     string
+	state    string
+	listener net.Listener
+	result   chan codeResult
+}
+
+type codeResult struct {
+	code string
+	err  error
+}
+
+// NewCodeServer creates a code server with a random state
+func NewCodeServer(port int, path string) (*CodeServer, error) {
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+	return &CodeServer{
+		Port:   port,
+		Path:   path,
+		state:  state,
+		result: make(chan codeResult, 1),
+	}, nil
+}
+
+// randomState returns an unguessable value for the state parameter
+func randomState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate OAuth state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// State returns the value to send as the authorize URL's state parameter
+func (s *CodeServer) State() string {
+	return s.state
+}
+
+// URL returns the callback URL
+func (s *CodeServer) URL() string {
+	return fmt.Sprintf("http://localhost:%d%s", s.Port, s.Path)
+}
+
+// Start starts listening on 127.0.0.1 until ctx is done
+func (s *CodeServer) Start(ctx context.Context) error {
+	var err error
+	s.listener, err = net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", s.Port))
+	if err != nil {
+		return fmt.Errorf("failed to start callback server: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.Path, s.handleCallback)
+
+	server := &http.Server{Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	go func() {
+		if err := server.Serve(s.listener); err != nil && err != http.ErrServerClosed {
+			s.send(codeResult{err: err})
+		}
+	}()
+
+	return nil
+}
+
+// Wait returns the authorization code, or an error for a rejected callback
+// or timeout
+func (s *CodeServer) Wait(timeout time.Duration) (string, error) {
+	select {
+	case result := <-s.result:
+		return result.code, result.err
+	case <-time.After(timeout):
+		return "", fmt.Errorf("authorization timeout")
+	}
+}
+
+// send delivers the first result; later callbacks are dropped
+func (s *CodeServer) send(result codeResult) {
+	select {
+	case s.result <- result:
+	default:
+	}
+}
+
+// handleCallback checks the state and passes on the code. The response is
+// written first, since the server may be closed as soon as the code arrives.
+func (s *CodeServer) handleCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	state := query.Get("state")
+	if subtle.ConstantTimeCompare([]byte(state), []byte(s.state)) != 1 {
+		http.Error(w, "Error: This callback doesn't match the authorization request. Run the authorization again.", http.StatusBadRequest)
+		s.send(codeResult{err: fmt.Errorf("callback state didn't match the authorization request")})
+		return
+	}
+
+	if denied := query.Get("error"); denied != "" {
+		if description := query.Get("error_description"); description != "" {
+			denied = description
+		}
+		fmt.Fprintf(w, "Authorization was not granted. You can close this window.")
+		s.send(codeResult{err: fmt.Errorf("authorization denied: %s", denied)})
+		return
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		http.Error(w, "Error: No authorization code received", http.StatusBadRequest)
+		s.send(codeResult{err: fmt.Errorf("no authorization code received")})
+		return
+	}
+
+	fmt.Fprintf(w, "Authorization successful! You can close this window and return to the terminal.")
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	s.send(codeResult{code: code})
+}
@@ -0,0 +1,60 @@
+// Package render turns upload results into output snippets. The CLI, the
+// GUI and watch all render through it, so a format looks the same whichever
+// way the image was uploaded.
+package render
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pdxmph/imgupv2/pkg/config"
+	"github.com/pdxmph/imgupv2/pkg/templates"
+	"github.com/pdxmph/imgupv2/pkg/textutil"
+)
+
+// ErrUnknownFormat is returned for formats with no configured template
+var ErrUnknownFormat = errors.New("unknown format")
+
+// Template returns the template text for a format, read from disk when the
+// configured value is an @file reference
+func Template(cfg *config.Config, format string) (string, error) {
+	value, exists := cfg.Templates[format]
+	if !exists {
+		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+	template, err := templates.Source(value)
+	if err != nil {
+		return "", fmt.Errorf("template %s: %w", format, err)
+	}
+	return template, nil
+}
+
+// Snippet renders vars with the template configured for format
+func Snippet(format string, vars templates.Variables, cfg *config.Config) (string, error) {
+	template, err := Template(cfg, format)
+	if err != nil {
+		return "", err
+	}
+	return templates.Process(template, vars), nil
+}
+
+// Filename returns the %filename% value for an image: its base name without
+// the extension, sanitized with textutil.SanitizeFilename when asked
+func Filename(path string, sanitize bool) string {
+	filename := filepath.Base(path)
+	if sanitize {
+		filename = textutil.SanitizeFilename(filename)
+	}
+	return strings.TrimSuffix(filename, filepath.Ext(filename))
+}
+
+// EditURL returns the %edit_url% value for a photo. Only Flickr has an edit
+// page that can be linked to.
+func EditURL(service, photoID string) string {
+	if service != "flickr" || photoID == "" {
+		return ""
+	}
+	return "https://www.flickr.com/photos/upload/edit/?ids=" + photoID
+}
@@ -6,14 +6,23 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/pdxmph/imgupv2/pkg/imageproc"
+	"github.com/pdxmph/imgupv2/pkg/metadata"
+	"github.com/pdxmph/imgupv2/pkg/services/hashtags"
 )
 
+// MaxBlobSize is the largest image Bluesky accepts, in bytes
+const MaxBlobSize = 1000000
+
 // Client represents a Bluesky API client
 type Client struct {
 	PDS         string // Personal Data Server URL (e.g., https://bsky.social)
@@ -22,6 +31,12 @@ type Client struct {
 	DID         string // Decentralized Identifier
 	AccessJWT   string
 	RefreshJWT  string
+	TagPrefix   string // Prefix applied to hashtags built from tags
+	HashtagStyle string // hashtags.AsIs (default), Camel or Lower
+	JPEGQuality int    // Quality for images re-encoded to fit the blob size limit; 0 uses imageproc.DefaultSocialJPEGQuality
+	KeepEXIF    bool   // Keep copyright and artist tags (never GPS) on re-encoded images
+
+	serviceEndpoint string // Actual PDS from the DID document, used for service auth
 }
 
 // Session represents the response from createSession
@@ -30,6 +45,13 @@ type Session struct {
 	RefreshJwt string `json:"refreshJwt"`
 	Handle     string `json:"handle"`
 	DID        string `json:"did"`
+	DidDoc     struct {
+		Service []struct {
+			ID              string `json:"id"`
+			Type            string `json:"type"`
+			ServiceEndpoint string `json:"serviceEndpoint"`
+		} `json:"service"`
+	} `json:"didDoc"`
 }
 
 // BlobResponse represents the response from uploadBlob
@@ -40,6 +62,7 @@ type BlobResponse struct {
 		MimeType string `json:"mimeType"`
 		Size     int    `json:"size"`
 	} `json:"blob"`
+	Warnings []string `json:"-"` // problems with an image re-encoded to fit, for the caller to report
 }
 
 // BlobRef represents a blob reference
@@ -52,7 +75,7 @@ type PostRecord struct {
 	Type      string    `json:"$type"`
 	Text      string    `json:"text"`
 	CreatedAt string    `json:"createdAt"`
-	Embed     *Embed    `json:"embed,omitempty"`
+	Embed     interface{} `json:"embed,omitempty"` // *Embed or *VideoEmbed
 	Facets    []Facet   `json:"facets,omitempty"`
 }
 
@@ -149,6 +172,13 @@ func (c *Client) Authenticate() error {
 	c.RefreshJWT = session.RefreshJwt
 	c.DID = session.DID
 	
+	// Remember the account's PDS for service auth (video uploads)
+	for _, svc := range session.DidDoc.Service {
+		if svc.Type == "AtprotoPersonalDataServer" {
+			c.serviceEndpoint = strings.TrimRight(svc.ServiceEndpoint, "/")
+		}
+	}
+	
 	return nil
 }
 
@@ -253,13 +283,7 @@ func (c *Client) PostStatus(text string, mediaBlobs []BlobResponse, altTexts []s
 	}
 	
 	// Convert tags to hashtags
-	for _, tag := range tags {
-		// Only add hashtag if not already in the text
-		hashtag := "#" + strings.ReplaceAll(tag, " ", "")
-		if !strings.Contains(text, hashtag) {
-			text += " " + hashtag
-		}
-	}
+	text = hashtags.Append(text, tags, c.TagPrefix, c.HashtagStyle)
 	
 	// Check character limit (300 for Bluesky)
 	if len(text) > 300 {
@@ -279,8 +303,36 @@ func (c *Client) PostStatus(text string, mediaBlobs []BlobResponse, altTexts []s
 		post.Facets = facets
 	}
 	
-	// Add images if provided
-	if len(mediaBlobs) > 0 {
+	// Add a video if provided - Bluesky allows a single video and no images alongside it
+	if len(mediaBlobs) > 0 && isVideoBlob(mediaBlobs[0]) {
+		if len(mediaBlobs) > 1 {
+			return fmt.Errorf("a Bluesky post can contain only one video and no other media")
+		}
+		
+		altText := ""
+		if len(altTexts) > 0 {
+			altText = altTexts[0]
+		}
+		
+		blob := mediaBlobs[0]
+		post.Embed = &VideoEmbed{
+			Type: "app.bsky.embed.video",
+			Video: ImageBlob{
+				Type:     blob.Blob.Type,
+				Ref:      blob.Blob.Ref,
+				MimeType: blob.Blob.MimeType,
+				Size:     blob.Blob.Size,
+			},
+			Alt: altText,
+		}
+	} else if len(mediaBlobs) > 0 {
+		// Add images if provided
+		for _, blob := range mediaBlobs {
+			if isVideoBlob(blob) {
+				return fmt.Errorf("a Bluesky post can contain only one video and no other media")
+			}
+		}
+		
 		embed := &Embed{
 			Type:   "app.bsky.embed.images",
 			Images: make([]Image, len(mediaBlobs)),
@@ -357,8 +409,13 @@ func (c *Client) PostStatus(text string, mediaBlobs []BlobResponse, altTexts []s
 	return nil
 }
 
-// UploadMedia uploads an image to Bluesky and returns the blob response
+// UploadMedia uploads an image (or video) to Bluesky and returns the blob response
 func (c *Client) UploadMedia(imagePath string, altText string) (*BlobResponse, string, error) {
+	// Videos go through the video service rather than uploadBlob
+	if IsVideoFile(imagePath) {
+		return c.UploadVideo(imagePath, altText)
+	}
+	
 	// Ensure we're authenticated
 	if c.AccessJWT == "" {
 		if err := c.Authenticate(); err != nil {
@@ -366,24 +423,39 @@ func (c *Client) UploadMedia(imagePath string, altText string) (*BlobResponse, s
 		}
 	}
 	
-	// Open the file
-	file, err := os.Open(imagePath)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
-	
 	// Get file info for size check
-	fileInfo, err := file.Stat()
+	fileInfo, err := os.Stat(imagePath)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get file info: %w", err)
 	}
 	
+	// Re-encode oversized stills as JPEG to fit the 1MB limit. GIFs are left
+	// alone so animations survive.
+	var warnings []string
+	if fileInfo.Size() > MaxBlobSize && strings.ToLower(filepath.Ext(imagePath)) != ".gif" {
+		quality := imageproc.ResolveJPEGQuality(c.JPEGQuality, imageproc.DefaultSocialJPEGQuality)
+		reencoded, cleanup, err := imageproc.Transcode(imagePath, "jpeg", quality)
+		if err == nil {
+			defer cleanup()
+			warnings = c.stripMetadata(imagePath, reencoded)
+			if info, err := os.Stat(reencoded); err == nil {
+				imagePath, fileInfo = reencoded, info
+			}
+		}
+	}
+	
 	// Check file size (1MB limit)
-	if fileInfo.Size() > 1000000 {
+	if fileInfo.Size() > MaxBlobSize {
 		return nil, "", fmt.Errorf("image file size too large. Maximum is 1MB, got %d bytes", fileInfo.Size())
 	}
 	
+	// Open the file
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	
 	// Read file content
 	fileBytes, err := io.ReadAll(file)
 	if err != nil {
@@ -430,12 +502,36 @@ func (c *Client) UploadMedia(imagePath string, altText string) (*BlobResponse, s
 		return nil, "", fmt.Errorf("failed to decode response: %w", err)
 	}
 	
+	blobResp.Warnings = warnings
+	
 	// Store alt text separately - it will be added when creating the post
 	// Bluesky includes alt text in the embed, not during upload
 	
 	return &blobResp, altText, nil
 }
 
+// stripMetadata clears the metadata on a re-encoded copy, keeping only the
+// rights tags from the original when KeepEXIF is set. Converters other than
+// the built-in encoder can carry GPS over, so this runs whenever exiftool is
+// available. It returns warnings for the caller to report.
+func (c *Client) stripMetadata(original, reencoded string) []string {
+	if !metadata.HasExiftool() {
+		if c.KeepEXIF {
+			return []string{fmt.Sprintf("exiftool not found, so copyright and artist weren't kept on the resized %s", filepath.Base(original))}
+		}
+		return nil
+	}
+
+	var tags []string
+	if c.KeepEXIF {
+		tags = metadata.RightsTags
+	}
+	if err := metadata.KeepTags(original, reencoded, tags); err != nil {
+		return []string{fmt.Sprintf("couldn't clean metadata on the resized %s: %v", filepath.Base(original), err)}
+	}
+	return nil
+}
+
 // UploadMediaFromURL downloads an image from URL and uploads it to Bluesky
 func (c *Client) UploadMediaFromURL(imageURL string, altText string) (*BlobResponse, string, error) {
 	if os.Getenv("IMGUP_DEBUG") != "" {
@@ -465,12 +561,21 @@ func (c *Client) UploadMediaFromURL(imageURL string, altText string) (*BlobRespo
 		fmt.Fprintf(os.Stderr, "DEBUG: Image downloaded successfully, creating temp file...\n")
 	}
 	
-	// Create temp file
-	tempFile, err := os.CreateTemp("", "bluesky-upload-*.jpg")
+	// Create temp file. A video keeps its name, so it's sent to the video
+	// service under that name.
+	tempDir, err := os.MkdirTemp("", "bluesky-upload-*")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+	name := "image.jpg"
+	if u, err := url.Parse(imageURL); err == nil && IsVideoFile(u.Path) {
+		name = path.Base(u.Path)
+	}
+	tempFile, err := os.Create(filepath.Join(tempDir, name))
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to create temp file: %w", err)
 	}
-	defer os.Remove(tempFile.Name())
 	defer tempFile.Close()
 	
 	// Copy image data
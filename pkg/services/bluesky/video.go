@@ -0,0 +1,340 @@
+package bluesky
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// videoServiceURL is the Bluesky video processing service
+	videoServiceURL = "https://video.bsky.app"
+
+	// MaxVideoSize is the largest video Bluesky accepts (100MB)
+	MaxVideoSize = 100 * 1024 * 1024
+
+	// MaxVideoDuration is the longest video Bluesky accepts
+	MaxVideoDuration = 3 * time.Minute
+
+	// videoJobTimeout bounds how long we wait for video processing
+	videoJobTimeout = 5 * time.Minute
+)
+
+// videoMimeTypes maps supported video extensions to MIME types
+var videoMimeTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".mov":  "video/quicktime",
+	".webm": "video/webm",
+	".mpeg": "video/mpeg",
+	".mpg":  "video/mpeg",
+}
+
+// VideoEmbed represents an app.bsky.embed.video embed
+type VideoEmbed struct {
+	Type  string    `json:"$type"`
+	Video ImageBlob `json:"video"`
+	Alt   string    `json:"alt,omitempty"`
+}
+
+// JobStatus represents the state of a video processing job
+type JobStatus struct {
+	JobID    string `json:"jobId"`
+	DID      string `json:"did"`
+	State    string `json:"state"`
+	Progress int    `json:"progress,omitempty"`
+	Blob     *struct {
+		Type     string  `json:"$type"`
+		Ref      BlobRef `json:"ref"`
+		MimeType string  `json:"mimeType"`
+		Size     int     `json:"size"`
+	} `json:"blob,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// IsVideoFile reports whether the path looks like a video Bluesky can embed
+func IsVideoFile(path string) bool {
+	_, ok := videoMimeTypes[strings.ToLower(filepath.Ext(path))]
+	return ok
+}
+
+// isVideoBlob reports whether an uploaded blob is a video
+func isVideoBlob(blob BlobResponse) bool {
+	return strings.HasPrefix(blob.Blob.MimeType, "video/")
+}
+
+// UploadVideo uploads a video through the Bluesky video service and waits for
+// processing to finish, returning a blob suitable for a video embed
+func (c *Client) UploadVideo(videoPath string, altText string) (*BlobResponse, string, error) {
+	// Ensure we're authenticated
+	if c.AccessJWT == "" {
+		if err := c.Authenticate(); err != nil {
+			return nil, "", fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	mimeType, ok := videoMimeTypes[strings.ToLower(filepath.Ext(videoPath))]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported video format: %s", filepath.Ext(videoPath))
+	}
+
+	// Check size and duration before sending anything
+	fileInfo, err := os.Stat(videoPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get file info: %w", err)
+	}
+	if fileInfo.Size() > MaxVideoSize {
+		return nil, "", fmt.Errorf("video file size too large. Maximum is 100MB, got %d bytes", fileInfo.Size())
+	}
+	if duration, err := videoDuration(videoPath); err == nil && duration > MaxVideoDuration {
+		return nil, "", fmt.Errorf("video too long. Maximum is %s, got %s", MaxVideoDuration, duration.Round(time.Second))
+	} else if err != nil && os.Getenv("IMGUP_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "DEBUG: Could not determine video duration: %v\n", err)
+	}
+
+	videoBytes, err := os.ReadFile(videoPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// The video service needs a service auth token scoped to uploadBlob on our PDS
+	token, err := c.getServiceAuth("com.atproto.repo.uploadBlob")
+	if err != nil {
+		return nil, "", err
+	}
+
+	params := url.Values{}
+	params.Set("did", c.DID)
+	params.Set("name", filepath.Base(videoPath))
+
+	req, err := http.NewRequest("POST", videoServiceURL+"/xrpc/app.bsky.video.uploadVideo?"+params.Encode(), bytes.NewReader(videoBytes))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", mimeType)
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to upload video: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	// 409 means this video was already uploaded; the response still carries the job
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusConflict {
+		return nil, "", fmt.Errorf("video upload failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var job JobStatus
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if job.JobID == "" {
+		return nil, "", fmt.Errorf("video upload returned no job ID: %s", string(body))
+	}
+
+	blob, err := c.waitForVideoJob(job.JobID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return blob, altText, nil
+}
+
+// waitForVideoJob polls the video service until the job completes or fails
+func (c *Client) waitForVideoJob(jobID string) (*BlobResponse, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	deadline := time.Now().Add(videoJobTimeout)
+
+	for time.Now().Before(deadline) {
+		resp, err := client.Get(videoServiceURL + "/xrpc/app.bsky.video.getJobStatus?jobId=" + url.QueryEscape(jobID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get video job status: %w", err)
+		}
+
+		var status struct {
+			JobStatus JobStatus `json:"jobStatus"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("video job status failed with status %d: %s", resp.StatusCode, string(body))
+		}
+		if err := json.Unmarshal(body, &status); err != nil {
+			return nil, fmt.Errorf("failed to decode job status: %w", err)
+		}
+
+		job := status.JobStatus
+		if os.Getenv("IMGUP_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "DEBUG: Bluesky video job %s: %s (%d%%)\n", jobID, job.State, job.Progress)
+		}
+
+		switch job.State {
+		case "JOB_STATE_COMPLETED":
+			if job.Blob == nil {
+				return nil, fmt.Errorf("video processing completed without a blob")
+			}
+			blob := &BlobResponse{}
+			blob.Blob.Type = job.Blob.Type
+			blob.Blob.Ref = job.Blob.Ref
+			blob.Blob.MimeType = job.Blob.MimeType
+			blob.Blob.Size = job.Blob.Size
+			return blob, nil
+		case "JOB_STATE_FAILED":
+			msg := job.Message
+			if msg == "" {
+				msg = job.Error
+			}
+			return nil, fmt.Errorf("video processing failed: %s", msg)
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+
+	return nil, fmt.Errorf("timed out waiting for video processing after %s", videoJobTimeout)
+}
+
+// getServiceAuth requests a short-lived token the video service can use against our PDS
+func (c *Client) getServiceAuth(lxm string) (string, error) {
+	pdsURL, err := url.Parse(c.pdsEndpoint())
+	if err != nil {
+		return "", fmt.Errorf("invalid PDS URL: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set("aud", "did:web:"+pdsURL.Host)
+	params.Set("lxm", lxm)
+	params.Set("exp", fmt.Sprintf("%d", time.Now().Add(30*time.Minute).Unix()))
+
+	req, err := http.NewRequest("GET", c.PDS+"/xrpc/com.atproto.server.getServiceAuth?"+params.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.AccessJWT)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get service auth: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("service auth failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode service auth: %w", err)
+	}
+
+	return result.Token, nil
+}
+
+// pdsEndpoint returns the account's actual PDS, which may differ from the
+// entryway (e.g. bsky.social) we authenticated against
+func (c *Client) pdsEndpoint() string {
+	if c.serviceEndpoint != "" {
+		return c.serviceEndpoint
+	}
+	return c.PDS
+}
+
+// videoDuration reads the duration from an MP4/QuickTime container's mvhd box
+func videoDuration(path string) (time.Duration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	moovStart, moovSize, err := findBox(f, 0, info.Size(), "moov")
+	if err != nil {
+		return 0, err
+	}
+	mvhdStart, _, err := findBox(f, moovStart, moovSize, "mvhd")
+	if err != nil {
+		return 0, err
+	}
+
+	header := make([]byte, 32)
+	if _, err := f.ReadAt(header, mvhdStart); err != nil {
+		return 0, fmt.Errorf("failed to read mvhd: %w", err)
+	}
+
+	var timescale uint32
+	var duration uint64
+	if header[0] == 1 {
+		// Version 1: 64-bit creation/modification times and duration
+		timescale = binary.BigEndian.Uint32(header[20:24])
+		duration = binary.BigEndian.Uint64(header[24:32])
+	} else {
+		timescale = binary.BigEndian.Uint32(header[12:16])
+		duration = uint64(binary.BigEndian.Uint32(header[16:20]))
+	}
+	if timescale == 0 {
+		return 0, fmt.Errorf("invalid mvhd timescale")
+	}
+
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second)), nil
+}
+
+// findBox scans sibling boxes in [start, start+size) and returns the payload
+// offset and payload size of the first box with the given type
+func findBox(r io.ReaderAt, start, size int64, boxType string) (int64, int64, error) {
+	end := start + size
+	header := make([]byte, 16)
+
+	for offset := start; offset+8 <= end; {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			return 0, 0, fmt.Errorf("failed to read box header: %w", err)
+		}
+
+		boxSize := int64(binary.BigEndian.Uint32(header[:4]))
+		headerSize := int64(8)
+		switch boxSize {
+		case 0:
+			// Box extends to the end of its container
+			boxSize = end - offset
+		case 1:
+			// 64-bit extended size follows the type
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return 0, 0, fmt.Errorf("failed to read box size: %w", err)
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+		if boxSize < headerSize {
+			return 0, 0, fmt.Errorf("invalid box size at offset %d", offset)
+		}
+
+		if string(header[4:8]) == boxType {
+			return offset + headerSize, boxSize - headerSize, nil
+		}
+		offset += boxSize
+	}
+
+	return 0, 0, fmt.Errorf("%s box not found", boxType)
+}
@@ -0,0 +1,116 @@
+package hashtags
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Sanitize strips everything but letters, digits and underscores so a tag
+// renders as a single hashtag on both Mastodon and Bluesky
+func Sanitize(tag string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimPrefix(tag, "#") {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Hashtag styles: AsIs keeps each tag's case, Camel capitalizes each word of
+// a tag ("black and white" becomes #BlackAndWhite) and Lower lowercases it
+const (
+	AsIs  = "asis"
+	Camel = "camel"
+	Lower = "lower"
+)
+
+// Styles lists the hashtag styles
+var Styles = []string{AsIs, Camel, Lower}
+
+// ValidStyle reports whether style is a hashtag style; empty means AsIs
+func ValidStyle(style string) bool {
+	return style == "" || style == AsIs || style == Camel || style == Lower
+}
+
+// Format builds a hashtag from a tag, applying an optional prefix and a style
+func Format(tag, prefix, style string) string {
+	if Sanitize(tag) == "" {
+		return ""
+	}
+	switch style {
+	case Camel:
+		return "#" + camel(prefix) + camel(tag)
+	case Lower:
+		return "#" + strings.ToLower(Sanitize(prefix)+Sanitize(tag))
+	}
+	return "#" + Sanitize(prefix) + Sanitize(tag)
+}
+
+// camel joins a tag's words, split on anything Sanitize drops and on
+// underscores, each with its first letter capitalized
+func camel(tag string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(tag, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		first := []rune(word)
+		first[0] = unicode.ToUpper(first[0])
+		b.WriteString(string(first))
+	}
+	return b.String()
+}
+
+// Extract returns the hashtags already written in text, normalized with key
+func Extract(text string) map[string]bool {
+	found := map[string]bool{}
+	runes := []rune(text)
+	for i, r := range runes {
+		// A hashtag starts a word: "#tag", not "issue#12"
+		if r != '#' || (i > 0 && isTagRune(runes[i-1])) {
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && isTagRune(runes[end]) {
+			end++
+		}
+		if name := key(string(runes[i+1 : end])); name != "" {
+			found[name] = true
+		}
+	}
+	return found
+}
+
+// Missing returns hashtags for the tags that text doesn't already have.
+// Tags match case-insensitively and ignoring punctuation, so "new york" is
+// already covered by #NewYork.
+func Missing(text string, tags []string, prefix, style string) []string {
+	present := Extract(text)
+	var missing []string
+	for _, tag := range tags {
+		hashtag := Format(tag, prefix, style)
+		if hashtag == "" || present[key(hashtag)] {
+			continue
+		}
+		present[key(hashtag)] = true
+		missing = append(missing, hashtag)
+	}
+	return missing
+}
+
+// Append adds hashtags for the given tags to text, skipping any already present
+func Append(text string, tags []string, prefix, style string) string {
+	for _, hashtag := range Missing(text, tags, prefix, style) {
+		text += " " + hashtag
+	}
+	return text
+}
+
+// isTagRune reports whether r can be part of a hashtag
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// key normalizes a hashtag or tag for comparison, ignoring case and
+// underscores along with the punctuation Sanitize drops
+func key(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(Sanitize(tag), "_", ""))
+}
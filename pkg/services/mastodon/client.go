@@ -13,6 +13,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/pdxmph/imgupv2/pkg/services/hashtags"
 )
 
 // Client represents a Mastodon API client
@@ -21,6 +23,54 @@ type Client struct {
 	ClientID     string
 	ClientSecret string
 	AccessToken  string
+	TagPrefix    string // Prefix applied to hashtags built from tags
+	HashtagStyle string // hashtags.AsIs (default), Camel or Lower
+	Poll         *Poll  // Poll attached to the next status, if any
+}
+
+// Poll limits; Mastodon's defaults for max options and expiry
+const (
+	MinPollOptions  = 2
+	MaxPollOptions  = 4
+	MinPollDuration = 5 * time.Minute
+	MaxPollDuration = 30 * 24 * time.Hour
+)
+
+// Poll is a poll attached to a status
+type Poll struct {
+	Options   []string
+	ExpiresIn time.Duration
+}
+
+// NewPoll builds a poll from its options and duration, checking them against
+// Mastodon's limits
+func NewPoll(options []string, expiresIn time.Duration) (*Poll, error) {
+	var cleaned []string
+	for _, option := range options {
+		if option = strings.TrimSpace(option); option != "" {
+			cleaned = append(cleaned, option)
+		}
+	}
+	if len(cleaned) < MinPollOptions || len(cleaned) > MaxPollOptions {
+		return nil, fmt.Errorf("a poll needs %d to %d options, got %d", MinPollOptions, MaxPollOptions, len(cleaned))
+	}
+	if expiresIn < MinPollDuration || expiresIn > MaxPollDuration {
+		return nil, fmt.Errorf("invalid poll duration %s. Must be between %s and %s", expiresIn, MinPollDuration, MaxPollDuration)
+	}
+	return &Poll{Options: cleaned, ExpiresIn: expiresIn}, nil
+}
+
+// ParsePoll builds a poll from "|"-separated options, as given to --poll
+func ParsePoll(options string, expiresIn time.Duration) (*Poll, error) {
+	return NewPoll(strings.Split(options, "|"), expiresIn)
+}
+
+// addTo adds the poll's form fields to a status request
+func (p *Poll) addTo(data url.Values) {
+	for _, option := range p.Options {
+		data.Add("poll[options][]", option)
+	}
+	data.Set("poll[expires_in]", fmt.Sprintf("%d", int(p.ExpiresIn.Seconds())))
 }
 
 // NewClient creates a new Mastodon client
@@ -36,16 +86,14 @@ func NewClient(instanceURL, clientID, clientSecret, accessToken string) *Client
 	}
 }
 
-// PostStatus posts a new status to Mastodon
+// PostStatus posts a new status to Mastodon. Mastodon doesn't allow a poll
+// and media on the same status, so when c.Poll is set and there are media,
+// the poll is posted as a reply to the status with the same text.
 func (c *Client) PostStatus(text string, mediaIDs []string, visibility string, tags []string) error {
+	question := text
+	
 	// Convert tags to hashtags
-	for _, tag := range tags {
-		// Only add hashtag if not already in the text
-		hashtag := "#" + strings.ReplaceAll(tag, " ", "")
-		if !strings.Contains(text, hashtag) {
-			text += " " + hashtag
-		}
-	}
+	text = hashtags.Append(text, tags, c.TagPrefix, c.HashtagStyle)
 	
 	// Build form data
 	data := url.Values{}
@@ -57,10 +105,37 @@ func (c *Client) PostStatus(text string, mediaIDs []string, visibility string, t
 		data.Add("media_ids[]", mediaID)
 	}
 	
+	if c.Poll != nil && len(mediaIDs) == 0 {
+		c.Poll.addTo(data)
+		_, err := c.postStatus(data)
+		return err
+	}
+	
+	statusID, err := c.postStatus(data)
+	if err != nil || c.Poll == nil {
+		return err
+	}
+	
+	if question == "" {
+		question = "Poll"
+	}
+	reply := url.Values{}
+	reply.Set("status", question)
+	reply.Set("visibility", visibility)
+	reply.Set("in_reply_to_id", statusID)
+	c.Poll.addTo(reply)
+	if _, err := c.postStatus(reply); err != nil {
+		return fmt.Errorf("posted the status, but not the poll reply: %w", err)
+	}
+	return nil
+}
+
+// postStatus sends a status form and returns the new status's ID
+func (c *Client) postStatus(data url.Values) (string, error) {
 	// Create request
 	req, err := http.NewRequest("POST", c.InstanceURL+"/api/v1/statuses", strings.NewReader(data.Encode()))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 	
 	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
@@ -70,13 +145,13 @@ func (c *Client) PostStatus(text string, mediaIDs []string, visibility string, t
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to post status: %w", err)
+		return "", fmt.Errorf("failed to post status: %w", err)
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("post failed with status %d: %s", resp.StatusCode, string(body))
+		return "", fmt.Errorf("post failed with status %d: %s", resp.StatusCode, string(body))
 	}
 	
 	// Parse response to get the status URL
@@ -86,10 +161,10 @@ func (c *Client) PostStatus(text string, mediaIDs []string, visibility string, t
 	}
 	
 	if err := json.NewDecoder(resp.Body).Decode(&statusResp); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+		return "", fmt.Errorf("failed to decode response: %w", err)
 	}
 	
-	return nil
+	return statusResp.ID, nil
 }
 
 // UploadMedia uploads an image to Mastodon and returns the media ID
@@ -0,0 +1,41 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileReference starts a template value that names a file holding the
+// template, as in "@~/templates/figure.html"
+const FileReference = "@"
+
+// Source returns the text of a configured template, loading it from disk
+// when the value is a FileReference
+func Source(value string) (string, error) {
+	if !strings.HasPrefix(value, FileReference) {
+		return value, nil
+	}
+	return LoadFile(strings.TrimPrefix(value, FileReference))
+}
+
+// LoadFile reads a template from a file. A leading ~ is expanded to the home
+// directory, and the file's final newline is dropped so snippets don't end
+// with a blank line.
+func LoadFile(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to expand %s: %w", path, err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template: %w", err)
+	}
+	text := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(text, "\r"), nil
+}
@@ -3,11 +3,8 @@ package templates
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
-	
-	"github.com/pdxmph/imgupv2/pkg/backends"
 )
 
 // Variables holds all the available template variables
@@ -97,24 +94,3 @@ func getVariable(name string, vars Variables) string {
 		return ""
 	}
 }
-
-// BuildVariables creates template variables from upload result and metadata
-func BuildVariables(result *backends.UploadResult, imagePath, title, description, alt string, tags []string) Variables {
-	filename := filepath.Base(imagePath)
-	filenameNoExt := strings.TrimSuffix(filename, filepath.Ext(filename))
-	
-	// Build edit URL
-	editURL := "https://www.flickr.com/photos/upload/edit/?ids=" + result.PhotoID
-	
-	return Variables{
-		PhotoID:     result.PhotoID,
-		URL:         result.URL,
-		ImageURL:    result.ImageURL,
-		EditURL:     editURL,
-		Filename:    filenameNoExt,
-		Title:       title,
-		Description: description,
-		Alt:         alt,
-		Tags:        tags,
-	}
-}
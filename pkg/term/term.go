@@ -0,0 +1,62 @@
+// Package term colors the messages imgup prints for people. Color is only
+// used on a terminal, and never when NO_COLOR is set or it has been turned
+// off with Disable.
+package term
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+const (
+	red    = "\033[31m"
+	green  = "\033[32m"
+	yellow = "\033[33m"
+	reset  = "\033[0m"
+)
+
+// disabled is set by --no-color
+var disabled bool
+
+// Disable turns color off for the rest of the run
+func Disable() {
+	disabled = true
+}
+
+// IsTerminal reports whether f is a terminal
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// ColorEnabled reports whether text written to f should be colored.
+// See https://no-color.org for NO_COLOR.
+func ColorEnabled(f *os.File) bool {
+	if disabled || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(f)
+}
+
+// Success colors text written to f green
+func Success(f *os.File, text string) string {
+	return paint(f, green, text)
+}
+
+// Warn colors text written to f yellow
+func Warn(f *os.File, text string) string {
+	return paint(f, yellow, text)
+}
+
+// Error colors text written to f red
+func Error(f *os.File, text string) string {
+	return paint(f, red, text)
+}
+
+// paint wraps text in a color when f takes color
+func paint(f *os.File, color, text string) string {
+	if text == "" || !ColorEnabled(f) {
+		return text
+	}
+	return color + text + reset
+}
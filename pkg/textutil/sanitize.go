@@ -0,0 +1,36 @@
+package textutil
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// parentheticals matches "(1)", "[edited]" and the like
+	parentheticals = regexp.MustCompile(`\s*[(\[][^)\]]*[)\]]`)
+
+	// copySuffix matches the " copy" and " copy 2" Finder adds to duplicates
+	copySuffix = regexp.MustCompile(`(?i)[\s_-]+copy(\s*\d+)?$`)
+
+	// whitespace matches runs of spaces, tabs and the like
+	whitespace = regexp.MustCompile(`\s+`)
+)
+
+// SanitizeFilename tidies a filename for use in titles and snippets:
+// parentheticals and duplicate counters are dropped and whitespace is
+// normalized, so "IMG_1234 (1).jpg" becomes "IMG_1234.jpg". The extension
+// is kept. A name that would end up empty is returned unchanged.
+func SanitizeFilename(filename string) string {
+	ext := filepath.Ext(filename)
+	name := strings.TrimSuffix(filepath.Base(filename), ext)
+
+	clean := parentheticals.ReplaceAllString(name, "")
+	clean = copySuffix.ReplaceAllString(clean, "")
+	clean = whitespace.ReplaceAllString(clean, " ")
+	clean = strings.Trim(clean, " _-.")
+	if clean == "" {
+		return filepath.Base(filename)
+	}
+	return clean + ext
+}
@@ -0,0 +1,58 @@
+package textutil
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Title cleanup modes for Titleize
+const (
+	CleanupFull   = "full"   // separators become spaces and words are title-cased
+	CleanupSpaces = "spaces" // separators become spaces, case is left alone
+	CleanupNone   = "none"   // the filename is used as-is, minus its extension
+)
+
+// minorWords stay lowercase in titles unless they come first
+var minorWords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "by": true, "for": true,
+	"in": true, "of": true, "on": true, "or": true, "the": true, "to": true,
+}
+
+// Titleize turns a filename into a title with full cleanup:
+// "golden_gate-at-dawn.jpg" becomes "Golden Gate at Dawn"
+func Titleize(filename string) string {
+	return TitleizeWith(filename, CleanupFull)
+}
+
+// TitleizeWith turns a filename into a title using the given cleanup mode.
+// Unknown modes are treated as CleanupFull.
+func TitleizeWith(filename, mode string) string {
+	name := filepath.Base(filename)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+
+	if mode == CleanupNone {
+		return name
+	}
+
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || unicode.IsSpace(r)
+	})
+
+	if mode != CleanupSpaces {
+		for i, word := range words {
+			// Words with capitals of their own ("IMG", "iPhone") are left alone
+			if strings.IndexFunc(word, unicode.IsUpper) >= 0 {
+				continue
+			}
+			if i > 0 && minorWords[word] {
+				continue
+			}
+			first, size := utf8.DecodeRuneInString(word)
+			words[i] = string(unicode.ToUpper(first)) + word[size:]
+		}
+	}
+
+	return strings.Join(words, " ")
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/pdxmph/imgupv2/pkg/duplicate"
+	"github.com/pdxmph/imgupv2/pkg/imageproc"
 
 	// Import image format handlers
 	_ "image/gif"
@@ -186,7 +187,7 @@ func (g *Generator) generateThumbnail(imagePath string, maxSize int) (string, er
 		err = png.Encode(&buf, thumb)
 	} else {
 		// Use JPEG for photos
-		err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 80})
+		err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: imageproc.ThumbnailJPEGQuality})
 	}
 	
 	if err != nil {
@@ -15,6 +15,10 @@ type ImageUpload struct {
 	Alt         string   `json:"alt,omitempty"`
 	Description string   `json:"description,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+	DateTaken   string   `json:"date_taken,omitempty"` // Flickr date taken: YYYY-MM-DD[ HH:MM[:SS]] or "auto"
+	RemoteFilename string `json:"remote_filename,omitempty"` // name the service stores the file under; defaults to the path's
+	Format      string   `json:"format,omitempty"` // snippet format for this image's rendered output; defaults to options.format
+	CopyEXIFFrom string  `json:"copy_exif_from,omitempty"` // image to copy copyright, artist and credit tags from; overrides common.copy_exif_from
 }
 
 // CommonSettings applies to all images in the batch
@@ -22,6 +26,19 @@ type CommonSettings struct {
 	Tags    []string `json:"tags,omitempty"`
 	Private bool     `json:"private,omitempty"`
 	Service string   `json:"service,omitempty"` // "flickr" or "smugmug"
+	
+	// Flickr-only settings
+	SafetyLevel string `json:"safety_level,omitempty"` // safe, moderate, restricted
+	ContentType string `json:"content_type,omitempty"` // photo, screenshot, other
+	HiddenFromSearch bool `json:"hidden_from_search,omitempty"`
+	
+	// SmugMug-only settings
+	SmugMugPrivacy string `json:"smugmug_privacy,omitempty"` // public, unlisted, private
+	
+	Transcode string `json:"transcode,omitempty"` // convert before upload: jpeg, png, webp
+	MinDimension int `json:"min_dimension,omitempty"` // reject images whose longest edge is shorter, in pixels
+	Album string `json:"album,omitempty"` // album (Flickr: photoset) name to upload to
+	CopyEXIFFrom string `json:"copy_exif_from,omitempty"` // image to copy copyright, artist and credit tags from into each upload
 }
 
 // SocialSettings configures social media posting
@@ -35,48 +52,78 @@ type MastodonSettings struct {
 	Enabled    bool   `json:"enabled"`
 	Post       string `json:"post,omitempty"`
 	Visibility string `json:"visibility,omitempty"` // public, unlisted, followers, direct
+	Poll       *PollSettings `json:"poll,omitempty"` // posted as a reply, since Mastodon doesn't allow a poll with media
+	Accounts   []string `json:"accounts,omitempty"` // named Mastodon accounts from config, each posted to in turn
+}
+
+// PollSettings for a Mastodon poll
+type PollSettings struct {
+	Options  []string `json:"options"`            // 2 to 4 choices
+	Duration string   `json:"duration,omitempty"` // how long it stays open, e.g. "24h" (the default)
 }
 
 // BlueskySettings for Bluesky posts
 type BlueskySettings struct {
 	Enabled bool   `json:"enabled"`
 	Post    string `json:"post,omitempty"`
+	Account string `json:"account,omitempty"` // named Bluesky account from config
 }
 
 // UploadOptions controls upload behavior
 type UploadOptions struct {
-	Format string `json:"format,omitempty"` // Output format preference
+	Format string `json:"format,omitempty"` // Output format preference; images without their own format are rendered in it
 	DryRun bool   `json:"dry_run,omitempty"`
 	Force  bool   `json:"force,omitempty"` // Force upload even if duplicate
+	Replace bool  `json:"replace,omitempty"` // Like force, and untag the earlier Flickr copies
+	SkipExistingInAlbum bool `json:"skip_existing_in_album,omitempty"` // Skip images the album already has by title or filename
+	VerifyUpload bool `json:"verify_upload,omitempty"` // SmugMug: compare each stored original's checksum with the file
+	BatchID string `json:"batch_id,omitempty"` // Progress key for --resume; defaults to a hash of the input
 }
 
 // BatchUploadResponse represents the JSON output from batch uploads
 type BatchUploadResponse struct {
 	Success bool                `json:"success"`
+	BatchID string              `json:"batch_id,omitempty"`
 	Uploads []UploadResult      `json:"uploads"`
+	Summary *BatchSummary       `json:"summary,omitempty"`
 	Social  *SocialPostResults  `json:"social,omitempty"`
 }
 
+// BatchSummary counts the outcomes of a batch's uploads
+type BatchSummary struct {
+	Uploaded   int `json:"uploaded"`
+	Duplicates int `json:"duplicates"`
+	Failed     int `json:"failed"`
+	Resumed    int `json:"resumed,omitempty"` // skipped by --resume
+}
+
 // UploadResult represents the result of a single image upload
 type UploadResult struct {
 	Path      string   `json:"path"`
 	URL       string   `json:"url,omitempty"`
 	ImageURL  string   `json:"imageUrl,omitempty"`
 	PhotoID   string   `json:"photoId,omitempty"`
+	Service   string   `json:"service,omitempty"` // flickr or smugmug
 	Duplicate bool     `json:"duplicate"`
+	Resumed   bool     `json:"resumed,omitempty"` // skipped by --resume; an earlier run uploaded it
+	Alt       string   `json:"alt,omitempty"`     // alt text generated by describe.endpoint
+	Rendered  string   `json:"rendered,omitempty"` // snippet in the image's format, when the batch asks for one
 	Error     *string  `json:"error"`
+	Retryable bool     `json:"retryable,omitempty"` // failed in transit; running the batch again may succeed
 	Warnings  []string `json:"warnings,omitempty"`
 }
 
 // SocialPostResults contains results from social media posting
 type SocialPostResults struct {
 	Mastodon *SocialPostResult `json:"mastodon,omitempty"`
+	MastodonAccounts []SocialPostResult `json:"mastodon_accounts,omitempty"` // one per named account; mastodon sums them up
 	Bluesky  *SocialPostResult `json:"bluesky,omitempty"`
 }
 
 // SocialPostResult represents the result of a social media post
 type SocialPostResult struct {
 	Success bool    `json:"success"`
+	Account string  `json:"account,omitempty"` // named account; empty for the main account
 	URL     string  `json:"url,omitempty"`
 	Error   *string `json:"error"`
 }
@@ -85,10 +132,26 @@ type SocialPostResult struct {
 type PullRequest struct {
 	Source  PullSource    `json:"source"`
 	Post    string        `json:"post"`                    // Single post text for all images
+	MastodonPost string   `json:"mastodon_post,omitempty"` // Mastodon text instead of post
+	BlueskyPost  string   `json:"bluesky_post,omitempty"`  // Bluesky text instead of post
 	Images  []PullImage   `json:"images"`
 	Targets []string      `json:"targets,omitempty"`       // ["mastodon", "bluesky"]
 	Visibility string     `json:"visibility,omitempty"`    // for mastodon
 	Format  string        `json:"format,omitempty"`        // output format: social, markdown, html
+	Offset  int           `json:"offset,omitempty"`        // images skipped before this page
+	Total   int           `json:"total,omitempty"`         // images available at the source
+}
+
+// PostFor returns the post text for a target, "mastodon" or "bluesky": its
+// own text when set, or else the shared post
+func (r *PullRequest) PostFor(target string) string {
+	switch {
+	case target == "mastodon" && r.MastodonPost != "":
+		return r.MastodonPost
+	case target == "bluesky" && r.BlueskyPost != "":
+		return r.BlueskyPost
+	}
+	return r.Post
 }
 
 // PullSource identifies where images are pulled from
@@ -106,6 +169,7 @@ type PullImage struct {
 	Sizes       ImageSizes  `json:"sizes"`
 	Alt         string      `json:"alt"`                    // alt text
 	Tags        []string    `json:"tags,omitempty"`         // from source service
+	Private     bool        `json:"private,omitempty"`      // in a private album; its URLs only work for the owner
 }
 
 // ImageSizes contains URLs for different image sizes
@@ -115,3 +179,23 @@ type ImageSizes struct {
 	Small  string `json:"small"`
 	Thumb  string `json:"thumb"`
 }
+
+// Pull progress events, in the order they happen for each service
+const (
+	PullUploading  = "uploading"   // an image is being sent to the service
+	PullUploaded   = "uploaded"    // the image was attached
+	PullFailed     = "failed"      // the image couldn't be attached; the post goes ahead without it
+	PullPosting    = "posting"     // the post itself is being made
+	PullPosted     = "posted"
+	PullPostFailed = "post_failed"
+)
+
+// PullProgress reports one step of posting pulled images to social media.
+// The CLI prints them as prose; the GUI emits them as pull-progress events.
+type PullProgress struct {
+	Event   string `json:"event"`             // one of the Pull* event constants
+	Service string `json:"service"`           // "mastodon" or "bluesky"
+	Image   int    `json:"image,omitempty"`   // 1-based position in the request's images; 0 for the post
+	Title   string `json:"title,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
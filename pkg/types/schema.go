@@ -0,0 +1,120 @@
+package types
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SchemaDraft is the JSON Schema dialect the generated schemas declare
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaEnums lists the accepted values for fields that take a fixed set,
+// keyed by "Type.field"
+var schemaEnums = map[string][]string{
+	"CommonSettings.service":         {"flickr", "smugmug"},
+	"CommonSettings.safety_level":    {"safe", "moderate", "restricted"},
+	"CommonSettings.content_type":    {"photo", "screenshot", "other"},
+	"CommonSettings.transcode":       {"jpeg", "png", "webp"},
+	"CommonSettings.smugmug_privacy": {"public", "unlisted", "private"},
+	"MastodonSettings.visibility":    {"public", "unlisted", "followers", "direct"},
+	"PullRequest.visibility":         {"public", "unlisted", "followers", "private", "direct"},
+	"PullSource.service":             {"flickr", "smugmug"},
+}
+
+// BatchUploadSchema returns the JSON Schema for upload --json input
+func BatchUploadSchema() map[string]interface{} {
+	return Schema(BatchUploadRequest{}, "imgup batch upload request")
+}
+
+// PullRequestSchema returns the JSON Schema for pull JSON
+func PullRequestSchema() map[string]interface{} {
+	return Schema(PullRequest{}, "imgup pull request")
+}
+
+// Schema builds a JSON Schema for v from its Go type and json struct tags.
+// Fields without omitempty are required.
+func Schema(v interface{}, title string) map[string]interface{} {
+	schema := typeSchema(reflect.TypeOf(v))
+	schema["$schema"] = SchemaDraft
+	schema["title"] = title
+	return schema
+}
+
+// typeSchema describes a single Go type
+func typeSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return structSchema(t)
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema describes a struct's exported, JSON-visible fields
+func structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		name := field.Name
+		omitempty := false
+		if tag := field.Tag.Get("json"); tag != "" {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				if opt == "omitempty" {
+					omitempty = true
+				}
+			}
+		}
+
+		prop := typeSchema(field.Type)
+		if values, ok := schemaEnums[t.Name()+"."+name]; ok {
+			prop["enum"] = values
+		}
+		properties[name] = prop
+
+		// Optional sections and on/off switches may be left out even without omitempty
+		kind := field.Type.Kind()
+		if !omitempty && kind != reflect.Ptr && kind != reflect.Bool {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
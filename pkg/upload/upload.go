@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/pdxmph/imgupv2/pkg/backends"
 	"github.com/pdxmph/imgupv2/pkg/config"
 	"github.com/pdxmph/imgupv2/pkg/metadata"
+	"github.com/pdxmph/imgupv2/pkg/render"
 	"github.com/pdxmph/imgupv2/pkg/templates"
 )
 
@@ -81,7 +81,7 @@ func (s *Service) Upload(ctx context.Context, imagePath string, opts Options) (*
 	uploadPath := imagePath
 	var tempFile string
 
-	if (opts.Title != "" || opts.Description != "" || len(opts.Tags) > 0) && metadata.HasExiftool() {
+	if (opts.Title != "" || opts.Description != "" || len(opts.Tags) > 0) && s.config.EmbedMetadataInFiles() && metadata.HasExiftool() {
 		writer, err := metadata.NewWriter()
 		if err == nil {
 			tempPath, err := writer.CopyWithMetadata(imagePath, opts.Title, opts.Description, opts.Tags)
@@ -104,32 +104,33 @@ func (s *Service) Upload(ctx context.Context, imagePath string, opts Options) (*
 	}
 
 	// Build result
-	editURL := "https://www.flickr.com/photos/upload/edit/?ids=" + resp.PhotoID
 	result := &Result{
 		PhotoID:   resp.PhotoID,
 		PhotoURL:  resp.URL,
 		DirectURL: resp.ImageURL,
-		EditURL:   editURL,
+		EditURL:   render.EditURL(opts.Backend, resp.PhotoID),
 	}
 
 	// Format output
-	tmpl, exists := s.config.Templates[opts.Format]
-	if !exists {
-		tmpl = s.config.Templates["url"] // Default to URL format
+	format := opts.Format
+	if _, exists := s.config.Templates[format]; !exists {
+		format = "url" // Default to URL format
 	}
 	
 	vars := templates.Variables{
 		PhotoID:     resp.PhotoID,
 		URL:         resp.URL,
 		ImageURL:    resp.ImageURL,
-		EditURL:     editURL,
-		Filename:    filepath.Base(imagePath),
+		EditURL:     result.EditURL,
+		Filename:    render.Filename(imagePath, s.config.Default.SanitizeFilename),
 		Title:       opts.Title,
 		Description: opts.Description,
 		Alt:         opts.Alt,
 		Tags:        opts.Tags,
 	}
-	result.FormattedOutput = templates.Process(tmpl, vars)
+	if result.FormattedOutput, err = render.Snippet(format, vars, s.config); err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
@@ -0,0 +1,91 @@
+#!/bin/bash
+
+# Test script for --alt-required
+# Uploads with and without alt text against replayed Flickr responses
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Alt Required Test"
+echo "========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURES="../tests/fixtures/http"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false, "alt_required": true},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+upload() {
+    IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-setmeta-unavailable.json" ../imgup upload "$@" --service flickr --force --no-remember 2>&1
+}
+
+echo -e "\n${YELLOW}Test: Upload without alt text is refused${NC}"
+output=$(upload "$TEST_IMAGE")
+if [ $? -ne 0 ] && echo "$output" | grep -qF "alt text is required: test_metadata.jpeg has no alt text or description"; then
+    echo -e "${GREEN}✓ $output${NC}"
+else
+    echo -e "${RED}✗ expected an alt text error, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: --alt satisfies the policy${NC}"
+output=$(upload "$TEST_IMAGE" --alt "A red barn")
+if [ $? -eq 0 ] && echo "$output" | grep -qF "54321098765"; then
+    echo -e "${GREEN}✓ $output${NC}"
+else
+    echo -e "${RED}✗ expected the upload to succeed, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: A description satisfies the policy${NC}"
+output=$(upload "$TEST_IMAGE" --description "A red barn beside the road")
+if [ $? -eq 0 ] && echo "$output" | grep -qF "54321098765"; then
+    echo -e "${GREEN}✓ $output${NC}"
+else
+    echo -e "${RED}✗ expected the upload to succeed, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: --alt-required=false overrides the config${NC}"
+output=$(upload "$TEST_IMAGE" --alt-required=false)
+if [ $? -eq 0 ] && echo "$output" | grep -qF "54321098765"; then
+    echo -e "${GREEN}✓ $output${NC}"
+else
+    echo -e "${RED}✗ expected the upload to succeed, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Batch reports the image without alt text${NC}"
+output=$(upload --json <<JSON
+{"images": [
+  {"path": "$TEST_IMAGE", "alt": "A red barn"},
+  {"path": "$TEST_IMAGE"}
+]}
+JSON
+)
+if echo "$output" | grep -qF '"photoId": "54321098765"' && echo "$output" | grep -qF '"error": "alt text is required: test_metadata.jpeg'; then
+    echo -e "${GREEN}✓${NC}"
+    echo "$output"
+else
+    echo -e "${RED}✗ expected one upload and one alt text error, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
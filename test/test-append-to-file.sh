@@ -0,0 +1,66 @@
+#!/bin/bash
+
+# Test script for --append-to-file
+# Appends a single upload replayed from the Flickr cassette, then a JSON batch
+# of the same image, which is a cache hit, and checks both entries landed
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Append To File Test"
+echo "==========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="$(cd ../tests/fixtures && pwd)/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload.json"
+URL="https://www.flickr.com/photos/98806759@N00/54321098766"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": true, "append_separator": "\\n-- %timestamp% --\\n"},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# An empty cassette fails every request
+echo '{"interactions": []}' > "$HOME/offline.json"
+LOG="$HOME/images.md"
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+echo -e "\n${YELLOW}Test: Single upload is appended${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember --append-to-file "$LOG" 2>&1) || fail "upload failed" "$output"
+grep -qF "$URL" "$LOG" || fail "expected $URL in the file" "$(cat "$LOG")"
+[ "$(grep -c '^-- .* --$' "$LOG")" = 1 ] || fail "expected one separator" "$(cat "$LOG")"
+echo -e "${GREEN}✓ $(cat "$LOG")${NC}"
+
+echo -e "\n${YELLOW}Test: Each batch upload is appended${NC}"
+cat > "$HOME/batch.json" <<JSON
+{"images": [{"path": "$TEST_IMAGE", "title": "Batch entry"}], "options": {"format": "markdown"}}
+JSON
+output=$(IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup upload --json-file "$HOME/batch.json" --service flickr --no-remember --append-to-file "$LOG" 2>&1) || fail "batch failed" "$output"
+[ "$(grep -c '^-- .* --$' "$LOG")" = 2 ] || fail "expected a second entry" "$(cat "$LOG")"
+grep -qF "![Batch entry](" "$LOG" || fail "expected the batch's markdown in the file" "$(cat "$LOG")"
+echo -e "${GREEN}✓ $(tail -n 1 "$LOG")${NC}"
+
+echo -e "\n${YELLOW}Test: Batch without a format appends in the default format${NC}"
+cat > "$HOME/batch.json" <<JSON
+{"images": [{"path": "$TEST_IMAGE"}]}
+JSON
+output=$(IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup upload --json-file "$HOME/batch.json" --service flickr --no-remember --append-to-file "$LOG" 2>&1) || fail "batch failed" "$output"
+[ "$(grep -c '^-- .* --$' "$LOG")" = 3 ] || fail "expected a third entry" "$(cat "$LOG")"
+[ "$(tail -n 1 "$LOG")" = "$URL" ] || fail "expected the URL as the last entry" "$(cat "$LOG")"
+echo -e "${GREEN}✓ $(tail -n 1 "$LOG")${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,90 @@
+#!/bin/bash
+
+# Test script for per-image formats in batch output
+# Replays a batch whose images ask for different formats, and checks each
+# upload is rendered in its own format or the batch's
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Batch Format Test"
+echo "========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURES="../tests/fixtures/http"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+for name in a b c; do
+    cp ../tests/fixtures/test_metadata.jpeg "$HOME/$name.jpeg"
+done
+
+# rendered <output> prints each upload's rendered field, one per line
+rendered() {
+    echo "$1" | python3 -c 'import json, sys; print("\n".join(u.get("rendered", "-") for u in json.load(sys.stdin)["uploads"]))'
+}
+
+echo -e "\n${YELLOW}Test: Images are rendered in their own format${NC}"
+output=$(cat <<JSON | IMGUP_HTTP_FIXTURE="$FIXTURES/batch-social-order.json" ../imgup upload --json --no-remember 2>/dev/null
+{
+  "images": [
+    {"path": "$HOME/a.jpeg", "alt": "Pier", "format": "markdown"},
+    {"path": "$HOME/b.jpeg", "title": "Gulls"},
+    {"path": "$HOME/c.jpeg", "format": "url"}
+  ],
+  "common": {"service": "flickr"},
+  "options": {"format": "org-link"}
+}
+JSON
+)
+expected="![Pier](https://live.staticflickr.com/65535/54321098761_abcdef1234_b.jpg)
+[[https://www.flickr.com/photos/98806759@N00/54321098762][Gulls]]
+https://www.flickr.com/photos/98806759@N00/54321098763"
+if [ "$(rendered "$output")" = "$expected" ]; then
+    echo -e "${GREEN}✓ markdown, options.format, url${NC}"
+else
+    echo -e "${RED}✗ expected:${NC}"
+    echo "$expected"
+    echo -e "${RED}got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Batches without a format aren't rendered${NC}"
+output=$(cat <<JSON | IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-upload-date-taken.json" ../imgup upload --json --no-remember 2>/dev/null
+{
+  "images": [{"path": "$HOME/a.jpeg"}],
+  "common": {"service": "flickr"}
+}
+JSON
+)
+if [ "$(rendered "$output")" = "-" ]; then
+    echo -e "${GREEN}✓ no rendered field${NC}"
+else
+    echo -e "${RED}✗ expected no rendered field, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Unknown formats are rejected before uploading${NC}"
+output=$(echo "{\"images\": [{\"path\": \"$HOME/a.jpeg\", \"format\": \"nope\"}]}" | ../imgup upload --json 2>&1)
+if [ $? -ne 0 ] && echo "$output" | grep -qF "Unknown format: nope"; then
+    echo -e "${GREEN}✓ Unknown format: nope${NC}"
+else
+    echo -e "${RED}✗ expected an unknown format error, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
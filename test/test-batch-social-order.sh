@@ -0,0 +1,84 @@
+#!/bin/bash
+
+# Test script for the media in batch social posts
+# Replays batches whose Flickr uploads do or don't return an image URL, and
+# checks missing URLs are looked up again, the post's media follow the batch
+# order, and images still without one stop the post with an error naming them
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Batch Social Order Test"
+echo "==============================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURES="../tests/fixtures/http"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "mastodon": {"instance_url": "https://news.example", "access_token": "token"}
+}
+JSON
+for name in a b c d; do
+    cp ../tests/fixtures/test_metadata.jpeg "$HOME/$name.jpeg"
+done
+
+# batch <image names...> writes a batch posting the images to Mastodon
+batch() {
+    images=""
+    for name in "$@"; do
+        images="$images${images:+, }{\"path\": \"$HOME/$name.jpeg\", \"alt\": \"Photo $name\"}"
+    done
+    cat <<JSON
+{
+  "images": [$images],
+  "common": {"service": "flickr"},
+  "social": {"mastodon": {"enabled": true, "post": "Walk"}}
+}
+JSON
+}
+
+echo -e "\n${YELLOW}Test: Images without an image URL are reported${NC}"
+# The second and fourth uploads get no sizes back from Flickr; looking
+# again finds the fourth's
+output=$(batch a b c d | IMGUP_HTTP_FIXTURE="$FIXTURES/batch-social-missing-url.json" ../imgup upload --json --no-remember 2>/dev/null)
+paths=$(echo "$output" | python3 -c 'import json, os, sys; print(" ".join(os.path.basename(u["path"]) for u in json.load(sys.stdin)["uploads"]))')
+error=$(echo "$output" | python3 -c 'import json, sys; print(json.load(sys.stdin)["social"]["mastodon"]["error"])')
+if [ "$paths" = "a.jpeg b.jpeg c.jpeg d.jpeg" ]; then
+    echo -e "${GREEN}✓ uploads in batch order${NC}"
+else
+    echo -e "${RED}✗ expected uploads a-d in order, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+warnings=$(echo "$output" | python3 -c 'import json, sys; print(" ".join(str(len(u.get("warnings") or [])) for u in json.load(sys.stdin)["uploads"]))')
+if [ "$error" = "image 2 (b.jpeg) has no image URL to attach, so nothing was posted" ] && [ "$warnings" = "0 1 0 0" ]; then
+    echo -e "${GREEN}✓ $error${NC}"
+else
+    echo -e "${RED}✗ unexpected social result:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Media follow the batch order${NC}"
+# The second upload's image URL is only found by looking again
+output=$(batch a b c | IMGUP_DEBUG=1 IMGUP_HTTP_FIXTURE="$FIXTURES/batch-social-order.json" ../imgup upload --json --no-remember 2>&1)
+attached=$(echo "$output" | grep -o "DEBUG: Attaching image [0-9]*" | awk '{print $4}' | tr '\n' ' ')
+if [ "$attached" = "1 2 3 " ] && echo "$output" | grep -qF '"success": true'; then
+    echo -e "${GREEN}✓ attached images $attached${NC}"
+else
+    echo -e "${RED}✗ expected images 1 2 3 posted in order, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,59 @@
+#!/bin/bash
+
+# Test script for the summary at the end of batch uploads
+# Replays one Flickr upload; the second copy of the image finds no recorded
+# interaction and fails
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Batch Summary Test"
+echo "=========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="$(cd ../tests/fixtures && pwd)/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload-date-taken.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+cat > "$HOME/batch.json" <<JSON
+{
+  "images": [{"path": "$TEST_IMAGE"}, {"path": "$TEST_IMAGE"}],
+  "common": {"service": "flickr"}
+}
+JSON
+
+echo -e "\n${YELLOW}Test: Summary counts${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload --json-file "$HOME/batch.json" 2>/dev/null)
+summary=$(echo "$output" | python3 -c 'import json, sys; print(json.dumps(json.load(sys.stdin)["summary"], sort_keys=True))')
+expected='{"duplicates": 0, "failed": 1, "uploaded": 1}'
+if [ "$summary" = "$expected" ]; then
+    echo -e "${GREEN}✓ $summary${NC}"
+else
+    echo -e "${RED}✗ expected $expected, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: No summary line on stderr outside a terminal${NC}"
+errors=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload --json-file "$HOME/batch.json" 2>&1 >/dev/null)
+if echo "$errors" | grep -qF "1 uploaded"; then
+    echo -e "${RED}✗ summary line printed to a pipe:${NC}"
+    echo "$errors"
+    exit 1
+fi
+echo -e "${GREEN}✓ stderr has no summary line${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,85 @@
+#!/bin/bash
+
+# Test script for Bluesky video posts
+# Uploads a short MP4 to a replayed SmugMug and posts it to Bluesky, checking
+# the video service job is polled until it completes or fails, and that videos
+# over Bluesky's duration or size limit are rejected before they're sent
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Bluesky Video Test"
+echo "=========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURES="../tests/fixtures/http"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "smugmug": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret", "album_id": "abc123"},
+  "bluesky": {"handle": "imgupv2.bsky.social", "app_password": "app-password"}
+}
+JSON
+
+# A 30 second MP4 with just the boxes imgup reads the duration from
+python3 - "$HOME/clip.mp4" <<'PY'
+import struct, sys
+def box(kind, payload):
+    return struct.pack(">I", 8 + len(payload)) + kind + payload
+mvhd = struct.pack(">IIIII", 0, 0, 0, 1000, 30000) + bytes(80)
+open(sys.argv[1], "wb").write(box(b"ftyp", b"isom" + struct.pack(">I", 0x200) + b"isom") + box(b"moov", box(b"mvhd", mvhd)))
+PY
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+# post_video <fixture> uploads the clip and posts it to Bluesky
+post_video() {
+    IMGUP_DEBUG=1 IMGUP_HTTP_FIXTURE="$1" ../imgup upload "$HOME/clip.mp4" --service smugmug --no-remember --bluesky --post "River at dusk" 2>&1
+}
+
+echo -e "\n${YELLOW}Test: Upload and poll to completion${NC}"
+output=$(post_video "$FIXTURES/bluesky-video.json")
+echo "$output" | grep -qxF "DEBUG: Bluesky video job job-1: JOB_STATE_ENCODING (50%)" || fail "the job wasn't polled" "$output"
+echo "$output" | grep -qxF "DEBUG: Bluesky video job job-1: JOB_STATE_COMPLETED (100%)" || fail "the job didn't complete" "$output"
+echo "$output" | grep -qxF "Posted to Bluesky successfully!" || fail "the post failed" "$output"
+echo -e "${GREEN}✓ posted after processing${NC}"
+
+echo -e "\n${YELLOW}Test: Poll to failure${NC}"
+output=$(post_video "$FIXTURES/bluesky-video-failed.json")
+echo "$output" | grep -qxF "Bluesky post failed: failed to upload media: video processing failed: Video is not a valid MP4" || fail "expected the processing error" "$output"
+echo -e "${GREEN}✓ processing error reported${NC}"
+
+echo -e "\n${YELLOW}Test: Too long${NC}"
+# The replayed download is 4 minutes long, over Bluesky's 3 minute limit
+output=$(post_video "$FIXTURES/bluesky-video-too-long.json")
+echo "$output" | grep -qxF "Bluesky post failed: failed to upload media: video too long. Maximum is 3m0s, got 4m0s" || fail "expected the duration error" "$output"
+echo -e "${GREEN}✓ rejected before upload${NC}"
+
+echo -e "\n${YELLOW}Test: Too large${NC}"
+# Replay a download one byte over 100MB; the video service isn't in the fixture
+python3 - "$FIXTURES/bluesky-video-too-long.json" "$HOME/too-large.json" <<'PY'
+import json, sys
+cassette = json.load(open(sys.argv[1]))
+for interaction in cassette["interactions"]:
+    if interaction["request"]["url"].endswith(".mp4"):
+        del interaction["response"]["body_base64"]
+        interaction["response"]["body"] = "0" * (100 * 1024 * 1024 + 1)
+json.dump(cassette, open(sys.argv[2], "w"))
+PY
+output=$(post_video "$HOME/too-large.json")
+echo "$output" | grep -qxF "Bluesky post failed: failed to upload media: video file size too large. Maximum is 100MB, got 104857601 bytes" || fail "expected the size error" "$output"
+echo -e "${GREEN}✓ rejected before upload${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,92 @@
+#!/bin/bash
+
+# Test script for concurrent access to the upload cache
+# Runs uploads that record into the cache alongside checks that read from
+# it, the way the GUI and CLI overlap, and checks none of them hit
+# "database is locked" and that the cache is in WAL mode
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Cache Concurrency Test"
+echo "=============================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload.json"
+URL="https://www.flickr.com/photos/98806759@N00/54321098766"
+WRITERS=8
+READERS=8
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": true},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# An empty cassette fails every request, so checks can only answer from the cache
+echo '{"interactions": []}' > "$HOME/offline.json"
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+# Seed the cache with the image the readers check
+cp "$TEST_IMAGE" "$HOME/seed.jpeg"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$HOME/seed.jpeg" --service flickr --force --no-remember 2>&1 </dev/null) || fail "seed upload failed" "$output"
+
+# Each writer uploads a copy with its own checksum
+for i in $(seq 1 $WRITERS); do
+    cp "$TEST_IMAGE" "$HOME/copy-$i.jpeg"
+    printf '%s' "$i" >> "$HOME/copy-$i.jpeg"
+done
+
+echo -e "\n${YELLOW}Test: $WRITERS uploads and $READERS checks at once${NC}"
+mkdir -p "$HOME/out"
+for i in $(seq 1 $WRITERS); do
+    (IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$HOME/copy-$i.jpeg" --service flickr --force --no-remember >"$HOME/out/write-$i" 2>&1 </dev/null; echo $? >>"$HOME/out/write-$i") &
+done
+for i in $(seq 1 $READERS); do
+    (for n in 1 2 3; do
+        IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup check "$HOME/seed.jpeg" --service flickr 2>&1 </dev/null || echo "exit $?"
+    done >"$HOME/out/read-$i") &
+done
+wait
+
+if grep -l "database is locked" "$HOME"/out/* >/dev/null 2>&1; then
+    fail "a process hit a locked database" "$(grep -h "database is locked" "$HOME"/out/*)"
+fi
+for i in $(seq 1 $WRITERS); do
+    [ "$(tail -n 1 "$HOME/out/write-$i")" = 0 ] || fail "upload $i failed" "$(cat "$HOME/out/write-$i")"
+done
+for i in $(seq 1 $READERS); do
+    [ "$(grep -cxF "$URL" "$HOME/out/read-$i")" = 3 ] || fail "check $i didn't find the seeded upload" "$(cat "$HOME/out/read-$i")"
+done
+echo -e "${GREEN}✓ no locked database errors${NC}"
+
+echo -e "\n${YELLOW}Test: Every concurrent upload was recorded${NC}"
+for i in $(seq 1 $WRITERS); do
+    output=$(IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup check "$HOME/copy-$i.jpeg" --service flickr 2>&1 </dev/null) || fail "copy $i isn't in the cache" "$output"
+done
+echo -e "${GREEN}✓ $WRITERS uploads recorded${NC}"
+
+echo -e "\n${YELLOW}Test: The cache uses WAL mode${NC}"
+if command -v sqlite3 >/dev/null 2>&1; then
+    mode=$(sqlite3 "$HOME/.config/imgupv2/uploads.db" 'PRAGMA journal_mode;')
+    [ "$mode" = "wal" ] || fail "expected journal_mode wal" "$mode"
+    echo -e "${GREEN}✓ journal_mode $mode${NC}"
+else
+    echo -e "${YELLOW}- skipped: sqlite3 isn't installed${NC}"
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
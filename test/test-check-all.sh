@@ -0,0 +1,65 @@
+#!/bin/bash
+
+# Test script for check --all
+# Uploads to SmugMug so the cache holds that upload, then checks Flickr's
+# replayed search still finds its own copy, and that --format json has an
+# entry for every service, found or not
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Check All Test"
+echo "======================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURES="../tests/fixtures/http"
+FLICKR_URL="https://www.flickr.com/photos/98806759@N00/3001"
+SMUGMUG_URL="https://example.smugmug.com/Travel/n-abc123/i-XyZ12ab"
+
+# Fake credentials are enough, since every request is replayed. SmugMug
+# searches aren't in the Flickr fixture, so SmugMug falls back to the cache.
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": true},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "smugmug": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret", "album_id": "abc123"}
+}
+JSON
+echo '{"interactions": []}' > "$HOME/offline.json"
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/smugmug-upload-verify.json" ../imgup upload "$TEST_IMAGE" --service smugmug --no-remember 2>&1) || fail "SmugMug upload failed" "$output"
+
+echo -e "\n${YELLOW}Test: Both services are reported${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-search-untagged.json" ../imgup check --all "$TEST_IMAGE" 2>&1) || fail "check failed" "$output"
+echo "$output" | grep -qxF "flickr: $FLICKR_URL" || fail "expected the Flickr copy" "$output"
+echo "$output" | grep -qxF "smugmug: $SMUGMUG_URL" || fail "expected the cached SmugMug copy" "$output"
+echo -e "${GREEN}✓ flickr and smugmug${NC}"
+
+echo -e "\n${YELLOW}Test: JSON has an entry per service, including not found${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup check --all --format json "$TEST_IMAGE" 2>/dev/null)
+summary=$(echo "$output" | python3 -c 'import json, sys; print(" ".join("%s=%s" % (r["service"], r["found"]) for r in json.load(sys.stdin)))')
+[ "$summary" = "flickr=False smugmug=True" ] || fail "unexpected results" "$output"
+echo -e "${GREEN}✓ $summary${NC}"
+
+echo -e "\n${YELLOW}Test: A file on neither service exits non-zero${NC}"
+cp "$TEST_IMAGE" "$HOME/other.jpeg"
+echo "not the same file" >> "$HOME/other.jpeg"
+output=$(IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup check --all "$HOME/other.jpeg" 2>&1) && fail "check succeeded" "$output"
+[ "$output" = "flickr: not found
+smugmug: not found" ] || fail "unexpected output" "$output"
+echo -e "${GREEN}✓ not found on either${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,71 @@
+#!/bin/bash
+
+# Test script for check --batch
+# Uploads one of two images with a replayed Flickr upload, so the cache knows
+# it, then checks both plus a missing file from stdin
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Batch Check Test"
+echo "========================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/flickr-upload-date-taken.json"
+URL="https://www.flickr.com/photos/98806759@N00/54321098765"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": true},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# Two different images
+cp ../tests/fixtures/test_metadata.jpeg "$HOME/a.jpeg"
+(cat ../tests/fixtures/test_metadata.jpeg; echo extra) > "$HOME/b.jpeg"
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$HOME/a.jpeg" --no-remember >/dev/null || fail "upload of a.jpeg failed" ""
+
+# statuses <json> prints each entry as status:name[:url|error]
+statuses() {
+    echo "$1" | python3 -c '
+import json, os, sys
+for e in json.load(sys.stdin):
+    status = "error" if e.get("error") else "duplicate" if e["duplicate"] else "new"
+    print(":".join([status, os.path.basename(e["path"])] + ([e["url"]] if e.get("url") else [])))'
+}
+
+expected=$(printf 'duplicate:a.jpeg:%s\nnew:b.jpeg\nerror:missing.jpeg' "$URL")
+
+echo -e "\n${YELLOW}Test: Paths from lines${NC}"
+output=$(printf '%s\n\n%s\n%s\n' "$HOME/a.jpeg" "$HOME/b.jpeg" "$HOME/missing.jpeg" | ../imgup check --batch --service flickr 2>&1)
+[ "$(statuses "$output")" = "$expected" ] || fail "unexpected statuses" "$output"
+echo -e "${GREEN}✓${NC}"
+
+echo -e "\n${YELLOW}Test: Paths from a JSON list, one at a time${NC}"
+output=$(printf '["%s", "%s", "%s"]' "$HOME/a.jpeg" "$HOME/b.jpeg" "$HOME/missing.jpeg" | ../imgup check --batch --json --concurrency 1 --service flickr 2>&1)
+[ "$(statuses "$output")" = "$expected" ] || fail "unexpected statuses" "$output"
+echo -e "${GREEN}✓${NC}"
+
+echo -e "\n${YELLOW}Test: Bad options${NC}"
+output=$(../imgup check --json "$HOME/a.jpeg" 2>&1) && fail "--json without --batch accepted" "$output"
+echo "$output" | grep -qF -- "--json and --concurrency need --batch" || fail "unexpected error" "$output"
+output=$(echo | ../imgup check --batch --concurrency 0 2>&1) && fail "--concurrency 0 accepted" "$output"
+echo "$output" | grep -qF -- "--concurrency must be at least 1" || fail "unexpected error" "$output"
+echo -e "${GREEN}✓${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
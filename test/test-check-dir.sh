@@ -0,0 +1,89 @@
+#!/bin/bash
+
+# Test script for check --dir and upload --dir with --only-new/--only-duplicates
+# Uploads one of two images with a replayed Flickr upload, so the cache knows it
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Directory Check Test"
+echo "============================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/flickr-upload-date-taken.json"
+URL="https://www.flickr.com/photos/98806759@N00/54321098765"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": true},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# Two different images, one in a subdirectory, plus files check skips
+PICS="$HOME/pics"
+mkdir -p "$PICS/sub" "$PICS/.hidden"
+cp ../tests/fixtures/test_metadata.jpeg "$PICS/a.jpeg"
+(cat ../tests/fixtures/test_metadata.jpeg; echo extra) > "$PICS/sub/b.jpeg"
+cp ../tests/fixtures/test_metadata.jpeg "$PICS/.hidden/c.jpeg"
+echo notes > "$PICS/notes.txt"
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+echo -e "\n${YELLOW}Test: Everything is new${NC}"
+output=$(../imgup check --dir "$PICS" 2>&1)
+expected=$(printf 'new\t%s\nnew\t%s' "$PICS/a.jpeg" "$PICS/sub/b.jpeg")
+[ "$output" = "$expected" ] || fail "unexpected manifest" "$output"
+echo -e "${GREEN}✓${NC}"
+
+IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$PICS/a.jpeg" --no-remember >/dev/null || fail "upload of a.jpeg failed" ""
+
+echo -e "\n${YELLOW}Test: The uploaded image is a duplicate${NC}"
+output=$(../imgup check --dir "$PICS" 2>&1)
+expected=$(printf 'duplicate\t%s\t%s\nnew\t%s' "$PICS/a.jpeg" "$URL" "$PICS/sub/b.jpeg")
+[ "$output" = "$expected" ] || fail "unexpected manifest" "$output"
+echo -e "${GREEN}✓${NC}"
+
+echo -e "\n${YELLOW}Test: Filters${NC}"
+output=$(../imgup check --dir "$PICS" --only-new 2>&1)
+[ "$output" = "$(printf 'new\t%s' "$PICS/sub/b.jpeg")" ] || fail "--only-new kept the wrong images" "$output"
+output=$(../imgup check --dir "$PICS" --only-duplicates --format json 2>&1)
+paths=$(echo "$output" | python3 -c 'import json, sys; print(" ".join(e["path"] for e in json.load(sys.stdin)))')
+[ "$paths" = "$PICS/a.jpeg" ] || fail "--only-duplicates kept the wrong images" "$output"
+echo -e "${GREEN}✓${NC}"
+
+echo -e "\n${YELLOW}Test: upload --dir --only-new uploads the rest${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload --dir "$PICS" --only-new --no-remember 2>&1)
+paths=$(echo "$output" | python3 -c 'import json, sys; print(" ".join(u["path"] for u in json.load(sys.stdin)["uploads"] if u["error"] is None))')
+[ "$paths" = "$PICS/sub/b.jpeg" ] || fail "expected only sub/b.jpeg uploaded" "$output"
+output=$(../imgup upload --dir "$PICS" --only-new 2>&1)
+echo "$output" | grep -qF "no images to upload" || fail "expected nothing left to upload" "$output"
+echo -e "${GREEN}✓${NC}"
+
+echo -e "\n${YELLOW}Test: Filters need --dir${NC}"
+output=$(../imgup check "$PICS/a.jpeg" --only-new 2>&1)
+echo "$output" | grep -qF -- "--only-new and --only-duplicates need --dir" || fail "expected an error" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: Nothing to check${NC}"
+output=$(../imgup check 2>&1) && fail "accepted no image" "$output"
+echo "$output" | grep -qF "nothing to check: pass an image, --dir or --batch" || fail "unexpected error" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: An image and --dir${NC}"
+output=$(../imgup check "$PICS/a.jpeg" --dir "$PICS" 2>&1) && fail "accepted both" "$output"
+echo "$output" | grep -qF "pass an image or --dir, not both" || fail "unexpected error" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,107 @@
+#!/bin/bash
+
+# Test script for upload --copy-exif-from
+# Checks the source is looked for before uploading, and that the option
+# reports a missing exiftool instead of uploading without the tags. It can't
+# be combined with --no-embed-metadata. With exiftool installed, replays a
+# Flickr upload and reads the tags back from the copy that was uploaded
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Copy EXIF Test"
+echo "======================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload.json"
+
+# Fake credentials are enough, since the only upload is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# expect_failure <test name> <expected text> <imgup args...>
+expect_failure() {
+    local name="$1" expected="$2"
+    shift 2
+    if output=$(../imgup "$@" 2>&1); then
+        echo -e "${RED}✗ $name: uploaded anyway:${NC}"
+        echo "$output"
+        exit 1
+    fi
+    if ! echo "$output" | grep -qF -- "$expected"; then
+        echo -e "${RED}✗ $name: unexpected error:${NC}"
+        echo "$output"
+        exit 1
+    fi
+    echo -e "${GREEN}✓ $name${NC}"
+}
+
+echo -e "\n${YELLOW}Test: Missing source${NC}"
+expect_failure "missing source" "can't copy EXIF from $HOME/master.jpg" \
+    upload "$TEST_IMAGE" --service flickr --no-remember --copy-exif-from "$HOME/master.jpg"
+
+echo -e "\n${YELLOW}Test: With --no-embed-metadata${NC}"
+expect_failure "--no-embed-metadata" "none of the others can be" \
+    upload "$TEST_IMAGE" --service flickr --no-remember --no-embed-metadata --copy-exif-from "$TEST_IMAGE"
+
+echo -e "\n${YELLOW}Test: Batch with --no-embed-metadata${NC}"
+batch="{\"images\": [{\"path\": \"$TEST_IMAGE\", \"copy_exif_from\": \"$TEST_IMAGE\"}], \"common\": {\"service\": \"flickr\"}}"
+output=$(echo "$batch" | ../imgup upload --json --no-remember --no-embed-metadata 2>&1)
+if ! echo "$output" | grep -qF -- "copy_exif_from writes tags into the uploaded file, which --no-embed-metadata doesn't allow"; then
+    echo -e "${RED}✗ batch: unexpected output:${NC}"
+    echo "$output"
+    exit 1
+fi
+echo -e "${GREEN}✓ batch${NC}"
+
+EXIFTOOL=$(command -v exiftool || ls /opt/homebrew/bin/exiftool /usr/local/bin/exiftool /usr/bin/exiftool 2>/dev/null | head -n 1)
+if [ -z "$EXIFTOOL" ]; then
+    echo -e "\n${YELLOW}Test: Missing exiftool${NC}"
+    expect_failure "needs exiftool" "copying EXIF from test_metadata.jpeg needs exiftool" \
+        upload "$TEST_IMAGE" --service flickr --no-remember --copy-exif-from "$TEST_IMAGE"
+    echo -e "${YELLOW}exiftool isn't installed; skipped checking the copied tags${NC}"
+else
+    echo -e "\n${YELLOW}Test: Tags copied into the uploaded file${NC}"
+    cp "$TEST_IMAGE" "$HOME/master.jpg"
+    "$EXIFTOOL" -q -overwrite_original -EXIF:Copyright="Copyright Test Owner" -EXIF:Artist="Test Artist" -IPTC:Credit="Test Credit" "$HOME/master.jpg" || exit 1
+
+    # imgup deletes its tagged temp copy after uploading, so an exiftool
+    # wrapper keeps one to read back
+    mkdir -p "$HOME/bin"
+    cat > "$HOME/bin/exiftool" <<SH
+#!/bin/sh
+"$EXIFTOOL" "\$@" || exit \$?
+for last; do :; done
+case " \$* " in *" -TagsFromFile "*) cp "\$last" "$HOME/uploaded.jpeg" ;; esac
+SH
+    chmod +x "$HOME/bin/exiftool"
+
+    if ! output=$(PATH="$HOME/bin:$PATH" IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember --copy-exif-from "$HOME/master.jpg" 2>&1); then
+        echo -e "${RED}✗ upload failed:${NC}"
+        echo "$output"
+        exit 1
+    fi
+    tags=$("$EXIFTOOL" -s3 -Copyright -Artist -Credit "$HOME/uploaded.jpeg")
+    expected="Copyright Test Owner
+Test Artist
+Test Credit"
+    if [ "$tags" != "$expected" ]; then
+        echo -e "${RED}✗ expected the master's tags in the upload, got:${NC}"
+        echo "$tags"
+        exit 1
+    fi
+    echo -e "${GREEN}✓ $(echo "$tags" | paste -sd, -)${NC}"
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,79 @@
+#!/bin/bash
+
+# Test script for upload --copy and --copy-format
+# Replays a Flickr upload and stands in for xclip to capture the clipboard
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Copy Format Test"
+echo "========================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload-date-taken.json"
+URL="https://www.flickr.com/photos/98806759@N00/54321098765"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2" "$HOME/bin"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# xclip stand-in: the clipboard is a file
+CLIPBOARD="$HOME/clipboard"
+cat > "$HOME/bin/xclip" <<SH
+#!/bin/bash
+cat > "$CLIPBOARD"
+SH
+chmod +x "$HOME/bin/xclip"
+export PATH="$HOME/bin:$PATH"
+unset WAYLAND_DISPLAY
+
+# upload_with <flags...>
+upload_with() {
+    rm -f "$CLIPBOARD"
+    IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --no-remember --title "Harbor" --alt "Boats at dusk" "$@"
+}
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+echo -e "\n${YELLOW}Test: --copy copies the printed output${NC}"
+output=$(upload_with --format markdown --copy)
+echo "$output" | grep -qF "![Boats at dusk](" || fail "expected markdown on stdout" "$output"
+[ "$(cat "$CLIPBOARD")" = "$output" ] || fail "clipboard differs from the output" "$(cat "$CLIPBOARD" 2>&1)"
+echo -e "${GREEN}✓${NC}"
+
+echo -e "\n${YELLOW}Test: --copy-format copies another format${NC}"
+output=$(upload_with --format markdown --copy-format url)
+echo "$output" | grep -qF "![Boats at dusk](" || fail "expected markdown on stdout" "$output"
+[ "$(cat "$CLIPBOARD")" = "$URL" ] || fail "expected the URL on the clipboard" "$(cat "$CLIPBOARD" 2>&1)"
+echo -e "${GREEN}✓ $URL${NC}"
+
+echo -e "\n${YELLOW}Test: Unknown --copy-format is rejected before upload${NC}"
+if output=$(../imgup upload "$TEST_IMAGE" --service flickr --no-remember --copy-format nope 2>&1); then
+    fail "accepted an unknown format" "$output"
+fi
+echo "$output" | grep -qF "Unknown format: nope" || fail "unexpected error" "$output"
+echo -e "${GREEN}✓${NC}"
+
+echo -e "\n${YELLOW}Test: A missing clipboard tool is a warning${NC}"
+rm "$HOME/bin/xclip"
+output=$(PATH="$HOME/bin" upload_with --copy 2>&1)
+echo "$output" | grep -qF "$URL" || fail "expected the upload to succeed" "$output"
+echo "$output" | grep -qF "Warning: failed to copy to the clipboard" || fail "expected a warning" "$output"
+echo -e "${GREEN}✓${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,56 @@
+#!/bin/bash
+
+# Test script for upload --date-taken
+# Replays a Flickr upload followed by flickr.photos.setDates
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Date Taken Test"
+echo "======================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload-date-taken.json"
+URL="https://www.flickr.com/photos/98806759@N00/54321098765"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+echo -e "\n${YELLOW}Test: Explicit date${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --no-remember --date-taken "1998-07-04 18:30" 2>&1)
+status=$?
+# No warning means the setDates call was replayed too
+if [ $status -eq 0 ] && [ "$output" = "$URL" ]; then
+    echo -e "${GREEN}✓ $output${NC}"
+else
+    echo -e "${RED}✗ expected a clean upload to $URL, got (exit $status):${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Invalid date${NC}"
+if output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --no-remember --date-taken "July 4" 2>&1); then
+    echo -e "${RED}✗ accepted an invalid date:${NC}"
+    echo "$output"
+    exit 1
+fi
+if ! echo "$output" | grep -qF "invalid date taken 'July 4'"; then
+    echo -e "${RED}✗ unexpected error:${NC}"
+    echo "$output"
+    exit 1
+fi
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
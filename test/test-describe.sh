@@ -0,0 +1,51 @@
+#!/bin/bash
+
+# Test script for alt text generation with describe.endpoint
+# Replays a describe endpoint and a Flickr upload and checks the alt text used
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Describe Test"
+echo "====================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURES="../tests/fixtures/http"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "describe": {"endpoint": "http://localhost:8080/describe"}
+}
+JSON
+
+# expect_output <test name> <cassette> <expected output> [upload flags...]
+expect_output() {
+    name=$1 cassette=$2 expected=$3
+    shift 3
+    echo -e "\n${YELLOW}Test: $name${NC}"
+    output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/$cassette" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember --format markdown "$@" 2>&1)
+    status=$?
+    if [ $status -eq 0 ] && echo "$output" | grep -qF -- "$expected"; then
+        echo -e "${GREEN}✓ $output${NC}"
+    else
+        echo -e "${RED}✗ expected success with \"$expected\", got (exit $status):${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+expect_output "Generated alt text" describe-flickr-upload.json "![A red barn beside a gravel road]"
+expect_output "Endpoint down fails open" describe-unavailable.json "Couldn't generate alt text"
+expect_output "--alt skips the endpoint" flickr-setmeta-unavailable.json "![Given alt text]" --alt "Given alt text"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,62 @@
+#!/bin/bash
+
+# Test script for Flickr error reporting
+# Replays captured Flickr error responses and checks the messages imgup shows
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Flickr Error Test"
+echo "========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURES="../tests/fixtures/http"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# expect_error <test name> <cassette> <expected message>
+expect_error() {
+    echo -e "\n${YELLOW}Test: $1${NC}"
+    output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/$2" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember 2>&1)
+    status=$?
+    if [ $status -ne 0 ] && echo "$output" | grep -qF -- "$3"; then
+        echo -e "${GREEN}✓ $output${NC}"
+    else
+        echo -e "${RED}✗ expected failure mentioning \"$3\", got (exit $status):${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+# expect_warning <test name> <cassette> <expected message>
+expect_warning() {
+    echo -e "\n${YELLOW}Test: $1${NC}"
+    output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/$2" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember --title "Test" 2>&1)
+    status=$?
+    if [ $status -eq 0 ] && echo "$output" | grep -qF -- "$3"; then
+        echo -e "${GREEN}✓ $output${NC}"
+    else
+        echo -e "${RED}✗ expected success with a warning mentioning \"$3\", got (exit $status):${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+expect_error "Upload limit reached" flickr-upload-over-limit.json "reached its upload limit"
+expect_error "Unsupported file type" flickr-upload-bad-filetype.json "--transcode jpeg"
+expect_warning "Metadata API unavailable" flickr-setmeta-unavailable.json "Flickr error 105"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,48 @@
+#!/bin/bash
+
+# Test script for upload --replace
+# Replays a Flickr session where an earlier copy is found by its checksum
+# machine tag, the image is uploaded again and the old copy is untagged
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Replace Upload Test"
+echo "==========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload-replace.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+echo -e "\n${YELLOW}Test: --replace uploads a new copy and untags the old one${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --replace --no-remember --tags test 2>&1)
+status=$?
+echo "$output"
+if [ $status -ne 0 ]; then
+    echo -e "${RED}✗ upload failed (exit $status)${NC}"
+    exit 1
+fi
+if ! echo "$output" | grep -q "22222222222"; then
+    echo -e "${RED}✗ expected the new photo's URL${NC}"
+    exit 1
+fi
+if ! echo "$output" | grep -q "Removed the checksum tag from replaced photo 11111111111"; then
+    echo -e "${RED}✗ expected the old photo to be untagged${NC}"
+    exit 1
+fi
+echo -e "${GREEN}✓ replaced 11111111111 with 22222222222${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,49 @@
+#!/bin/bash
+
+# Test script for remote Flickr checks on photos without a checksum tag
+# Replays searches where no photo has the imgupv2:checksum machine tag, so
+# only the photo titled with the filename is found
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Untagged Flickr Search Test"
+echo "==================================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-search-untagged.json"
+URL="https://www.flickr.com/photos/98806759@N00/3001"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": true},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+echo -e "\n${YELLOW}Test: --prefer-remote finds the photo by title${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup check "$TEST_IMAGE" --service flickr --prefer-remote 2>&1) || fail "check failed" "$output"
+echo "$output" | grep -qF "$URL" || fail "expected $URL" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: --all-matches lists only exact titles${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup check "$TEST_IMAGE" --service flickr --all-matches 2>&1) || fail "check failed" "$output"
+echo "$output" | grep -qF "$URL" || fail "expected $URL" "$output"
+echo "$output" | grep -qF "/3002" && fail "listed a photo with a different title" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
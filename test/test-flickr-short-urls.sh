@@ -0,0 +1,73 @@
+#!/bin/bash
+
+# Test script for default.flickr_short_urls
+# Replays Flickr uploads with known photo IDs and checks their flic.kr links,
+# whose base58 forms were worked out by hand from Flickr's alphabet, then
+# decodes the links back to the IDs with update-metadata
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Flickr Short URL Test"
+echo "============================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"flickr_short_urls": true},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+echo -e "\n${YELLOW}Test: Photo 54321098766 is flic.kr/p/2qLaQ9o${NC}"
+output=$(IMGUP_HTTP_FIXTURE="../tests/fixtures/http/flickr-upload.json" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember 2>&1) || fail "upload failed" "$output"
+[ "$output" = "https://flic.kr/p/2qLaQ9o" ] || fail "expected https://flic.kr/p/2qLaQ9o" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: Photo 22222222222 is flic.kr/p/zRGGss${NC}"
+output=$(IMGUP_HTTP_FIXTURE="../tests/fixtures/http/flickr-upload-replace.json" ../imgup upload "$TEST_IMAGE" --service flickr --replace --no-remember --tags test 2>&1) || fail "upload failed" "$output"
+echo "$output" | grep -qxF "https://flic.kr/p/zRGGss" || fail "expected https://flic.kr/p/zRGGss" "$output"
+echo -e "${GREEN}✓ https://flic.kr/p/zRGGss${NC}"
+
+# expect_photo_id <short link> <photo ID>
+expect_photo_id() {
+    echo -e "\n${YELLOW}Test: $1 decodes to $2${NC}"
+    output=$(IMGUP_HTTP_FIXTURE="../tests/fixtures/http/flickr-update-metadata.json" ../imgup update-metadata "$1" --title "Round trip" 2>&1) || fail "update-metadata failed" "$output"
+    [ "$output" = "Updated flickr photo $2" ] || fail "expected photo $2" "$output"
+    echo -e "${GREEN}✓ $output${NC}"
+}
+
+expect_photo_id "https://flic.kr/p/2qLaQ9o" 54321098766
+expect_photo_id "https://flic.kr/p/zRGGss/" 22222222222
+
+echo -e "\n${YELLOW}Test: Short link with a character outside base58${NC}"
+output=$(../imgup update-metadata "https://flic.kr/p/2qLaQ9O" --title "Round trip" 2>&1) && fail "accepted an O" "$output"
+echo "$output" | grep -qF "not a Flickr photo URL" || fail "unexpected error" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: Long URLs when the option is off${NC}"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+output=$(IMGUP_HTTP_FIXTURE="../tests/fixtures/http/flickr-upload.json" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember 2>&1) || fail "upload failed" "$output"
+[ "$output" = "https://www.flickr.com/photos/98806759@N00/54321098766" ] || fail "expected the photo page URL" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
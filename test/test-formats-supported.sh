@@ -0,0 +1,55 @@
+#!/bin/bash
+
+# Test script for per-service format checks
+# Checks that files a service doesn't accept are refused before anything is uploaded
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Supported Formats Test"
+echo "=============================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+# Fake credentials are enough: nothing may reach the network
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "smugmug": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret", "album_id": "abc123"}
+}
+JSON
+
+echo '{"interactions": []}' > "$HOME/empty.json"
+cp ../tests/fixtures/test_metadata.jpeg "$HOME/photo.webp"
+cp ../tests/fixtures/test_metadata.jpeg "$HOME/scan.tif"
+
+# expect_refused <test name> <expected message> <args...>
+expect_refused() {
+    name=$1 expected=$2
+    shift 2
+    echo -e "\n${YELLOW}Test: $name${NC}"
+    # An empty cassette fails any request, so a refusal must happen first
+    output=$(IMGUP_HTTP_FIXTURE="$HOME/empty.json" ../imgup upload --no-remember --validate=false "$@" 2>&1)
+    status=$?
+    if [ $status -ne 0 ] && echo "$output" | grep -qF -- "$expected"; then
+        echo -e "${GREEN}✓ $output${NC}"
+    else
+        echo -e "${RED}✗ expected a refusal mentioning \"$expected\", got (exit $status):${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+expect_refused "WebP to Flickr" "flickr doesn't support WEBP files; use --transcode jpeg to convert photo.webp" \
+    --service flickr "$HOME/photo.webp"
+expect_refused "TIFF to SmugMug" "smugmug doesn't support TIFF files" --service smugmug "$HOME/scan.tif"
+expect_refused "Transcoding to WebP for Flickr" "flickr doesn't support WEBP files; use --transcode jpeg instead" \
+    --service flickr --transcode webp ../tests/fixtures/test_metadata.jpeg
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,78 @@
+#!/bin/bash
+
+# Test script for output formats
+# Renders every default format, plus %filename% and %edit_url%, against a
+# replayed Flickr upload
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Output Format Test"
+echo "=========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURES="../tests/fixtures/http"
+PAGE="https://www.flickr.com/photos/98806759@N00/54321098765"
+IMAGE="https://live.staticflickr.com/65535/54321098765_abcdef1234_b.jpg"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "templates": {"names": "%filename% %edit_url%"}
+}
+JSON
+
+# expect_format <format> <expected output> [upload flags...]
+expect_format() {
+    format=$1
+    expected=$2
+    shift 2
+    echo -e "\n${YELLOW}Test: --format $format${NC}"
+    output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-upload-sizes.json" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember --format "$format" "$@" 2>/dev/null)
+    if [ "$output" = "$expected" ]; then
+        echo -e "${GREEN}✓${NC}"
+        echo "$output"
+    else
+        echo -e "${RED}✗ expected:${NC}"
+        echo "$expected"
+        echo -e "${RED}got:${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+expect_format url "$PAGE" --title Barn
+expect_format markdown "![Barn]($IMAGE)" --title Barn
+expect_format html "<img src=\"$IMAGE\" alt=\"Barn\">" --title Barn
+expect_format org "[[$IMAGE][Barn]]" --title Barn
+expect_format org-link "[[$PAGE][Barn]]" --title Barn --alt "Red barn"
+expect_format org-image "#+ATTR_HTML: :alt Red barn
+[[$IMAGE]]" --title Barn --alt "Red barn"
+expect_format bbcode "[url=$PAGE][img]$IMAGE[/img][/url]"
+expect_format bbcode-img "[img]$IMAGE[/img]"
+expect_format rst ".. image:: $IMAGE
+   :alt: Red barn
+   :target: $PAGE" --alt "Red barn"
+expect_format json "{\"photo_id\":\"54321098765\",\"url\":\"$PAGE\",\"image_url\":\"$IMAGE\"}"
+expect_format names "test_metadata https://www.flickr.com/photos/upload/edit/?ids=54321098765"
+
+echo -e "\n${YELLOW}Test: Unknown format${NC}"
+output=$(../imgup upload "$TEST_IMAGE" --service flickr --no-remember --format nope 2>&1)
+if [ $? -ne 0 ] && echo "$output" | grep -qF "nope"; then
+    echo -e "${GREEN}✓ $output${NC}"
+else
+    echo -e "${RED}✗ expected an unknown format error before uploading, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
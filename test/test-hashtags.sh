@@ -0,0 +1,80 @@
+#!/bin/bash
+
+# Test script for hashtags in social posts
+# Checks that tags already written as hashtags in the post text aren't added
+# again, and that default.hashtag_style changes the case of the rest
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Hashtag Test"
+echo "===================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+PAGE="https://www.flickr.com/photos/username/54321098765"
+
+# A dry run posts nothing, so fake credentials are enough
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "mastodon": {"instance_url": "https://news.example", "access_token": "token"},
+  "bluesky": {"handle": "me.bsky.social", "app_password": "password"}
+}
+JSON
+
+# expect <test name> <output> <expected line>
+expect() {
+    if echo "$2" | grep -qF -- "$3"; then
+        echo -e "${GREEN}✓ $1: $3${NC}"
+    else
+        echo -e "${RED}✗ $1: expected \"$3\", got:${NC}"
+        echo "$2"
+        exit 1
+    fi
+}
+
+echo -e "\n${YELLOW}Test: Tags already in the text, in a different case${NC}"
+output=$(../imgup post "$PAGE" --mastodon --bluesky --dry-run \
+    --post "Evening light over #OREGON and #new_york" --tags oregon,"New York",Coast,coast,sunset 2>&1)
+expect "Mastodon" "$output" "Hashtags: #Coast #sunset"
+expect "Bluesky" "$output" "$PAGE #Coast #sunset"
+
+echo -e "\n${YELLOW}Test: A longer hashtag doesn't cover a shorter tag${NC}"
+output=$(../imgup post "$PAGE" --mastodon --dry-run --post "Tide pools #coastline" --tags coast 2>&1)
+expect "Mastodon" "$output" "Hashtags: #coast"
+
+# post_with_style <style>
+post_with_style() {
+    ../imgup config set default.hashtag_style "$1" >/dev/null
+    ../imgup post "$PAGE" --mastodon --bluesky --dry-run \
+        --post "Old town #NewYork" --tags "black and white",new_york,Sunset,iPhone-15 2>&1
+}
+
+echo -e "\n${YELLOW}Test: asis hashtag style${NC}"
+output=$(post_with_style asis)
+expect "Mastodon" "$output" "Hashtags: #blackandwhite #Sunset #iPhone15"
+
+echo -e "\n${YELLOW}Test: camel hashtag style${NC}"
+output=$(post_with_style camel)
+expect "Mastodon" "$output" "Hashtags: #BlackAndWhite #Sunset #IPhone15"
+expect "Bluesky" "$output" "$PAGE #BlackAndWhite #Sunset #IPhone15"
+
+echo -e "\n${YELLOW}Test: camel hashtag style with a prefix${NC}"
+output=$(../imgup post "$PAGE" --mastodon --dry-run --post "Harbor" --tags "tide pools" --tag-prefix photo 2>&1)
+expect "Mastodon" "$output" "Hashtags: #PhotoTidePools"
+
+echo -e "\n${YELLOW}Test: lower hashtag style${NC}"
+output=$(post_with_style lower)
+expect "Mastodon" "$output" "Hashtags: #blackandwhite #sunset #iphone15"
+
+echo -e "\n${YELLOW}Test: Unknown hashtag style${NC}"
+output=$(../imgup config set default.hashtag_style title 2>&1)
+expect "config set" "$output" "invalid hashtag style 'title'. Must be one of: asis, camel, lower"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,50 @@
+#!/bin/bash
+
+# Test script for HEIC uploads from the command line
+# Replays a SmugMug upload of a .heic file, which should go up unchanged
+# unless default.convert_heic is on
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 HEIC Test"
+echo "================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/smugmug-upload-verify.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "smugmug": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret", "album_id": "abc123"}
+}
+JSON
+# Bytes no converter can read, so any attempt to convert fails
+head -c 4096 /dev/urandom > "$HOME/photo.heic"
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+echo -e "\n${YELLOW}Test: SmugMug gets HEIC unchanged by default${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$HOME/photo.heic" --service smugmug --no-remember 2>&1) || fail "upload failed" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: default.convert_heic converts for SmugMug too${NC}"
+../imgup config set default.convert_heic true >/dev/null
+if output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$HOME/photo.heic" --service smugmug --no-remember 2>&1); then
+    fail "uploaded without converting" "$output"
+fi
+echo "$output" | grep -qF "transcode failed" || fail "unexpected error" "$output"
+echo -e "${GREEN}✓ tried to convert${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,55 @@
+#!/bin/bash
+
+# Test script for replaying HTTP fixtures
+# Replays the Flickr upload cassette from docs/http-fixtures.md, then checks
+# that the second upload is a cache hit that makes no requests at all
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 HTTP Fixture Replay Test"
+echo "================================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload.json"
+URL="https://www.flickr.com/photos/98806759@N00/54321098766"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": true},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# An empty cassette fails every request
+echo '{"interactions": []}' > "$HOME/offline.json"
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+echo -e "\n${YELLOW}Test: Upload replayed from the cassette${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember 2>&1) || fail "upload failed" "$output"
+echo "$output" | grep -qF "$URL" || fail "expected $URL" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: Duplicate hit makes no requests${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup upload "$TEST_IMAGE" --service flickr --no-remember 2>&1) || fail "duplicate upload failed" "$output"
+echo "$output" | grep -qF "$URL" || fail "expected the cached $URL" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: Requests missing from the cassette fail${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember 2>&1) && fail "upload succeeded without a recorded response" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,53 @@
+#!/bin/bash
+
+# Test script for --json-schema
+# Validates the example batch upload against the generated schema
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 JSON Schema Test"
+echo "========================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+EXAMPLE="../tests/fixtures/batch-upload.json"
+SCHEMA_DIR=$(mktemp -d)
+trap 'rm -rf "$SCHEMA_DIR"' EXIT
+
+echo -e "\n${YELLOW}Test 1: Schemas are valid JSON${NC}"
+for cmd in upload pull; do
+    if ../imgup $cmd --json-schema > "$SCHEMA_DIR/$cmd.json" && python3 -m json.tool "$SCHEMA_DIR/$cmd.json" > /dev/null; then
+        echo -e "${GREEN}✓ imgup $cmd --json-schema${NC}"
+    else
+        echo -e "${RED}✗ imgup $cmd --json-schema${NC}"
+        exit 1
+    fi
+done
+
+echo -e "\n${YELLOW}Test 2: Example batch upload validates against the schema${NC}"
+if ! python3 -c "import jsonschema" 2>/dev/null; then
+    echo -e "${YELLOW}Skipped: pip install jsonschema to run this test${NC}"
+    exit 0
+fi
+python3 - "$SCHEMA_DIR/upload.json" "$EXAMPLE" <<'PY' || exit 1
+import json, sys, jsonschema
+schema = json.load(open(sys.argv[1]))
+example = json.load(open(sys.argv[2]))
+jsonschema.validate(example, schema)
+PY
+echo -e "${GREEN}✓ $EXAMPLE matches the schema${NC}"
+
+echo -e "\n${YELLOW}Test 3: Unknown fields are rejected${NC}"
+python3 - "$SCHEMA_DIR/upload.json" <<'PY' || exit 1
+import json, sys, jsonschema
+schema = json.load(open(sys.argv[1]))
+try:
+    jsonschema.validate({"images": [{"path": "a.jpg", "titel": "typo"}]}, schema)
+except jsonschema.ValidationError:
+    sys.exit(0)
+sys.exit(1)
+PY
+echo -e "${GREEN}✓ Typo'd field rejected${NC}"
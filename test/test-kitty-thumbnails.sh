@@ -0,0 +1,123 @@
+#!/bin/bash
+
+# Test script for Kitty thumbnails in pull's image list
+# Replays a Flickr photostream and stands in for kitten icat, which fails on
+# the second thumbnail partway through its escape sequence. Inside tmux or
+# screen the list is text unless tmux passthrough is configured
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Kitty Thumbnails Test"
+echo "============================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/flickr-pull-thumbnails.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2" "$HOME/bin" "$HOME/tmp"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"kitty_thumbnails": true},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# kitten icat stand-in: prints a marker with its options, or half an image
+# and fails
+cat > "$HOME/bin/kitten" <<'SH'
+#!/bin/bash
+file="${@: -1}"
+if grep -q BROKEN "$file"; then
+    printf '\033_Ga=T,f=100;partial'
+    exit 1
+fi
+echo "[thumbnail ${*:2:$#-2}]"
+SH
+chmod +x "$HOME/bin/kitten"
+
+# pull_list lists the photostream and picks the first image
+pull_list() {
+    printf '1\n' | IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull 5 --service flickr --no-remember --post "Picks" --dry-run 2>&1
+}
+
+export PATH="$HOME/bin:$PATH" TERM=xterm-kitty TMPDIR="$HOME/tmp"
+unset TMUX STY
+output=$(pull_list)
+
+echo -e "\n${YELLOW}Test: A failed thumbnail is listed as text${NC}"
+if echo "$output" | grep -qF "2) Photo 2 [Failed to display thumbnail]" &&
+    echo "$output" | grep -qF "4) Photo 4 [Failed to download thumbnail]"; then
+    echo -e "${GREEN}✓${NC}"
+else
+    echo -e "${RED}✗ expected photos 2 and 4 listed as text, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: The escape sequence is closed before the next line${NC}"
+if printf '%s' "$output" | grep -qF "$(printf 'partial\033\\\033[0m\r\033[2K2) Photo 2')"; then
+    echo -e "${GREEN}✓${NC}"
+else
+    echo -e "${RED}✗ photo 2's line doesn't follow a reset, got:${NC}"
+    echo "$output" | cat -v
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Later images keep their thumbnails${NC}"
+if echo "$output" | grep -A1 -F "[thumbnail --align left]" | grep -qF "3) Photo 3"; then
+    echo -e "${GREEN}✓${NC}"
+else
+    echo -e "${RED}✗ expected photo 3 under its thumbnail, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Text inside tmux${NC}"
+output=$(TMUX=/tmp/tmux-0/default,1,0 pull_list)
+if ! echo "$output" | grep -qF "[thumbnail" &&
+    [ "$(echo "$output" | grep -cF "Note: Kitty thumbnails are off inside tmux")" -eq 1 ] &&
+    echo "$output" | grep -qF "3) Photo 3"; then
+    echo -e "${GREEN}✓${NC}"
+else
+    echo -e "${RED}✗ expected a text list and one note, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Text inside screen${NC}"
+output=$(STY=1234.pts-0.host pull_list)
+if ! echo "$output" | grep -qF "[thumbnail" && echo "$output" | grep -qF "Note: Kitty thumbnails don't work inside GNU screen"; then
+    echo -e "${GREEN}✓${NC}"
+else
+    echo -e "${RED}✗ expected a text list and a note, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: tmux passthrough${NC}"
+../imgup config set default.kitty_in_tmux passthrough >/dev/null
+output=$(TMUX=/tmp/tmux-0/default,1,0 pull_list)
+if echo "$output" | grep -qF "[thumbnail --align left --passthrough tmux]" && ! echo "$output" | grep -qF "Note:"; then
+    echo -e "${GREEN}✓${NC}"
+else
+    echo -e "${RED}✗ expected thumbnails passed through tmux, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Temp files are removed${NC}"
+if [ -z "$(ls "$HOME/tmp" | grep imgup-thumb)" ]; then
+    echo -e "${GREEN}✓${NC}"
+else
+    echo -e "${RED}✗ left behind:${NC}"
+    ls "$HOME/tmp"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,76 @@
+#!/bin/bash
+
+# Test script for posting to named Mastodon accounts
+# Posts to two accounts, one of which rejects its token, against replayed responses
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Mastodon Accounts Test"
+echo "=============================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/mastodon-accounts.json"
+PAGE="https://www.flickr.com/photos/98806759@N00/54321098765"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "mastodon": {"instance_url": "", "client_id": "", "client_secret": "", "accounts": {
+    "news": {"instance_url": "https://news.example", "access_token": "token"},
+    "events": {"instance_url": "https://events.example", "access_token": "revoked"}
+  }}
+}
+JSON
+
+echo -e "\n${YELLOW}Test: post reports each account${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup post "$PAGE" --mastodon --mastodon-account news,events --post "New photo" 2>&1)
+if [ $? -ne 0 ] && echo "$output" | grep -qF "Posted to Mastodon (news) successfully!" && echo "$output" | grep -qF "Mastodon (events) post failed: failed to upload media: upload failed with status 401"; then
+    echo -e "${GREEN}✓${NC}"
+    echo "$output"
+else
+    echo -e "${RED}✗ expected news to succeed and events to fail, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Unknown account is caught before posting${NC}"
+output=$(../imgup post "$PAGE" --mastodon --mastodon-account news,typo 2>&1)
+if [ $? -ne 0 ] && echo "$output" | grep -qF "unknown Mastodon account 'typo'"; then
+    echo -e "${GREEN}✓ $output${NC}"
+else
+    echo -e "${RED}✗ expected an unknown account error, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Batch lists per-account results${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload --json --no-remember 2>&1 <<JSON
+{
+  "images": [{"path": "$TEST_IMAGE", "alt": "A red barn"}],
+  "common": {"service": "flickr"},
+  "social": {"mastodon": {"enabled": true, "post": "New photo", "accounts": ["news", "events"]}}
+}
+JSON
+)
+if echo "$output" | grep -qF '"error": "1 of 2 Mastodon accounts failed (events: ' &&
+    echo "$output" | grep -A1 -F '"success": true' | grep -qF '"account": "news"' &&
+    echo "$output" | grep -A1 -F '"success": false' | grep -qF '"account": "events"'; then
+    echo -e "${GREEN}✓${NC}"
+    echo "$output"
+else
+    echo -e "${RED}✗ expected news to succeed and events to fail, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,97 @@
+#!/bin/bash
+
+# Test script for the Mastodon authorization callback
+# Checks the callback listens on loopback only and needs the state sent with
+# the authorize URL, which also carries a PKCE challenge
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Mastodon Auth Callback Test"
+echo "==================================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/mastodon-auth.json"
+
+# The app is already registered, so only the token exchange is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"; [ -n "$pid" ] && kill $pid 2>/dev/null' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "mastodon": {"instance_url": "https://news.example", "client_id": "client", "client_secret": "secret"}
+}
+JSON
+
+# start_auth runs 'imgup auth mastodon' in the background and sets $state
+# from the authorize URL it prints
+start_auth() {
+    IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup auth mastodon > "$HOME/auth.log" 2>&1 &
+    pid=$!
+    for i in $(seq 50); do
+        state=$(grep -o 'state=[0-9a-f]*' "$HOME/auth.log" | cut -d= -f2)
+        [ -n "$state" ] && return
+        sleep 0.1
+    done
+    echo -e "${RED}✗ no authorize URL with a state:${NC}"
+    cat "$HOME/auth.log"
+    exit 1
+}
+
+# finish_auth waits for imgup and sets $status
+finish_auth() {
+    wait $pid
+    status=$?
+    pid=
+}
+
+echo -e "\n${YELLOW}Test: Loopback only${NC}"
+start_auth
+if ss -ltn | grep -qE '127\.0\.0\.1:8080\b' && ! ss -ltn | grep -qE '(\*|0\.0\.0\.0|\[::\]):8080\b'; then
+    echo -e "${GREEN}✓ listening on 127.0.0.1:8080 only${NC}"
+else
+    echo -e "${RED}✗ unexpected listeners:${NC}"
+    ss -ltn | grep 8080
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: PKCE challenge${NC}"
+if grep -qE 'code_challenge=[A-Za-z0-9_-]{43}&code_challenge_method=S256' "$HOME/auth.log"; then
+    echo -e "${GREEN}✓ S256 challenge in the authorize URL${NC}"
+else
+    echo -e "${RED}✗ no S256 code_challenge in:${NC}"
+    cat "$HOME/auth.log"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Mismatched state${NC}"
+code=$(curl -s -o /dev/null -w '%{http_code}' "http://127.0.0.1:8080/callback?code=abc&state=forged")
+finish_auth
+if [ "$code" = "400" ] && [ $status -ne 0 ] && grep -qF "state didn't match" "$HOME/auth.log"; then
+    echo -e "${GREEN}✓ callback refused (HTTP $code) and authorization stopped${NC}"
+else
+    echo -e "${RED}✗ expected HTTP 400 and a failed authorization, got HTTP $code, exit $status:${NC}"
+    cat "$HOME/auth.log"
+    exit 1
+fi
+if grep -qF '"access_token"' "$HOME/.config/imgupv2/config.json"; then
+    echo -e "${RED}✗ a token was saved${NC}"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Matching state${NC}"
+start_auth
+code=$(curl -s -o /dev/null -w '%{http_code}' "http://127.0.0.1:8080/callback?code=abc&state=$state")
+finish_auth
+if [ "$code" = "200" ] && [ $status -eq 0 ] && grep -qF "Authenticated as @photos" "$HOME/auth.log"; then
+    echo -e "${GREEN}✓ token exchanged for @photos${NC}"
+else
+    echo -e "${RED}✗ expected a successful authorization, got HTTP $code, exit $status:${NC}"
+    cat "$HOME/auth.log"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
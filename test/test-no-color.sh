@@ -0,0 +1,86 @@
+#!/bin/bash
+
+# Test script for colored output
+# Runs imgup under script(1) to get a terminal, and checks color is used there
+# but not with NO_COLOR, --no-color, a pipe, or in JSON output
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 No Color Test"
+echo "====================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+if ! command -v script >/dev/null; then
+    echo -e "${YELLOW}script(1) not found, skipping${NC}"
+    exit 0
+fi
+
+IMGUP="$(cd .. && pwd)/imgup"
+TEST_IMAGE="$(cd ../tests/fixtures && pwd)/test_metadata.jpeg"
+FIXTURE="$(cd ../tests/fixtures/http && pwd)/flickr-upload-date-taken.json"
+ESC=$(printf '\033')
+
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# The second copy of the image finds no recorded interaction and fails
+cat > "$HOME/batch.json" <<JSON
+{
+  "images": [{"path": "$TEST_IMAGE"}, {"path": "$TEST_IMAGE"}],
+  "common": {"service": "flickr"}
+}
+JSON
+
+# on_terminal <command> runs a shell command with a terminal for its output
+on_terminal() {
+    script -qc "$1" /dev/null
+}
+
+# expect_plain <test name> <output>
+expect_plain() {
+    if echo "$2" | grep -qF "$ESC["; then
+        echo -e "${RED}✗ $1: output is colored:${NC}"
+        echo "$2" | cat -v
+        exit 1
+    fi
+    echo -e "${GREEN}✓ $1${NC}"
+}
+
+echo -e "\n${YELLOW}Test: Errors are red on a terminal${NC}"
+output=$(on_terminal "'$IMGUP' update-metadata 12345")
+if echo "$output" | grep -qF "${ESC}[31mError:${ESC}[0m nothing to do"; then
+    echo -e "${GREEN}✓ Error: is red${NC}"
+else
+    echo -e "${RED}✗ expected a red Error:, got:${NC}"
+    echo "$output" | cat -v
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Color is turned off${NC}"
+expect_plain "NO_COLOR" "$(on_terminal "NO_COLOR=1 '$IMGUP' update-metadata 12345")"
+expect_plain "--no-color" "$(on_terminal "'$IMGUP' --no-color update-metadata 12345")"
+expect_plain "pipe" "$("$IMGUP" update-metadata 12345 2>&1)"
+
+echo -e "\n${YELLOW}Test: Batch summary is colored, JSON is not${NC}"
+output=$(on_terminal "IMGUP_HTTP_FIXTURE='$FIXTURE' '$IMGUP' upload --json-file '$HOME/batch.json'")
+if echo "$output" | grep -qF "${ESC}[31m1 uploaded, 1 failed${ESC}[0m"; then
+    echo -e "${GREEN}✓ summary line is red${NC}"
+else
+    echo -e "${RED}✗ expected a red summary line, got:${NC}"
+    echo "$output" | cat -v
+    exit 1
+fi
+expect_plain "JSON output" "$(echo "$output" | grep -vF "1 uploaded, 1 failed")"
+
+echo -e "\n${GREEN}All tests passed${NC}"
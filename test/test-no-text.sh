@@ -0,0 +1,75 @@
+#!/bin/bash
+
+# Test script for --no-text
+# Checks dry-run posts carry no text, link or hashtags, and that post text
+# and polls are refused
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 No Text Test"
+echo "===================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+PAGE="https://www.flickr.com/photos/username/54321098765"
+
+# A dry run posts nothing, so fake credentials are enough
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "mastodon": {"instance_url": "https://news.example", "access_token": "token"},
+  "bluesky": {"handle": "me.bsky.social", "app_password": "secret"}
+}
+JSON
+
+# expect_line <test name> <output> <expected line>
+expect_line() {
+    if echo "$2" | grep -qxF -- "$3"; then
+        echo -e "${GREEN}✓ $1${NC}"
+    else
+        echo -e "${RED}✗ $1: expected line \"$3\", got:${NC}"
+        echo "$2"
+        exit 1
+    fi
+}
+
+# expect_refused <test name> <expected error> <args...>
+expect_refused() {
+    name=$1 message=$2
+    shift 2
+    if output=$(../imgup "$@" 2>&1); then
+        echo -e "${RED}✗ $name: accepted:${NC}"
+        echo "$output"
+        exit 1
+    fi
+    if echo "$output" | grep -qF -- "$message"; then
+        echo -e "${GREEN}✓ $name${NC}"
+    else
+        echo -e "${RED}✗ $name: expected \"$message\", got:${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+echo -e "\n${YELLOW}Test: Image only${NC}"
+output=$(../imgup post "$PAGE" --mastodon --bluesky --dry-run --no-text --tags fog,oregon 2>&1)
+expect_line "Empty Mastodon text" "$output" "  Text: "
+expect_line "Empty Bluesky text" "$output" "  Text (0 chars): "
+if echo "$output" | grep -qF "Hashtags:"; then
+    echo -e "${RED}✗ hashtags added to an image-only post:${NC}"
+    echo "$output"
+    exit 1
+fi
+echo -e "${GREEN}✓ No hashtags${NC}"
+
+echo -e "\n${YELLOW}Test: Conflicting options${NC}"
+expect_refused "Post text" "can't be combined with post text" post "$PAGE" --mastodon --dry-run --no-text --post "Fog again"
+expect_refused "Poll" "--poll can't be used with --no-text" upload ../tests/fixtures/test_metadata.jpeg --mastodon --dry-run --no-text --poll "Yes|No"
+
+echo -e "\n${GREEN}All tests passed${NC}"
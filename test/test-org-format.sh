@@ -0,0 +1,60 @@
+#!/bin/bash
+
+# Test script for the Org-mode formats
+# Checks that the org templates' double brackets come through as literal
+# text, with the alt/description/title/filename fallbacks filled in
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Org Format Test"
+echo "======================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload-sizes.json"
+PAGE="https://www.flickr.com/photos/98806759@N00/54321098765"
+IMAGE="https://live.staticflickr.com/65535/54321098765_abcdef1234_b.jpg"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "templates": {"org-both": "[[%url%][%title%]] [[%image_url%]]"}
+}
+JSON
+
+# expect_output <description> <expected output> [upload flags...]
+expect_output() {
+    name=$1
+    expected=$2
+    shift 2
+    echo -e "\n${YELLOW}Test: $name${NC}"
+    output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember "$@" 2>/dev/null)
+    if [ "$output" = "$expected" ]; then
+        echo -e "${GREEN}✓ $output${NC}"
+    else
+        echo -e "${RED}✗ expected:${NC}"
+        echo "$expected"
+        echo -e "${RED}got:${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+expect_output "org prefers alt text" "[[$IMAGE][Red barn]]" --format org --title Barn --alt "Red barn"
+expect_output "org falls back to the description" "[[$IMAGE][Barn at dusk]]" --format org --description "Barn at dusk"
+expect_output "org-link prefers the title" "[[$PAGE][Barn]]" --format org-link --title Barn --alt "Red barn"
+expect_output "org-link falls back to alt text" "[[$PAGE][Red barn]]" --format org-link --alt "Red barn"
+expect_output "org-image puts alt text in the attribute line" "#+ATTR_HTML: :alt Red barn
+[[$IMAGE]]" --format org-image --alt "Red barn"
+expect_output "Configured template with two links" "[[$PAGE][Barn]] [[$IMAGE]]" --format org-both --title Barn
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,70 @@
+#!/bin/bash
+
+# Test script for --post-file and --post -
+# Checks that post text is read from a file or stdin, keeping line breaks,
+# and that pull only takes it from stdin with --json
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Post File Test"
+echo "======================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+PHOTO="https://www.flickr.com/photos/98806759@N00/2001"
+
+# Fake credentials are enough for a dry run
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "mastodon": {"instance_url": "https://mastodon.example", "client_id": "id", "client_secret": "secret", "access_token": "token"}
+}
+JSON
+
+# expect_text <test name> <output> <expected text block>
+expect_text() {
+    if echo "$2" | grep -A3 "Text:" | tr '\n' '|' | grep -qF -- "$3"; then
+        echo -e "${GREEN}✓ $1${NC}"
+    else
+        echo -e "${RED}✗ $1, got:${NC}"
+        echo "$2"
+        exit 1
+    fi
+}
+
+echo -e "\n${YELLOW}Test: Text from a file${NC}"
+printf 'First line\nSecond line\n' > "$HOME/post.txt"
+output=$(../imgup post "$PHOTO" --mastodon --post-file "$HOME/post.txt" --dry-run 2>&1)
+expect_text "multi-line text without the trailing newline" "$output" "Text: First line|Second line||$PHOTO"
+
+echo -e "\n${YELLOW}Test: Text from stdin${NC}"
+output=$(printf 'From stdin\n' | ../imgup post "$PHOTO" --mastodon --post - --dry-run 2>&1)
+expect_text "stdin text" "$output" "Text: From stdin||$PHOTO"
+
+echo -e "\n${YELLOW}Test: --post and --post-file together${NC}"
+if output=$(../imgup post "$PHOTO" --mastodon --post "Hi" --post-file "$HOME/post.txt" --dry-run 2>&1); then
+    echo -e "${RED}✗ accepted both:${NC}"
+    echo "$output"
+    exit 1
+fi
+echo -e "${GREEN}✓ refused${NC}"
+
+echo -e "\n${YELLOW}Test: pull --post - without --json${NC}"
+if output=$(printf 'From stdin\n' | ../imgup pull --service flickr --mastodon --post - 2>&1); then
+    echo -e "${RED}✗ accepted stdin text alongside the image selection:${NC}"
+    echo "$output"
+    exit 1
+fi
+if ! echo "$output" | grep -qF -- "--post - needs --json"; then
+    echo -e "${RED}✗ unexpected error:${NC}"
+    echo "$output"
+    exit 1
+fi
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
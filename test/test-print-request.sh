@@ -0,0 +1,55 @@
+#!/bin/bash
+
+# Test script for upload --print-request
+# Checks that the effective batch request, with defaults and flags applied, goes to stderr
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Print Request Test"
+echo "=========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+
+# Fake credentials are enough for a dry run
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false, "title_from_filename": true},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret", "safety_level": "moderate"}
+}
+JSON
+
+BATCH="$HOME/batch.json"
+cat > "$BATCH" <<JSON
+{"images": [{"path": "$TEST_IMAGE"}], "common": {"tags": ["harbor"]}, "options": {"batch_id": "print-test"}}
+JSON
+
+echo -e "\n${YELLOW}Test: Effective request on stderr${NC}"
+request=$(../imgup upload --json-file "$BATCH" --dry-run --force --min-dimension 100 --print-request 2>&1 >/dev/null)
+for expected in '"title": "Test Metadata"' '"service": "flickr"' '"safety_level": "moderate"' \
+    '"min_dimension": 100' '"force": true' '"dry_run": true' '"batch_id": "print-test"'; do
+    if ! echo "$request" | grep -qF -- "$expected"; then
+        echo -e "${RED}✗ missing $expected in:${NC}"
+        echo "$request"
+        exit 1
+    fi
+done
+echo -e "${GREEN}✓ request includes defaults, flags and the batch id${NC}"
+
+echo -e "\n${YELLOW}Test: Nothing printed without the flag${NC}"
+stderr=$(../imgup upload --json-file "$BATCH" --dry-run 2>&1 >/dev/null)
+if echo "$stderr" | grep -qF '"images"'; then
+    echo -e "${RED}✗ request printed without --print-request:${NC}"
+    echo "$stderr"
+    exit 1
+fi
+echo -e "${GREEN}✓ stderr has no request${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,67 @@
+#!/bin/bash
+
+# Test script for the per-profile upload cache
+# Uploads the same file under two profiles with replayed Flickr uploads, and
+# checks the second profile doesn't take it for a duplicate of the first's
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Profile Cache Test"
+echo "=========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/flickr-upload-date-taken.json"
+URL="https://www.flickr.com/photos/98806759@N00/54321098765"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+CONFIG_DIR="$HOME/.config/imgupv2"
+mkdir -p "$CONFIG_DIR"
+cat > "$CONFIG_DIR/config.json" <<JSON
+{
+  "default": {"duplicate_check": true},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+../imgup profile use --create client-a >/dev/null
+../imgup profile use default >/dev/null
+cp "$CONFIG_DIR/config.json" "$CONFIG_DIR/profiles/client-a/config.json"
+
+# A fixture without interactions fails any request, so an upload that
+# succeeds with it came from the cache
+echo '{"interactions": []}' > "$HOME/offline.json"
+cp ../tests/fixtures/test_metadata.jpeg "$HOME/a.jpeg"
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+echo -e "\n${YELLOW}Test: Upload under the default profile${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$HOME/a.jpeg" --no-remember 2>&1)
+[ "$output" = "$URL" ] || fail "upload failed" "$output"
+[ -f "$CONFIG_DIR/uploads.db" ] || fail "no cache in the default profile" "$(find "$HOME" -name 'uploads.db*')"
+output=$(IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup upload "$HOME/a.jpeg" --no-remember 2>&1)
+echo "$output" | grep -qF "$URL" || fail "second upload wasn't a duplicate" "$output"
+echo -e "${GREEN}✓ duplicate within the profile${NC}"
+
+echo -e "\n${YELLOW}Test: Same file under another profile${NC}"
+if output=$(../imgup check "$HOME/a.jpeg" --service flickr --config-profile client-a 2>&1); then
+    fail "client-a sees the default profile's upload" "$output"
+fi
+if output=$(IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup upload "$HOME/a.jpeg" --no-remember --config-profile client-a 2>&1); then
+    fail "client-a returned the default profile's URL without uploading" "$output"
+fi
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$HOME/a.jpeg" --no-remember --config-profile client-a 2>&1)
+[ "$output" = "$URL" ] || fail "upload under client-a failed" "$output"
+[ -f "$CONFIG_DIR/profiles/client-a/uploads.db" ] || fail "no cache in client-a" "$(find "$HOME" -name 'uploads.db*')"
+../imgup check "$HOME/a.jpeg" --service flickr --config-profile client-a >/dev/null || fail "client-a didn't record its upload" ""
+echo -e "${GREEN}✓ uploaded again, into client-a's cache${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,55 @@
+#!/bin/bash
+
+# Test script for config profiles
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Profiles Test"
+echo "====================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+CONFIG_DIR="$HOME/.config/imgupv2"
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+echo -e "\n${YELLOW}Test: Unknown profile${NC}"
+if output=$(../imgup profile use client-a 2>&1); then
+    fail "switched to a profile that doesn't exist" "$output"
+fi
+echo "$output" | grep -qF "no profile named 'client-a'" || fail "unexpected error" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: Create and switch${NC}"
+../imgup profile use --create client-a >/dev/null || fail "profile use --create failed" ""
+../imgup config set default.format html >/dev/null
+[ -f "$CONFIG_DIR/profiles/client-a/config.json" ] || fail "setting not saved in the profile" "$(find "$HOME" -type f)"
+[ ! -f "$CONFIG_DIR/config.json" ] || fail "default config written while client-a was active" ""
+output=$(../imgup profile list)
+[ "$output" = "$(printf '  default\n* client-a')" ] || fail "unexpected profile list" "$output"
+output=$(../imgup config show)
+echo "$output" | grep -qF "Profile: client-a" || fail "config show doesn't name the profile" "$output"
+echo -e "${GREEN}✓ settings go to the active profile${NC}"
+
+echo -e "\n${YELLOW}Test: --config-profile for one command${NC}"
+output=$(../imgup --config-profile default config path)
+[ "$output" = "$CONFIG_DIR/config.json" ] || fail "--config-profile ignored" "$output"
+[ "$(../imgup config path)" = "$CONFIG_DIR/profiles/client-a/config.json" ] || fail "--config-profile switched profiles" ""
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: Back to default${NC}"
+../imgup profile use default >/dev/null
+[ ! -f "$CONFIG_DIR/active_profile" ] || fail "active_profile left behind" "$(cat "$CONFIG_DIR/active_profile")"
+[ "$(../imgup config path)" = "$CONFIG_DIR/config.json" ] || fail "still using client-a" ""
+echo -e "${GREEN}✓ default profile active${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
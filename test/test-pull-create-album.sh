@@ -0,0 +1,62 @@
+#!/bin/bash
+
+# Test script for creating a missing SmugMug pull album
+# Replays an account without the default Sharing album, and checks pull
+# explains how to create it, then creates it with --create-album. Names typed
+# with --album, and likely typos, aren't created by smugmug.create_pull_album
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Pull Create Album Test"
+echo "=============================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/smugmug-pull-create-album.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "smugmug": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret", "album_id": "abc123"}
+}
+JSON
+
+# check <test name> <expected exit status> <output> <status> <expected text>
+check() {
+    if [ $4 -eq $2 ] && echo "$3" | grep -qF -- "$5"; then
+        echo -e "${GREEN}✓ $1${NC}"
+    else
+        echo -e "${RED}✗ $1: expected exit $2 with \"$5\", got (exit $4):${NC}"
+        echo "$3"
+        exit 1
+    fi
+}
+
+echo -e "\n${YELLOW}Test: Missing album without a terminal${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull --service smugmug --no-remember --json </dev/null 2>&1)
+check "Hint" 1 "$output" $? "album 'Sharing' not found (run again with --create-album to create it)"
+
+echo -e "\n${YELLOW}Test: --create-album${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull --service smugmug --no-remember --create-album </dev/null 2>&1)
+check "Created" 0 "$output" $? "Created unlisted SmugMug album 'Sharing'"
+
+echo -e "\n${YELLOW}Test: smugmug.create_pull_album${NC}"
+../imgup config set smugmug.create_pull_album true >/dev/null
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull --service smugmug --no-remember --json </dev/null 2>&1)
+check "Created" 0 "$output" $? "Created unlisted SmugMug album 'Sharing'"
+
+echo -e "\n${YELLOW}Test: smugmug.create_pull_album leaves --album names alone${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull --service smugmug --album Travel --no-remember --json </dev/null 2>&1)
+check "Hint" 1 "$output" $? "album 'Travel' not found (run again with --create-album to create it)"
+
+echo -e "\n${YELLOW}Test: Name with similar albums${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull --service smugmug --album Fam --no-remember --json </dev/null 2>&1)
+check "Suggestions" 1 "$output" $? "Did you mean one of: Family (run again with --create-album to create 'Fam' anyway)"
+
+echo -e "\n${GREEN}All tests passed${NC}"
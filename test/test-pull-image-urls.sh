@@ -0,0 +1,96 @@
+#!/bin/bash
+
+# Test script for pulled images without a usable image URL
+# Edits the pull JSON with a script standing in for $EDITOR, blanking image
+# sizes, and checks the unusable images are reported and left out
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Pull Image URLs Test"
+echo "============================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/flickr-pull-photostream.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# blank_sizes <sizes> makes $EDITOR fill in post and blank the listed sizes
+# of the first image, or every size with "all"
+blank_sizes() {
+    cat > "$HOME/editor.sh" <<SCRIPT
+#!/bin/sh
+python3 - "\$1" <<'PY'
+import json, sys
+path = sys.argv[1]
+req = json.load(open(path))
+req["post"] = "Fog rolling up the river"
+sizes = req["images"][0]["sizes"]
+for key in list(sizes):
+    if "$1" == "all" or key in "$1".split(","):
+        sizes[key] = ""
+json.dump(req, open(path, "w"))
+PY
+SCRIPT
+    chmod +x "$HOME/editor.sh"
+    export EDITOR="$HOME/editor.sh"
+}
+
+# pull_dry_run <selection> runs pull with the images chosen, posting to Mastodon
+pull_dry_run() {
+    printf '%s\n' "$1" | IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull 5 --service flickr --no-remember --mastodon --size small --dry-run 2>&1
+}
+
+# expect_text <test name> <output> <expected text>
+expect_text() {
+    if echo "$2" | grep -qF -- "$3"; then
+        echo -e "${GREEN}✓ $1${NC}"
+    else
+        echo -e "${RED}✗ $1: missing: $3${NC}"
+        echo "$2"
+        exit 1
+    fi
+}
+
+echo -e "\n${YELLOW}Test: An image without sizes is left out${NC}"
+blank_sizes all
+output=$(pull_dry_run "1,2")
+expect_text "reported" "$output" "Warning: image 1 ("
+expect_text "reason" "$output" "has no image URL to post; left out"
+expect_text "second image posted" "$output" "    2. "
+if echo "$output" | grep -q "^    1\. "; then
+    echo -e "${RED}✗ image 1 should be left out:${NC}"
+    echo "$output"
+    exit 1
+fi
+echo -e "${GREEN}✓ image 1 not posted${NC}"
+
+echo -e "\n${YELLOW}Test: Nothing usable stops the post${NC}"
+output=$(pull_dry_run "1")
+status=$?
+expect_text "error" "$output" "None of the selected images has an image URL to post, so nothing was posted"
+if [ $status -eq 0 ] || echo "$output" | grep -qF "[DRY RUN] Would post"; then
+    echo -e "${RED}✗ should fail without posting (exit $status):${NC}"
+    echo "$output"
+    exit 1
+fi
+echo -e "${GREEN}✓ nothing posted${NC}"
+
+echo -e "\n${YELLOW}Test: A missing size falls back outside a terminal${NC}"
+blank_sizes small
+output=$(pull_dry_run "1")
+expect_text "warned" "$output" "has no small size; posting the next size available"
+expect_text "posted" "$output" "    1. "
+
+echo -e "\n${GREEN}All tests passed${NC}"
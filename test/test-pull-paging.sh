@@ -0,0 +1,70 @@
+#!/bin/bash
+
+# Test script for default.pull_page_size
+# Pages through a replayed Flickr photostream and picks images from several pages
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Pull Paging Test"
+echo "========================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/flickr-pull-photostream.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"pull_page_size": 2},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# pull_with <input>
+pull_with() {
+    printf "$1" | IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull 5 --service flickr --no-remember --post "Picks" --dry-run 2>&1
+}
+
+echo -e "\n${YELLOW}Test: Pages keep their numbering${NC}"
+output=$(pull_with '\n\n1,4,5\n')
+if echo "$output" | grep -qF "or press Enter for more (3-4 of 5)" &&
+    echo "$output" | grep -qF "or press Enter for more (5 of 5)" &&
+    echo "$output" | grep -qF "5) Photo 5" &&
+    echo "$output" | grep -qF "1. Photo 1 (" &&
+    echo "$output" | grep -qF "2. Photo 4 (" &&
+    echo "$output" | grep -qF "3. Photo 5 ("; then
+    echo -e "${GREEN}✓${NC}"
+    echo "$output"
+else
+    echo -e "${RED}✗ expected photos 1, 4 and 5 picked across pages, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Choosing on the first page stops listing${NC}"
+output=$(pull_with '2\n')
+if ! echo "$output" | grep -qF "3) Photo 3" && echo "$output" | grep -qF "1. Photo 2 ("; then
+    echo -e "${GREEN}✓${NC}"
+else
+    echo -e "${RED}✗ expected only the first page and photo 2, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Numbers not listed yet are rejected${NC}"
+output=$(pull_with '1,3\n')
+if echo "$output" | grep -qF "Invalid selection: 3" && echo "$output" | grep -qF "Images: 1"; then
+    echo -e "${GREEN}✓${NC}"
+else
+    echo -e "${RED}✗ expected 3 to be rejected, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
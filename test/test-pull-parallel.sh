@@ -0,0 +1,41 @@
+#!/bin/bash
+
+# Test script for parallel photo lookups in Flickr pulls
+# Replays info and size lookups recorded out of order and checks every photo
+# is filled in, in photostream order, and a photo whose sizes fail is dropped
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Pull Parallel Test"
+echo "=========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/flickr-pull-parallel.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+echo -e "\n${YELLOW}Test: Every photo filled in, in order${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull 5 --service flickr --no-remember --json 2>&1)
+order=$(echo "$output" | grep -o '"description": "Description of [0-9]*"' | grep -o '[0-9]*"$' | tr -d '"' | tr '\n' ' ')
+if [ "$order" = "1001 1002 1004 1005 " ] &&
+    echo "$output" | grep -qF '"large": "https://live.staticflickr.com/65535/1005_abc_b.jpg"'; then
+    echo -e "${GREEN}✓ photos $order${NC}"
+else
+    echo -e "${RED}✗ expected photos 1001 1002 1004 1005 with sizes, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,93 @@
+#!/bin/bash
+
+# Test script for per-target post text in pull
+# Edits the pull JSON with a script standing in for $EDITOR, and checks each
+# target gets its own text or falls back to post
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Pull Post Targets Test"
+echo "=============================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/flickr-pull-photostream.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# edit_with <post> <mastodon_post> <bluesky_post> makes $EDITOR fill in the
+# pull JSON's post fields, leaving out empty ones
+edit_with() {
+    cat > "$HOME/editor.sh" <<SCRIPT
+#!/bin/sh
+python3 - "\$1" <<'PY'
+import json, sys
+path = sys.argv[1]
+req = json.load(open(path))
+for key, value in (("post", "$1"), ("mastodon_post", "$2"), ("bluesky_post", "$3")):
+    if value:
+        req[key] = value
+json.dump(req, open(path, "w"))
+PY
+SCRIPT
+    chmod +x "$HOME/editor.sh"
+    export EDITOR="$HOME/editor.sh"
+}
+
+# pull_dry_run runs pull with one image chosen, posting to both targets
+pull_dry_run() {
+    printf '1\n' | IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull 5 --service flickr --no-remember --mastodon --bluesky --dry-run 2>&1
+}
+
+# expect_line <test name> <output> <expected line>
+expect_line() {
+    if echo "$2" | grep -qxF -- "$3"; then
+        echo -e "${GREEN}✓ $1${NC}"
+    else
+        echo -e "${RED}✗ $1: missing line: $3${NC}"
+        echo "$2"
+        exit 1
+    fi
+}
+
+echo -e "\n${YELLOW}Test: Bluesky gets its own text${NC}"
+edit_with "Fog rolling up the river this morning" "" "Fog"
+output=$(pull_dry_run)
+expect_line "shared text" "$output" "  Text: Fog rolling up the river this morning"
+expect_line "Bluesky text" "$output" "  Text for bluesky: Fog"
+if echo "$output" | grep -qF "Text for mastodon"; then
+    echo -e "${RED}✗ Mastodon should use post:${NC}"
+    echo "$output"
+    exit 1
+fi
+echo -e "${GREEN}✓ Mastodon falls back to post${NC}"
+
+echo -e "\n${YELLOW}Test: Per-target text without post${NC}"
+edit_with "" "Fog rolling up the river this morning" "Fog"
+output=$(pull_dry_run)
+expect_line "Mastodon text" "$output" "  Text for mastodon: Fog rolling up the river this morning"
+expect_line "Bluesky text" "$output" "  Text for bluesky: Fog"
+
+echo -e "\n${YELLOW}Test: A target without text stops the post${NC}"
+edit_with "" "" "Fog"
+output=$(pull_dry_run)
+expect_line "Mastodon has no text" "$output" "No post text for mastodon. Fill in 'post' or 'mastodon_post' in the JSON."
+if echo "$output" | grep -qF "[DRY RUN] Would post"; then
+    echo -e "${RED}✗ posted anyway:${NC}"
+    echo "$output"
+    exit 1
+fi
+echo -e "${GREEN}✓ nothing posted${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
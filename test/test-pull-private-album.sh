@@ -0,0 +1,65 @@
+#!/bin/bash
+
+# Test script for pulling from a private SmugMug album
+# Replays a private album and checks its images come back marked private
+# and are left out of the link output
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Private Album Pull Test"
+echo "==============================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURES="../tests/fixtures/http"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "smugmug": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret", "album_id": "abc123"},
+  "mastodon": {"instance_url": "https://news.example", "access_token": "token"}
+}
+JSON
+
+# check <test name> <expected exit status> <output> <status> <expected text>
+check() {
+    if [ $4 -eq $2 ] && echo "$3" | grep -qF -- "$5"; then
+        echo -e "${GREEN}✓ $1${NC}"
+    else
+        echo -e "${RED}✗ $1: expected exit $2 with \"$5\", got (exit $4):${NC}"
+        echo "$3"
+        exit 1
+    fi
+}
+
+echo -e "\n${YELLOW}Test: Private album images are listed${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/smugmug-pull-private.json" ../imgup pull 2 --service smugmug --album Family --no-remember --json 2>&1)
+status=$?
+check "Both images fetched" 0 "$output" $status '"title": "Beach"'
+check "Images marked private" 0 "$output" $status '"private": true'
+
+echo -e "\n${YELLOW}Test: Failed size lookups are reported${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/smugmug-pull-private-forbidden.json" ../imgup pull 2 --service smugmug --album Family --no-remember --json 2>&1)
+status=$?
+check "Error instead of an empty album" 1 "$output" $status "failed to get image sizes from album 'Family'"
+
+echo -e "\n${YELLOW}Test: No public links for private images${NC}"
+output=$(echo 1 | IMGUP_HTTP_FIXTURE="$FIXTURES/smugmug-pull-private-post.json" ../imgup pull 1 --service smugmug --album Family --no-remember \
+    --mastodon --post "Birthday" --format markdown 2>&1)
+status=$?
+check "Posted" 0 "$output" $status "Successfully posted 1 images"
+check "Warned" 0 "$output" $status "Birthday is in a private album"
+if echo "$output" | grep -qF "](https://photos.smugmug.com"; then
+    echo -e "${RED}✗ private image link in the output:${NC}"
+    echo "$output"
+    exit 1
+fi
+echo -e "${GREEN}✓ no markdown link emitted${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,74 @@
+#!/bin/bash
+
+# Test script for pull progress
+# Posts a replayed Flickr photostream to Mastodon and checks each step is
+# reported, that the output uses the configured templates, and that --json
+# only prints the images
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Pull Progress Test"
+echo "=========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/flickr-pull-post-progress.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "mastodon": {"instance_url": "https://news.example", "access_token": "token"},
+  "templates": {"markdown": "[%title%](%url%) %edit_url%"}
+}
+JSON
+
+# expect_line <output> <expected line>
+expect_line() {
+    if echo "$1" | grep -qxF -- "$2"; then
+        echo -e "${GREEN}✓ $2${NC}"
+    else
+        echo -e "${RED}✗ missing line: $2${NC}"
+        echo "$1"
+        exit 1
+    fi
+}
+
+echo -e "\n${YELLOW}Test: One line per step${NC}"
+output=$(printf '1,2\n' | IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull 2 --service flickr --no-remember --no-thumbnails --mastodon --post "Picks" 2>&1)
+status=$?
+if [ $status -ne 0 ]; then
+    echo -e "${RED}✗ pull failed (exit $status):${NC}"
+    echo "$output"
+    exit 1
+fi
+expect_line "$output" 'Uploading images to Mastodon...'
+expect_line "$output" '  Uploading Photo 1... done'
+expect_line "$output" '  Uploading Photo 2... failed: received HTML/text response instead of image from URL: https://live.staticflickr.com/65535/1002_abc_b.jpg'
+expect_line "$output" 'Posting to Mastodon... done'
+
+echo -e "\n${YELLOW}Test: Output uses the format's template${NC}"
+output=$(printf '1\n' | IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull 2 --service flickr --no-remember --no-thumbnails --mastodon --post "Picks" --format markdown 2>&1)
+expect_line "$output" '[Photo 1](https://www.flickr.com/photos/98806759@N00/1001) https://www.flickr.com/photos/upload/edit/?ids=1001'
+
+echo -e "\n${YELLOW}Test: --json doesn't post${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup pull 2 --service flickr --no-remember --json --mastodon --post "Picks" 2>&1)
+if echo "$output" | grep -qF "Posting to Mastodon"; then
+    echo -e "${RED}✗ --json posted:${NC}"
+    echo "$output"
+    exit 1
+fi
+echo "$output" | python3 -c 'import json, sys; json.load(sys.stdin)' || {
+    echo -e "${RED}✗ output isn't the JSON listing:${NC}"
+    echo "$output"
+    exit 1
+}
+echo -e "${GREEN}✓ printed the images only${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
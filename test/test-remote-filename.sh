@@ -0,0 +1,73 @@
+#!/bin/bash
+
+# Test script for --remote-filename and "remote_filename" in batch JSON
+# Replays a Flickr upload, and checks titles from filenames use the remote
+# name
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Remote Filename Test"
+echo "============================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="$(cd ../tests/fixtures && pwd)/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload-date-taken.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+cat > "$HOME/batch.json" <<JSON
+{
+  "images": [{"path": "$TEST_IMAGE", "remote_filename": "sunset_over_hood.jpg"}],
+  "common": {"service": "flickr"}
+}
+JSON
+
+echo -e "\n${YELLOW}Test: Single upload${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload --service flickr --remote-filename IMG_1234.HEIC "$TEST_IMAGE" 2>&1)
+if echo "$output" | grep -qxF "https://www.flickr.com/photos/98806759@N00/54321098765"; then
+    echo -e "${GREEN}✓ uploaded${NC}"
+else
+    echo -e "${RED}✗ upload failed:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Batch titles come from the remote filename${NC}"
+request=$(../imgup upload --json-file "$HOME/batch.json" --dry-run --print-request --title-from-filename 2>&1 >/dev/null)
+title=$(echo "$request" | python3 -c 'import json, sys; print(json.load(sys.stdin)["images"][0]["title"])')
+if [ "$title" = "Sunset Over Hood" ]; then
+    echo -e "${GREEN}✓ $title${NC}"
+else
+    echo -e "${RED}✗ expected \"Sunset Over Hood\", got:${NC}"
+    echo "$request"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Flag refused for batches${NC}"
+if output=$(../imgup upload --json-file "$HOME/batch.json" --remote-filename x.jpg 2>&1); then
+    echo -e "${RED}✗ accepted:${NC}"
+    echo "$output"
+    exit 1
+fi
+if echo "$output" | grep -qF 'set "remote_filename" on each image'; then
+    echo -e "${GREEN}✓ refused${NC}"
+else
+    echo -e "${RED}✗ unexpected error:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,84 @@
+#!/bin/bash
+
+# Test script for retry-social
+# Fails the Mastodon post after a replayed Flickr upload, so it's queued,
+# then checks the retry posts the same kind of text: none for --no-text,
+# and no photo URL for --no-social-url, and the same poll. A post that keeps
+# failing is given up on after its last attempt.
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Retry Social Test"
+echo "========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload-date-taken.json"
+URL="https://www.flickr.com/photos/98806759@N00/54321098765"
+
+# Fake credentials are enough, since every request is replayed. The
+# fixture has no Mastodon interactions, so every post fails.
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "mastodon": {"instance_url": "https://mastodon.example", "access_token": "token"}
+}
+JSON
+echo '{"interactions": []}' > "$HOME/offline.json"
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+# queue_and_retry <upload flags...> uploads with a failing Mastodon post,
+# then prints the text retry-social would post
+queue_and_retry() {
+    rm -f "$HOME/.config/imgupv2/uploads.db"*
+    IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --no-remember --mastodon "$@" >/dev/null 2>&1
+    IMGUP_DEBUG=1 IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup retry-social 2>&1 | grep "DEBUG: Retrying mastodon post"
+}
+
+echo -e "\n${YELLOW}Test: Post text and URL${NC}"
+output=$(queue_and_retry --post "Fog on the river")
+[ "$output" = "DEBUG: Retrying mastodon post with text \"Fog on the river\\n\\n$URL\"" ] || fail "unexpected retry" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: --no-text${NC}"
+output=$(queue_and_retry --no-text)
+[ "$output" = 'DEBUG: Retrying mastodon post with text ""' ] || fail "retry posts text" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: --no-social-url${NC}"
+output=$(queue_and_retry --post "Fog on the river" --no-social-url)
+[ "$output" = 'DEBUG: Retrying mastodon post with text "Fog on the river"' ] || fail "retry posts the URL" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: --poll${NC}"
+rm -f "$HOME/.config/imgupv2/uploads.db"*
+IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --no-remember --mastodon --post "Which bridge?" --poll "Hawthorne|Burnside" --poll-duration 2h >/dev/null 2>&1
+output=$(IMGUP_DEBUG=1 IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup retry-social 2>&1 | grep "DEBUG: Retrying poll")
+[ "$output" = 'DEBUG: Retrying poll ["Hawthorne" "Burnside"] open for 2h0m0s' ] || fail "retry drops the poll" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: Given up after 5 attempts${NC}"
+queue_and_retry --post "Fog on the river" >/dev/null
+for attempt in 2 3 4; do
+    IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup retry-social >/dev/null 2>&1
+done
+output=$(IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup retry-social 2>&1) && fail "retry-social succeeded" "$output"
+echo "$output" | grep -qF "Error: gave up on mastodon post for $URL after 5 attempts" || fail "expected the post to be given up on" "$output"
+output=$(IMGUP_HTTP_FIXTURE="$HOME/offline.json" ../imgup retry-social 2>&1) || fail "retry-social failed" "$output"
+[ "$output" = "No queued social posts." ] || fail "the post is still queued" "$output"
+echo -e "${GREEN}✓ given up and dropped from the queue${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,77 @@
+#!/bin/bash
+
+# Test script for the reStructuredText format
+# Checks that the rst image directive keeps its newlines and indented
+# options, from the default template, a config template and a template file
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 reStructuredText Format Test"
+echo "===================================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload-sizes.json"
+PAGE="https://www.flickr.com/photos/98806759@N00/54321098765"
+IMAGE="https://live.staticflickr.com/65535/54321098765_abcdef1234_b.jpg"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "templates": {"rst-figure": ".. figure:: %image_url%\\n   :alt: %alt%\\n\\n   %title%"}
+}
+JSON
+printf '.. image:: %%image_url%%\n   :alt: %%alt|title%%\n' > "$HOME/image.rst"
+
+# expect_output <description> <expected output> [upload flags...]
+expect_output() {
+    name=$1
+    expected=$2
+    shift 2
+    echo -e "\n${YELLOW}Test: $name${NC}"
+    output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember "$@" 2>/dev/null)
+    if [ "$output" = "$expected" ]; then
+        echo -e "${GREEN}✓${NC}"
+        echo "$output"
+    else
+        echo -e "${RED}✗ expected:${NC}"
+        echo "$expected"
+        echo -e "${RED}got:${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+expect_output "rst with alt text" ".. image:: $IMAGE
+   :alt: Red barn
+   :target: $PAGE" --format rst --title Barn --alt "Red barn"
+expect_output "rst falls back to the description" ".. image:: $IMAGE
+   :alt: Barn at dusk
+   :target: $PAGE" --format rst --description "Barn at dusk"
+expect_output "Config template keeps its newlines" ".. figure:: $IMAGE
+   :alt: Red barn
+
+   Barn" --format rst-figure --title Barn --alt "Red barn"
+expect_output "Template file keeps its newlines" ".. image:: $IMAGE
+   :alt: Barn" --template-file "$HOME/image.rst" --title Barn
+
+echo -e "\n${YELLOW}Test: rst is offered when completing --format${NC}"
+output=$(../imgup __complete upload --format r 2>/dev/null)
+if echo "$output" | grep -qx "rst"; then
+    echo -e "${GREEN}✓ rst${NC}"
+else
+    echo -e "${RED}✗ expected rst among the completions, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,60 @@
+#!/bin/bash
+
+# Test script for --sanitize-filename
+# Uploads copies of the test image under awkward names and checks what
+# %filename% renders as, with and without sanitizing
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Sanitize Filename Test"
+echo "=============================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload-sizes.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "templates": {"name": "%filename%"}
+}
+JSON
+
+# expect_filename <filename> <expected %filename%> [upload flags...]
+expect_filename() {
+    name=$1
+    expected=$2
+    shift 2
+    echo -e "\n${YELLOW}Test: \"$name\"${NC}"
+    cp "$TEST_IMAGE" "$HOME/$name"
+    output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$HOME/$name" --service flickr --force --no-remember --format name "$@" 2>/dev/null)
+    if [ "$output" = "$expected" ]; then
+        echo -e "${GREEN}✓ $output${NC}"
+    else
+        echo -e "${RED}✗ expected \"$expected\", got:${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+expect_filename "IMG_1234 (1).jpeg" "IMG_1234" --sanitize-filename
+expect_filename "Beach  at   dusk copy 2.jpeg" "Beach at dusk" --sanitize-filename
+expect_filename "Barn (edited) (2).jpeg" "Barn" --sanitize-filename
+expect_filename "Sunset_copy.jpeg" "Sunset" --sanitize-filename
+expect_filename "(1).jpeg" "(1)" --sanitize-filename
+expect_filename "IMG_1234 (1).jpeg" "IMG_1234 (1)"
+
+# The config default sanitizes without the flag
+../imgup config set default.sanitize_filename true >/dev/null || exit 1
+expect_filename "IMG_5678 (3).jpeg" "IMG_5678"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,64 @@
+#!/bin/bash
+
+# Test script for --skip-existing-in-album
+# Replays Flickr searches and photoset listings and checks that matching titles and filenames skip the upload
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Skip Existing Test"
+echo "=========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-album-match.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# expect_skip <test name> <expected URL> <expected note> <args...>
+expect_skip() {
+    name=$1 url=$2 note=$3
+    shift 3
+    echo -e "\n${YELLOW}Test: $name${NC}"
+    output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --no-remember --skip-existing-in-album "$@" 2>&1)
+    status=$?
+    if [ $status -eq 0 ] && echo "$output" | grep -qF -- "$url" && echo "$output" | grep -qF -- "$note"; then
+        echo -e "${GREEN}✓ $output${NC}"
+    else
+        echo -e "${RED}✗ expected a skip to $url, got (exit $status):${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+expect_skip "Matching title, ignoring case" "https://www.flickr.com/photos/98806759@N00/2002" \
+    'a photo with the title "Harbor at dusk" is already on flickr' --title "Harbor at dusk"
+
+expect_skip "Matching title in the target photoset" "https://www.flickr.com/photos/98806759@N00/2005" \
+    'a photo with the title "Harbor at dusk" is already on flickr' --title "Harbor at dusk" --album Blog
+
+../imgup config set default.skip_existing_match filename > /dev/null
+expect_skip "Matching filename" "https://www.flickr.com/photos/98806759@N00/2003" \
+    'a photo with the filename "test_metadata.jpeg" is already on flickr'
+
+echo -e "\n${YELLOW}Test: Unknown match field${NC}"
+if output=$(../imgup config set default.skip_existing_match caption 2>&1); then
+    echo -e "${RED}✗ accepted an unknown field:${NC}"
+    echo "$output"
+    exit 1
+fi
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
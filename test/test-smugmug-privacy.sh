@@ -0,0 +1,61 @@
+#!/bin/bash
+
+# Test script for --smugmug-privacy
+# Replays SmugMug uploads into a private and an unlisted album, and checks a
+# private image is only uploaded to a private album, a public one warns in an
+# album that isn't public, and an unlisted one doesn't look at the album
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 SmugMug Privacy Test"
+echo "============================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURES="../tests/fixtures/http"
+URL="https://example.smugmug.com/Travel/n-abc123/i-XyZ12ab"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "smugmug": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret", "album_id": "abc123"}
+}
+JSON
+
+fail() {
+    echo -e "${RED}✗ $1${NC}"
+    echo "$2"
+    exit 1
+}
+
+echo -e "\n${YELLOW}Test: Private image in a private album${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/smugmug-upload-private.json" ../imgup upload "$TEST_IMAGE" --service smugmug --smugmug-privacy private --no-remember 2>&1) || fail "upload failed" "$output"
+[ "$output" = "$URL" ] || fail "expected only $URL" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: Private image in an unlisted album${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/smugmug-upload-unlisted-album.json" ../imgup upload "$TEST_IMAGE" --service smugmug --smugmug-privacy private --no-remember 2>&1) && fail "uploaded into an unlisted album" "$output"
+echo "$output" | grep -qF 'album "Travel" is unlisted' || fail "expected the album's privacy in the error" "$output"
+echo "$output" | grep -qF "$URL" && fail "expected nothing to be uploaded" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${YELLOW}Test: Public image in an unlisted album${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/smugmug-upload-unlisted-album.json" ../imgup upload "$TEST_IMAGE" --service smugmug --smugmug-privacy public --no-remember 2>&1) || fail "upload failed" "$output"
+echo "$output" | grep -qF "$URL" || fail "expected $URL" "$output"
+echo "$output" | grep -qF "only as visible as the album" || fail "expected a warning about the album" "$output"
+echo -e "${GREEN}✓ warned${NC}"
+
+echo -e "\n${YELLOW}Test: Unlisted image in any album${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/smugmug-upload-unlisted-album.json" ../imgup upload "$TEST_IMAGE" --service smugmug --smugmug-privacy unlisted --no-remember 2>&1) || fail "upload failed" "$output"
+[ "$output" = "$URL" ] || fail "expected only $URL" "$output"
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
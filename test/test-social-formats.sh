@@ -0,0 +1,43 @@
+#!/bin/bash
+
+# Test script for social format checks
+# Replays a Flickr photo whose sizes are TIFF files, and checks Mastodon and
+# Bluesky posts stop with a clear error before fetching the image
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Social Format Test"
+echo "=========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURE="../tests/fixtures/http/flickr-sizes-tiff.json"
+PHOTO="https://www.flickr.com/photos/pdxmph/54321098765"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "mastodon": {"instance_url": "https://mastodon.example", "access_token": "token"},
+  "bluesky": {"handle": "me.bsky.social", "app_password": "password", "pds": "https://bsky.social"}
+}
+JSON
+
+echo -e "\n${YELLOW}Test: TIFF sizes${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup post "$PHOTO" --mastodon --bluesky --post "From the archive" </dev/null 2>&1)
+for target in mastodon bluesky; do
+    if ! echo "$output" | grep -qF "$target doesn't support TIFF images"; then
+        echo -e "${RED}✗ expected $target to refuse the TIFF:${NC}"
+        echo "$output"
+        exit 1
+    fi
+done
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
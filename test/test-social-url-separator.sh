@@ -0,0 +1,59 @@
+#!/bin/bash
+
+# Test script for default.social_url_separator
+# Checks the text between the post and the photo link in dry-run posts
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Social URL Separator Test"
+echo "================================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+PAGE="https://www.flickr.com/photos/username/54321098765"
+
+# A dry run posts nothing, so fake credentials are enough
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "mastodon": {"instance_url": "https://news.example", "access_token": "token"}
+}
+JSON
+
+# expect <test name> <expected text>
+expect() {
+    output=$(../imgup post "$PAGE" --mastodon --dry-run --post "Fog again" 2>&1)
+    if echo "$output" | grep -qF -- "$2"; then
+        echo -e "${GREEN}✓ $1${NC}"
+    else
+        echo -e "${RED}✗ $1: expected \"$2\", got:${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+echo -e "\n${YELLOW}Test: Link on its own line by default${NC}"
+output=$(../imgup post "$PAGE" --mastodon --dry-run --post "Fog again" 2>&1)
+if echo "$output" | grep -A2 -xF "  Text: Fog again" | tail -2 | tr '\n' '|' | grep -qxF "|$PAGE|"; then
+    echo -e "${GREEN}✓ Blank line, then the link${NC}"
+else
+    echo -e "${RED}✗ expected a blank line and the link after the text, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Inline link${NC}"
+../imgup config set default.social_url_separator ' — ' > /dev/null
+expect "Inline separator" "Text: Fog again — $PAGE"
+
+echo -e "\n${YELLOW}Test: Escaped newline${NC}"
+../imgup config set default.social_url_separator '\nvia ' > /dev/null
+expect "Newline separator" "via $PAGE"
+
+echo -e "\n${GREEN}All tests passed${NC}"
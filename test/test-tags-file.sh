@@ -0,0 +1,81 @@
+#!/bin/bash
+
+# Test script for --tags-file
+# Checks tags from a file are merged with --tags, without repeats, for
+# single uploads and batches
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Tags File Test"
+echo "======================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload-date-taken.json"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "mastodon": {"instance_url": "https://news.example", "access_token": "token"}
+}
+JSON
+
+TAGS="$HOME/tags.txt"
+cat > "$TAGS" <<TXT
+# House tags
+portland, oregon
+
+  Film
+oregon
+TXT
+
+echo -e "\n${YELLOW}Test: Single upload${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --no-remember --dry-run --mastodon --post "Harbor" \
+    --tags film,harbor --tags-file "$TAGS" 2>&1)
+if echo "$output" | grep -qxF "  Hashtags: #film #harbor #portland #oregon"; then
+    echo -e "${GREEN}✓ Merged in order, without repeats${NC}"
+else
+    echo -e "${RED}✗ expected #film #harbor #portland #oregon, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Batch${NC}"
+BATCH="$HOME/batch.json"
+cat > "$BATCH" <<JSON
+{"images": [{"path": "$TEST_IMAGE"}], "common": {"service": "flickr", "tags": ["harbor", "Oregon"]}}
+JSON
+request=$(../imgup upload --json-file "$BATCH" --dry-run --tags-file "$TAGS" --print-request 2>&1 >/dev/null)
+tags=$(echo "$request" | tr -d ' \n' | grep -o '"tags":\[[^]]*\]')
+if [ "$tags" = '"tags":["harbor","Oregon","portland","Film"]' ]; then
+    echo -e "${GREEN}✓ $tags${NC}"
+else
+    echo -e "${RED}✗ unexpected batch tags \"$tags\" in:${NC}"
+    echo "$request"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Missing file${NC}"
+if output=$(../imgup upload "$TEST_IMAGE" --tags-file "$HOME/missing.txt" 2>&1); then
+    echo -e "${RED}✗ accepted a missing tags file:${NC}"
+    echo "$output"
+    exit 1
+fi
+if echo "$output" | grep -qF "failed to read tags"; then
+    echo -e "${GREEN}✓ $output${NC}"
+else
+    echo -e "${RED}✗ unexpected error:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,68 @@
+#!/bin/bash
+
+# Test script for --template-file and @file template references
+# Renders a multiline template file against a replayed Flickr upload
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Template File Test"
+echo "=========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURES="../tests/fixtures/http"
+TEMPLATE="$(cd ../tests/fixtures/templates && pwd)/figure.html"
+
+EXPECTED='<figure>
+  <a href="https://www.flickr.com/photos/98806759@N00/54321098765">Photo 54321098765</a>
+  <figcaption>Barn</figcaption>
+</figure>'
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "templates": {"figure": "@$TEMPLATE"}
+}
+JSON
+
+# expect_render <test name> [upload flags...]
+expect_render() {
+    name=$1
+    shift
+    echo -e "\n${YELLOW}Test: $name${NC}"
+    output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-setmeta-unavailable.json" ../imgup upload "$TEST_IMAGE" --service flickr --force --no-remember --title Barn "$@" 2>/dev/null)
+    if [ "$output" = "$EXPECTED" ]; then
+        echo -e "${GREEN}✓${NC}"
+        echo "$output"
+    else
+        echo -e "${RED}✗ expected:${NC}"
+        echo "$EXPECTED"
+        echo -e "${RED}got:${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+expect_render "--template-file" --template-file "$TEMPLATE"
+expect_render "@file reference in config" --format figure
+
+echo -e "\n${YELLOW}Test: Missing template file${NC}"
+output=$(../imgup upload "$TEST_IMAGE" --service flickr --no-remember --template-file /nonexistent/template.html 2>&1)
+if [ $? -ne 0 ] && echo "$output" | grep -qF "failed to read template"; then
+    echo -e "${GREEN}✓ $output${NC}"
+else
+    echo -e "${RED}✗ expected a read error before uploading, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,54 @@
+#!/bin/bash
+
+# Test script for update-metadata
+# Replays Flickr and SmugMug responses and checks each service's update path
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Update Metadata Test"
+echo "============================"
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURES="../tests/fixtures/http"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "smugmug": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret", "album_id": "abc123"}
+}
+JSON
+
+# expect <test name> <expected exit status> <expected output> <cassette> <args...>
+expect() {
+    name=$1 want=$2 message=$3 cassette=$4
+    shift 4
+    echo -e "\n${YELLOW}Test: $name${NC}"
+    output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/$cassette" ../imgup update-metadata "$@" 2>&1)
+    status=$?
+    if [ $status -eq $want ] && echo "$output" | grep -qF -- "$message"; then
+        echo -e "${GREEN}✓ $output${NC}"
+    else
+        echo -e "${RED}✗ expected exit $want with \"$message\", got (exit $status):${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+expect "Nothing to change" 1 "nothing to do" flickr-update-metadata.json \
+    https://www.flickr.com/photos/username/54321098765
+expect "Flickr title and tags" 0 "Updated flickr photo 54321098765" flickr-update-metadata.json \
+    https://www.flickr.com/photos/username/54321098765 --title "Sunset" --tags sunset
+expect "SmugMug caption and keywords" 0 "Updated smugmug photo XyZ12ab" smugmug-update-metadata.json \
+    https://example.smugmug.com/Travel/n-abc123/i-XyZ12ab --description "From the ferry" --tags ferry
+expect "SmugMug image gone" 1 "API returned status 404" smugmug-update-metadata-missing.json \
+    https://example.smugmug.com/Travel/n-abc123/i-Gone123 --title "Gone"
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,78 @@
+#!/bin/bash
+
+# Test script for upload --album and flickr.upload_album
+# Replays a Flickr upload and checks the photo is added to the named photoset,
+# which config show lists and the last-used state remembers
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Upload Album Test"
+echo "========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURE="../tests/fixtures/http/flickr-upload-album.json"
+URL="https://www.flickr.com/photos/98806759@N00/54321098765"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+# expect_added <test name> <args...>
+expect_added() {
+    echo -e "\n${YELLOW}Test: $1${NC}"
+    shift
+    output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --no-remember "$@" 2>&1)
+    status=$?
+    # Every request must be replayed, including the photosets.addPhoto call
+    if [ $status -eq 0 ] && [ "$output" = "$URL" ]; then
+        echo -e "${GREEN}✓ $output${NC}"
+    else
+        echo -e "${RED}✗ expected a clean upload to $URL, got (exit $status):${NC}"
+        echo "$output"
+        exit 1
+    fi
+}
+
+expect_added "--album, ignoring case" --album blog
+
+../imgup config set flickr.upload_album Blog > /dev/null
+expect_added "flickr.upload_album"
+
+echo -e "\n${YELLOW}Test: config show and the remembered album${NC}"
+output=$(../imgup config show)
+echo "$output" | grep -qF "Upload Album: Blog" || { echo -e "${RED}✗ config show omits flickr.upload_album:${NC}"; echo "$output"; exit 1; }
+IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr > /dev/null 2>&1
+album=$(python3 -c 'import json, sys; print(json.load(open(sys.argv[1]))["albums"]["flickr"])' "$HOME/.config/imgupv2/state.json" 2>&1)
+if [ "$album" != "Blog" ]; then
+    echo -e "${RED}✗ expected Blog to be remembered, got:${NC}"
+    echo "$album"
+    exit 1
+fi
+echo -e "${GREEN}✓ shown and remembered${NC}"
+
+echo -e "\n${YELLOW}Test: Unknown album${NC}"
+if output=$(IMGUP_HTTP_FIXTURE="$FIXTURE" ../imgup upload "$TEST_IMAGE" --service flickr --no-remember --album Blogs 2>&1); then
+    echo -e "${RED}✗ uploaded to an unknown album:${NC}"
+    echo "$output"
+    exit 1
+fi
+if ! echo "$output" | grep -qF "photoset 'Blogs' not found. Did you mean one of: Blog"; then
+    echo -e "${RED}✗ unexpected error:${NC}"
+    echo "$output"
+    exit 1
+fi
+echo -e "${GREEN}✓ $output${NC}"
+
+echo -e "\n${GREEN}All tests passed${NC}"
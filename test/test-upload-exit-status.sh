@@ -0,0 +1,81 @@
+#!/bin/bash
+
+# Test script for upload exit statuses
+# Checks uploads that fail in transit, or with a server error or rate limit,
+# exit with 3, so the GUI can retry them, and other failures exit with 1
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Upload Exit Status Test"
+echo "==============================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURES="../tests/fixtures/http"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+# The same, with SmugMug set up too
+mkdir -p "$HOME/smugmug/.config/imgupv2"
+cat > "$HOME/smugmug/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "smugmug": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret", "album_id": "abc123"}
+}
+JSON
+
+# expect_status <test name> <expected exit status> <output> <status>
+expect_status() {
+    if [ $4 -eq $2 ]; then
+        echo -e "${GREEN}✓ $1 (exit $4)${NC}"
+    else
+        echo -e "${RED}✗ $1: expected exit $2, got $4:${NC}"
+        echo "$3"
+        exit 1
+    fi
+}
+
+echo -e "\n${YELLOW}Test: Single upload${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-upload-unavailable.json" ../imgup upload "$TEST_IMAGE" --service flickr --no-remember 2>&1)
+expect_status "Flickr unavailable" 3 "$output" $?
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-upload-bad-filetype.json" ../imgup upload "$TEST_IMAGE" --service flickr --no-remember 2>&1)
+expect_status "Rejected file type" 1 "$output" $?
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-upload-unavailable.json" ../imgup upload "$TEST_IMAGE" --service smugmug --no-remember 2>&1)
+expect_status "Not authenticated" 1 "$output" $?
+
+echo -e "\n${YELLOW}Test: HTTP status${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-upload-503.json" ../imgup upload "$TEST_IMAGE" --service flickr --no-remember 2>&1)
+expect_status "Flickr 503" 3 "$output" $?
+output=$(HOME="$HOME/smugmug" IMGUP_HTTP_FIXTURE="$FIXTURES/smugmug-upload-429.json" ../imgup upload "$TEST_IMAGE" --service smugmug --no-remember 2>&1)
+expect_status "SmugMug 429" 3 "$output" $?
+output=$(HOME="$HOME/smugmug" IMGUP_HTTP_FIXTURE="$FIXTURES/smugmug-upload-400.json" ../imgup upload "$TEST_IMAGE" --service smugmug --no-remember 2>&1)
+expect_status "SmugMug 400" 1 "$output" $?
+
+echo -e "\n${YELLOW}Test: Batch${NC}"
+batch="{\"images\": [{\"path\": \"$TEST_IMAGE\"}], \"common\": {\"service\": \"flickr\"}}"
+output=$(echo "$batch" | IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-upload-unavailable.json" ../imgup upload --json --no-remember 2>/dev/null)
+status=$?
+expect_status "Every upload failed in transit" 3 "$output" $status
+if echo "$output" | grep -qF '"retryable": true'; then
+    echo -e "${GREEN}✓ Marked retryable${NC}"
+else
+    echo -e "${RED}✗ expected \"retryable\": true in:${NC}"
+    echo "$output"
+    exit 1
+fi
+output=$(echo "$batch" | IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-upload-bad-filetype.json" ../imgup upload --json --no-remember 2>/dev/null)
+expect_status "Rejected upload reported in the JSON" 0 "$output" $?
+
+echo -e "\n${GREEN}All tests passed${NC}"
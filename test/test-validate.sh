@@ -0,0 +1,65 @@
+#!/bin/bash
+
+# Test script for the pre-upload corruption check
+# Uploads a truncated JPEG against replayed Flickr responses
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Image Validation Test"
+echo "============================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+TRUNCATED_IMAGE="../tests/fixtures/truncated.jpeg"
+TEST_IMAGE="../tests/fixtures/test_metadata.jpeg"
+FIXTURES="../tests/fixtures/http"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"}
+}
+JSON
+
+upload() {
+    IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-setmeta-unavailable.json" ../imgup upload "$@" --service flickr --force --no-remember 2>&1
+}
+
+echo -e "\n${YELLOW}Test: Truncated JPEG is rejected${NC}"
+output=$(upload "$TRUNCATED_IMAGE")
+if [ $? -ne 0 ] && echo "$output" | grep -qF "image is corrupt or truncated"; then
+    echo -e "${GREEN}✓ $output${NC}"
+else
+    echo -e "${RED}✗ expected a corruption error, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: --validate=false uploads it anyway${NC}"
+output=$(upload "$TRUNCATED_IMAGE" --validate=false)
+if [ $? -eq 0 ] && echo "$output" | grep -qF "54321098765"; then
+    echo -e "${GREEN}✓ $output${NC}"
+else
+    echo -e "${RED}✗ expected the upload to go ahead, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Intact JPEG passes${NC}"
+output=$(upload "$TEST_IMAGE")
+if [ $? -eq 0 ] && echo "$output" | grep -qF "54321098765"; then
+    echo -e "${GREEN}✓ $output${NC}"
+else
+    echo -e "${RED}✗ expected the upload to succeed, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"
@@ -0,0 +1,78 @@
+#!/bin/bash
+
+# Test script for --verify-upload
+# Replays SmugMug uploads whose stored checksum matches the file, doesn't, or
+# isn't reported yet, and checks only the last two get a warning
+# Run from the test directory after building ../imgup
+
+echo "imgupv2 Verify Upload Test"
+echo "=========================="
+
+# Colors for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+FIXTURES="../tests/fixtures/http"
+
+# Fake credentials are enough, since every request is replayed
+export HOME=$(mktemp -d)
+trap 'rm -rf "$HOME"' EXIT
+mkdir -p "$HOME/.config/imgupv2"
+cat > "$HOME/.config/imgupv2/config.json" <<JSON
+{
+  "default": {"duplicate_check": false},
+  "flickr": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret"},
+  "smugmug": {"consumer_key": "key", "consumer_secret": "secret", "access_token": "token", "access_secret": "secret", "album_id": "abc123"}
+}
+JSON
+for name in a b c; do
+    cp ../tests/fixtures/test_metadata.jpeg "$HOME/$name.jpeg"
+done
+
+echo -e "\n${YELLOW}Test: SmugMug checksums are compared${NC}"
+# SmugMug reports the file's MD5 for a, another MD5 for b and none yet for c
+output=$(cat <<JSON | IMGUP_HTTP_FIXTURE="$FIXTURES/smugmug-upload-verify.json" ../imgup upload --json --no-remember 2>/dev/null
+{
+  "images": [{"path": "$HOME/a.jpeg"}, {"path": "$HOME/b.jpeg"}, {"path": "$HOME/c.jpeg"}],
+  "common": {"service": "smugmug"},
+  "options": {"verify_upload": true}
+}
+JSON
+)
+warnings=$(echo "$output" | python3 -c 'import json, sys; print("\n".join("; ".join(u.get("warnings") or ["-"]) for u in json.load(sys.stdin)["uploads"]))')
+expected="-
+SmugMug's copy doesn't match the uploaded file (MD5 0123456789abcdef0123456789abcdef, expected bed02247f9cc3756a8e08288fc3b2a2e); it may have been transcoded or corrupted
+Couldn't verify the upload: SmugMug hasn't reported a checksum for it yet"
+if [ "$warnings" = "$expected" ]; then
+    echo -e "${GREEN}✓ match, mismatch, not reported yet${NC}"
+else
+    echo -e "${RED}✗ expected warnings:${NC}"
+    echo "$expected"
+    echo -e "${RED}got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: A single upload that matches prints no warning${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/smugmug-upload-verify.json" ../imgup upload "$HOME/a.jpeg" --service smugmug --verify-upload --no-remember 2>&1)
+if [ $? -eq 0 ] && [ "$output" = "https://example.smugmug.com/Travel/n-abc123/i-XyZ12ab" ]; then
+    echo -e "${GREEN}✓ no warning${NC}"
+else
+    echo -e "${RED}✗ expected only the URL, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${YELLOW}Test: Flickr uploads aren't verified${NC}"
+output=$(IMGUP_HTTP_FIXTURE="$FIXTURES/flickr-upload-date-taken.json" ../imgup upload "$HOME/a.jpeg" --service flickr --verify-upload --no-remember 2>&1)
+if echo "$output" | grep -qF -- "--verify-upload only applies to SmugMug"; then
+    echo -e "${GREEN}✓ warns that it only applies to SmugMug${NC}"
+else
+    echo -e "${RED}✗ expected a warning, got:${NC}"
+    echo "$output"
+    exit 1
+fi
+
+echo -e "\n${GREEN}All tests passed${NC}"